@@ -0,0 +1,11 @@
+data "tailscale_device" "example" {
+  hostname = "device.example.com"
+}
+
+# Run with `terraform apply -invoke=action.tailscale_device_authorize.example`.
+action "tailscale_device_authorize" "example" {
+  config {
+    device_id  = data.tailscale_device.example.node_id
+    authorized = true
+  }
+}
@@ -0,0 +1,10 @@
+data "tailscale_device" "example" {
+  hostname = "ephemeral-runner"
+}
+
+# Run with `terraform apply -invoke=action.tailscale_device_delete.example`.
+action "tailscale_device_delete" "example" {
+  config {
+    device_id = data.tailscale_device.example.node_id
+  }
+}
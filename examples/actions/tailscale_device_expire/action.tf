@@ -0,0 +1,20 @@
+data "tailscale_device" "example" {
+  hostname = "build-runner"
+}
+
+resource "terraform_data" "runner" {
+  input = data.tailscale_device.example.node_id
+
+  lifecycle {
+    action_trigger {
+      events  = [before_destroy]
+      actions = [action.tailscale_device_expire.example]
+    }
+  }
+}
+
+action "tailscale_device_expire" "example" {
+  config {
+    device_id = data.tailscale_device.example.node_id
+  }
+}
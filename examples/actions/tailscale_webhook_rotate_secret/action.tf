@@ -0,0 +1,12 @@
+resource "tailscale_webhook" "example" {
+  endpoint_url  = "https://example.com/webhook/endpoint"
+  provider_type = "slack"
+  subscriptions = ["nodeCreated", "userDeleted"]
+}
+
+# Run with `terraform apply -invoke=action.tailscale_webhook_rotate_secret.example`.
+action "tailscale_webhook_rotate_secret" "example" {
+  config {
+    id = tailscale_webhook.example.id
+  }
+}
@@ -0,0 +1,18 @@
+resource "tailscale_webhook" "example" {
+  endpoint_url  = "https://example.com/webhook/endpoint"
+  provider_type = "slack"
+  subscriptions = ["nodeCreated", "userDeleted"]
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create, after_update]
+      actions = [action.tailscale_webhook_test_event.example]
+    }
+  }
+}
+
+action "tailscale_webhook_test_event" "example" {
+  config {
+    id = tailscale_webhook.example.id
+  }
+}
@@ -1 +1,5 @@
 data "tailscale_acl" "example" {}
+
+output "engineering_members" {
+  value = data.tailscale_acl.example.groups["group:engineering"]
+}
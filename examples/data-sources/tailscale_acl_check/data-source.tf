@@ -0,0 +1,12 @@
+data "tailscale_acl_check" "web_https" {
+  src = "alice@example.com"
+  dst = "tag:web:443"
+}
+
+# Check a policy file before it is applied.
+data "tailscale_acl_check" "dns" {
+  policy = tailscale_acl.example.acl
+  src    = "tag:web"
+  dst    = "10.0.0.53:53"
+  proto  = "udp"
+}
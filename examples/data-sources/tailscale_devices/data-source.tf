@@ -10,3 +10,26 @@ data "tailscale_devices" "sample_devices" {
     values = ["tag:server", "tag:test"]
   }
 }
+
+data "tailscale_devices" "stale_web_servers" {
+  hostname_regex   = "^web-[0-9]+$"
+  tags_all         = ["tag:web", "tag:prod"]
+  tags_none        = ["tag:canary"]
+  os               = ["linux"]
+  client_version   = "< 1.60"
+  last_seen_before = "2026-01-01T00:00:00Z"
+  authorized       = true
+  sort_by          = "last_seen"
+  limit            = 10
+}
+
+resource "tailscale_device_tag" "outdated" {
+  for_each = toset(data.tailscale_devices.stale_web_servers.ids)
+
+  device_id = each.key
+  tag       = "tag:outdated"
+}
+
+output "stale_web_server_addresses" {
+  value = data.tailscale_devices.stale_web_servers.addresses_by_name
+}
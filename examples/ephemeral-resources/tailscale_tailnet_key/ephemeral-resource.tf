@@ -0,0 +1,16 @@
+ephemeral "tailscale_tailnet_key" "bootstrap" {
+  reusable      = false
+  ephemeral     = true
+  preauthorized = true
+  expiry        = 3600
+  tags          = ["tag:server"]
+  description   = "Bootstrap key"
+}
+
+# Ephemeral values can only be referenced from other ephemeral contexts, such
+# as write-only arguments, so the key never appears in the plan or state.
+resource "aws_secretsmanager_secret_version" "bootstrap" {
+  secret_id                = aws_secretsmanager_secret.bootstrap.id
+  secret_string_wo         = ephemeral.tailscale_tailnet_key.bootstrap.key
+  secret_string_wo_version = 1
+}
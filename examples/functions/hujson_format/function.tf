@@ -0,0 +1,3 @@
+resource "tailscale_acl" "as_hujson" {
+  acl = provider::tailscale::hujson_format(file("${path.module}/policy.hujson"))
+}
@@ -0,0 +1,7 @@
+locals {
+  policy = jsondecode(provider::tailscale::hujson_to_json(file("${path.module}/policy.hujson")))
+}
+
+output "groups" {
+  value = local.policy.groups
+}
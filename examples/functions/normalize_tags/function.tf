@@ -0,0 +1,5 @@
+resource "tailscale_device_tags" "sample_tags" {
+  device_id = data.tailscale_device.sample_device.node_id
+  # ["tag:server", "tag:web"]
+  tags = provider::tailscale::normalize_tags(["web", "tag:server", "tag:web"])
+}
@@ -0,0 +1,4 @@
+output "site_7_via" {
+  # fd7a:115c:a1e0:b1a:0:7:a01:100/120
+  value = provider::tailscale::via6(7, "10.1.1.0/24")
+}
@@ -0,0 +1,13 @@
+locals {
+  decoded = provider::tailscale::via6_decode("fd7a:115c:a1e0:b1a:0:7:a01:100/120")
+}
+
+output "site" {
+  # 7
+  value = local.decoded.site
+}
+
+output "cidr" {
+  # 10.1.1.0/24
+  value = local.decoded.cidr
+}
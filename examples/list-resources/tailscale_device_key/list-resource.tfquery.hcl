@@ -0,0 +1,7 @@
+list "tailscale_device_key" "example" {
+  provider = tailscale
+
+  config {
+    name_prefix = "web"
+  }
+}
@@ -0,0 +1,7 @@
+list "tailscale_device_tags" "example" {
+  provider = tailscale
+
+  config {
+    name_prefix = "web"
+  }
+}
@@ -0,0 +1,10 @@
+list "tailscale_federated_identity" "example" {
+  provider = tailscale
+
+  config {
+    filter {
+      name   = "tags"
+      values = ["tag:ci"]
+    }
+  }
+}
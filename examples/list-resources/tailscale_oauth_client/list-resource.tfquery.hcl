@@ -0,0 +1,10 @@
+list "tailscale_oauth_client" "example" {
+  provider = tailscale
+
+  config {
+    filter {
+      name   = "tags"
+      values = ["tag:ci"]
+    }
+  }
+}
@@ -0,0 +1,7 @@
+list "tailscale_service" "example" {
+  provider = tailscale
+
+  config {
+    name_prefix = "svc:web-"
+  }
+}
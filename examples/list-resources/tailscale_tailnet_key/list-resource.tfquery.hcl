@@ -0,0 +1,10 @@
+list "tailscale_tailnet_key" "example" {
+  provider = tailscale
+
+  config {
+    filter {
+      name   = "tags"
+      values = ["tag:ci"]
+    }
+  }
+}
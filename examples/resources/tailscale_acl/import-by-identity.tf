@@ -0,0 +1,6 @@
+import {
+  to = tailscale_acl.sample_acl
+  identity = {
+    tailnet = "-"
+  }
+}
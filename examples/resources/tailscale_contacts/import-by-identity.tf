@@ -0,0 +1,6 @@
+import {
+  to = tailscale_contacts.sample_contacts
+  identity = {
+    tailnet = "-"
+  }
+}
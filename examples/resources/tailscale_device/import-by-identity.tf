@@ -0,0 +1,7 @@
+import {
+  to = tailscale_device.sample
+  identity = {
+    tailnet   = "-"
+    device_id = "nodeidCNTRL"
+  }
+}
@@ -0,0 +1,4 @@
+# Devices can be imported using the node ID (preferred), e.g.,
+terraform import tailscale_device.sample nodeidCNTRL
+# Devices can be imported using the legacy ID, e.g.,
+terraform import tailscale_device.sample 123456789
@@ -0,0 +1,13 @@
+resource "tailscale_device" "router" {
+  hostname = "router"
+  wait_for = "5m"
+
+  authorized          = true
+  key_expiry_disabled = true
+  tags                = ["tag:router"]
+  routes              = ["10.0.0.0/16", "0.0.0.0/0", "::/0"]
+
+  posture_attributes = {
+    "custom:environment" = "production"
+  }
+}
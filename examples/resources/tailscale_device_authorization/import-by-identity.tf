@@ -0,0 +1,7 @@
+import {
+  to = tailscale_device_authorization.sample_authorization
+  identity = {
+    tailnet   = "-"
+    device_id = "nodeidCNTRL"
+  }
+}
@@ -0,0 +1,7 @@
+import {
+  to = tailscale_device_ipv4_address.sample
+  identity = {
+    tailnet   = "-"
+    device_id = "nodeidCNTRL"
+  }
+}
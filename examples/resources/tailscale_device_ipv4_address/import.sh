@@ -0,0 +1,4 @@
+# Device IPv4 address can be imported using the node ID (preferred), e.g.,
+terraform import tailscale_device_ipv4_address.sample nodeidCNTRL
+# Device IPv4 address can be imported using the legacy ID, e.g.,
+terraform import tailscale_device_ipv4_address.sample 123456789
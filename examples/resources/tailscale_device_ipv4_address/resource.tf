@@ -0,0 +1,8 @@
+data "tailscale_device" "example_device" {
+  name = "db-1.example.ts.net"
+}
+
+resource "tailscale_device_ipv4_address" "example_address" {
+  device_id    = data.tailscale_device.example_device.node_id
+  ipv4_address = "100.100.1.1"
+}
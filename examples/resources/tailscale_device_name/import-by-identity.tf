@@ -0,0 +1,7 @@
+import {
+  to = tailscale_device_name.sample
+  identity = {
+    tailnet   = "-"
+    device_id = "nodeidCNTRL"
+  }
+}
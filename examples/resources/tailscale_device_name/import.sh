@@ -0,0 +1,4 @@
+# Device name can be imported using the node ID (preferred), e.g.,
+terraform import tailscale_device_name.sample nodeidCNTRL
+# Device name can be imported using the legacy ID, e.g.,
+terraform import tailscale_device_name.sample 123456789
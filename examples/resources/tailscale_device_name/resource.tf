@@ -0,0 +1,8 @@
+data "tailscale_device" "example_device" {
+  hostname = "ip-10-0-0-12"
+}
+
+resource "tailscale_device_name" "example_name" {
+  device_id = data.tailscale_device.example_device.node_id
+  name      = "db-1"
+}
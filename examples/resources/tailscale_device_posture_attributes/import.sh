@@ -0,0 +1,4 @@
+# Device posture attributes can be imported using the node ID (preferred), e.g.,
+terraform import tailscale_device_posture_attributes.sample nodeidCNTRL
+# Device posture attributes can be imported using the legacy ID, e.g.,
+terraform import tailscale_device_posture_attributes.sample 123456789
@@ -0,0 +1,19 @@
+data "tailscale_device" "example_device" {
+  name = "device.example.com"
+}
+
+resource "tailscale_device_posture_attributes" "example" {
+  device_id = data.tailscale_device.example_device.node_id
+
+  attribute {
+    key     = "custom:tier"
+    value   = "gold"
+    comment = "Production database server"
+  }
+
+  attribute {
+    key    = "custom:patched"
+    value  = "true"
+    expiry = "2027-01-01T00:00:00Z"
+  }
+}
@@ -0,0 +1,8 @@
+import {
+  to = tailscale_device_subnet_route.sample
+  identity = {
+    tailnet   = "-"
+    device_id = "nodeidCNTRL"
+    route     = "10.0.1.0/24"
+  }
+}
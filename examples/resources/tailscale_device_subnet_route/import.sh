@@ -0,0 +1,2 @@
+# Device subnet routes can be imported using the node ID and the route, e.g.,
+terraform import tailscale_device_subnet_route.sample nodeidCNTRL:10.0.1.0/24
@@ -0,0 +1,14 @@
+data "tailscale_device" "sample_router" {
+  name = "router.example.com"
+}
+
+# Routes of the same subnet router can be enabled from different modules.
+resource "tailscale_device_subnet_route" "office" {
+  device_id = data.tailscale_device.sample_router.node_id
+  route     = "10.0.1.0/24"
+}
+
+resource "tailscale_device_subnet_route" "datacenter" {
+  device_id = data.tailscale_device.sample_router.node_id
+  route     = "10.10.0.0/16"
+}
@@ -15,9 +15,6 @@ resource "tailscale_device_subnet_routes" "sample_routes" {
 resource "tailscale_device_subnet_routes" "sample_exit_node" {
   # Prefer the new, stable `node_id` attribute; the legacy `.id` field still works.
   device_id = data.tailscale_device.sample_device.node_id
-  routes = [
-    # Configure as an exit node
-    "0.0.0.0/0",
-    "::/0"
-  ]
+  # Configure as an exit node, enabling the 0.0.0.0/0 and ::/0 routes
+  exit_node = true
 }
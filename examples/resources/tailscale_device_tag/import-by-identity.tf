@@ -0,0 +1,8 @@
+import {
+  to = tailscale_device_tag.sample
+  identity = {
+    tailnet   = "-"
+    device_id = "nodeidCNTRL"
+    tag       = "tag:web"
+  }
+}
@@ -0,0 +1,2 @@
+# Device tags can be imported using the node ID and the tag, e.g.,
+terraform import tailscale_device_tag.sample nodeidCNTRL:tag:web
@@ -0,0 +1,14 @@
+data "tailscale_device" "sample_device" {
+  name = "device.example.com"
+}
+
+# Tags can be applied to the same device from different modules.
+resource "tailscale_device_tag" "web" {
+  device_id = data.tailscale_device.sample_device.node_id
+  tag       = "tag:web"
+}
+
+resource "tailscale_device_tag" "monitored" {
+  device_id = data.tailscale_device.sample_device.node_id
+  tag       = "tag:monitored"
+}
@@ -0,0 +1,6 @@
+import {
+  to = tailscale_dns_configuration.sample_configuration
+  identity = {
+    tailnet = "-"
+  }
+}
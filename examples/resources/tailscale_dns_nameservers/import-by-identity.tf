@@ -0,0 +1,6 @@
+import {
+  to = tailscale_dns_nameservers.sample
+  identity = {
+    tailnet = "-"
+  }
+}
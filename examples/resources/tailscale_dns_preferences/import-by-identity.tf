@@ -0,0 +1,6 @@
+import {
+  to = tailscale_dns_preferences.sample_preferences
+  identity = {
+    tailnet = "-"
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = tailscale_dns_search_paths.sample
+  identity = {
+    tailnet = "-"
+  }
+}
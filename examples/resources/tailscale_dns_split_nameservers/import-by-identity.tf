@@ -0,0 +1,7 @@
+import {
+  to = tailscale_dns_split_nameservers.sample_split_nameservers
+  identity = {
+    tailnet = "-"
+    domain  = "example.com"
+  }
+}
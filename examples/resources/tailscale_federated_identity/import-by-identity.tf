@@ -0,0 +1,7 @@
+import {
+  to = tailscale_federated_identity.example
+  identity = {
+    tailnet = "-"
+    id      = "k1234511CNTRL"
+  }
+}
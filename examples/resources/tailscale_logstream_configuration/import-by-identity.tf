@@ -0,0 +1,7 @@
+import {
+  to = tailscale_logstream_configuration.sample_logstream_configuration
+  identity = {
+    tailnet  = "-"
+    log_type = "network"
+  }
+}
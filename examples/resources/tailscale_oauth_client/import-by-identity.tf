@@ -0,0 +1,7 @@
+import {
+  to = tailscale_oauth_client.example
+  identity = {
+    tailnet = "-"
+    id      = "k1234511CNTRL"
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = tailscale_policy.sample_policy
+  identity = {
+    tailnet = "-"
+  }
+}
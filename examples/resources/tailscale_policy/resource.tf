@@ -0,0 +1,43 @@
+locals {
+  services = {
+    web = 443
+    db  = 5432
+  }
+}
+
+resource "tailscale_policy" "sample_policy" {
+  groups {
+    name    = "group:engineering"
+    members = ["alice@example.com", "bob@example.com"]
+  }
+
+  dynamic "tag_owners" {
+    for_each = local.services
+    content {
+      tag    = "tag:${tag_owners.key}"
+      owners = ["group:engineering"]
+    }
+  }
+
+  // Allow engineering access to each service on its port.
+  dynamic "grants" {
+    for_each = local.services
+    content {
+      src = ["group:engineering"]
+      dst = ["tag:${grants.key}"]
+      ip  = ["tcp:${grants.value}"]
+    }
+  }
+
+  ssh {
+    action = "check"
+    src    = ["group:engineering"]
+    dst    = ["tag:web"]
+    users  = ["autogroup:nonroot"]
+  }
+
+  tests {
+    src    = "alice@example.com"
+    accept = ["tag:db:5432"]
+  }
+}
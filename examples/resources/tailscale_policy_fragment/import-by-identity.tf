@@ -0,0 +1,7 @@
+import {
+  to = tailscale_policy_fragment.web_grants
+  identity = {
+    tailnet = "-"
+    key     = "web-team-grants"
+  }
+}
@@ -0,0 +1,2 @@
+# Policy fragments can be imported using their key.
+terraform import tailscale_policy_fragment.web_grants web-team-grants
@@ -0,0 +1,22 @@
+// Owned by the platform team.
+resource "tailscale_policy_fragment" "tag_owners" {
+  key     = "platform-tag-owners"
+  section = "tagOwners"
+  content = jsonencode({
+    "tag:web" = ["group:web-team"]
+    "tag:db"  = ["group:db-team"]
+  })
+}
+
+// Owned by the web team, in a separate workspace.
+resource "tailscale_policy_fragment" "web_grants" {
+  key     = "web-team-grants"
+  section = "grants"
+  content = jsonencode([
+    {
+      src = ["group:web-team"]
+      dst = ["tag:web"]
+      ip  = ["443"]
+    },
+  ])
+}
@@ -0,0 +1,7 @@
+import {
+  to = tailscale_posture_integration.sample_posture_integration
+  identity = {
+    tailnet = "-"
+    id      = "123456789"
+  }
+}
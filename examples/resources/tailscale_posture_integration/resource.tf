@@ -4,3 +4,11 @@ resource "tailscale_posture_integration" "sample_posture_integration" {
   client_id        = "clientid1"
   client_secret    = "test-secret1"
 }
+
+# With Terraform 1.11 or later, the secret can be kept out of state by using
+# the write-only attribute. Increment the version to rotate the secret.
+resource "tailscale_posture_integration" "sample_posture_integration_write_only" {
+  posture_provider         = "kolide"
+  client_secret_wo         = var.kolide_api_key
+  client_secret_wo_version = 1
+}
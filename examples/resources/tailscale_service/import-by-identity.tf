@@ -0,0 +1,7 @@
+import {
+  to = tailscale_service.example
+  identity = {
+    tailnet = "-"
+    name    = "svc:my-service"
+  }
+}
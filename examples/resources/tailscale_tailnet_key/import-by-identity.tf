@@ -0,0 +1,7 @@
+import {
+  to = tailscale_tailnet_key.sample_key
+  identity = {
+    tailnet = "-"
+    id      = "k123456CNTRL"
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = tailscale_tailnet_settings.sample_preferences
+  identity = {
+    tailnet = "-"
+  }
+}
@@ -0,0 +1,7 @@
+import {
+  to = tailscale_webhook.sample_webhook
+  identity = {
+    tailnet = "-"
+    id      = "123456789"
+  }
+}
@@ -0,0 +1,379 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// cgnatRange is the range from which Tailscale assigns IPv4 addresses.
+var cgnatRange = netip.MustParsePrefix("100.64.0.0/10")
+
+// device is a device in the fake tailnet, with its posture attributes.
+type device struct {
+	tailscale.Device
+	attributes map[string]postureAttribute
+}
+
+// postureAttribute is a custom posture attribute set through the API.
+type postureAttribute struct {
+	Value   any
+	Expiry  tailscale.Time
+	Comment string
+}
+
+// AddDevice adds a device to the tailnet, as if it had joined the tailnet.
+// Missing IDs, names and addresses are generated, and the stored device is
+// returned.
+func (s *Server) AddDevice(d tailscale.Device) tailscale.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	n := s.nextID
+	if d.NodeID == "" {
+		d.NodeID = fmt.Sprintf("n%dCNTRL", n)
+	}
+	if d.ID == "" {
+		d.ID = strconv.Itoa(1000 + n)
+	}
+	if d.Hostname == "" {
+		d.Hostname = fmt.Sprintf("device-%d", n)
+	}
+	if d.Name == "" {
+		d.Name = d.Hostname + "." + DNSSuffix
+	}
+	if len(d.Addresses) == 0 {
+		d.Addresses = []string{
+			fmt.Sprintf("100.64.0.%d", n),
+			fmt.Sprintf("fd7a:115c:a1e0::%x", n),
+		}
+	}
+	if d.User == "" && len(d.Tags) == 0 {
+		d.User = CreatorLoginName
+	}
+	if d.OS == "" {
+		d.OS = "linux"
+	}
+	if d.Created.IsZero() {
+		d.Created = tailscale.Time{Time: s.now()}
+	}
+	if d.Expires.IsZero() && !d.KeyExpiryDisabled {
+		d.Expires = tailscale.Time{Time: d.Created.AddDate(0, 0, s.settings.DevicesKeyDurationDays)}
+	}
+
+	s.devices = append(s.devices, &device{Device: d, attributes: make(map[string]postureAttribute)})
+	return d
+}
+
+// Device returns the device with the given node ID or legacy ID.
+func (s *Server) Device(id string) (tailscale.Device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.findDevice(id)
+	if d == nil {
+		return tailscale.Device{}, false
+	}
+	return d.Device, true
+}
+
+// AdvertiseRoutes sets the routes advertised by the device with the given node
+// ID or legacy ID, as if the device had been reconfigured.
+func (s *Server) AdvertiseRoutes(id string, routes []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d := s.findDevice(id); d != nil {
+		d.AdvertisedRoutes = routes
+	}
+}
+
+// findDevice returns the device with the given node ID or legacy ID, or nil.
+func (s *Server) findDevice(id string) *device {
+	for _, d := range s.devices {
+		if d.NodeID == id || d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *Server) registerDeviceHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/devices", s.listDevices)
+	mux.HandleFunc("GET /api/v2/device/{id}", s.withDevice(s.getDevice))
+	mux.HandleFunc("DELETE /api/v2/device/{id}", s.withDevice(s.deleteDevice))
+	mux.HandleFunc("POST /api/v2/device/{id}/authorized", s.withDevice(s.setDeviceAuthorized))
+	mux.HandleFunc("POST /api/v2/device/{id}/name", s.withDevice(s.setDeviceName))
+	mux.HandleFunc("POST /api/v2/device/{id}/tags", s.withDevice(s.setDeviceTags))
+	mux.HandleFunc("POST /api/v2/device/{id}/key", s.withDevice(s.setDeviceKey))
+	mux.HandleFunc("POST /api/v2/device/{id}/expire", s.withDevice(s.expireDevice))
+	mux.HandleFunc("POST /api/v2/device/{id}/ip", s.withDevice(s.setDeviceIPv4Address))
+	mux.HandleFunc("GET /api/v2/device/{id}/routes", s.withDevice(s.getDeviceRoutes))
+	mux.HandleFunc("POST /api/v2/device/{id}/routes", s.withDevice(s.setDeviceRoutes))
+	mux.HandleFunc("GET /api/v2/device/{id}/attributes", s.withDevice(s.getPostureAttributes))
+	mux.HandleFunc("POST /api/v2/device/{id}/attributes/{key}", s.withDevice(s.setPostureAttribute))
+	mux.HandleFunc("DELETE /api/v2/device/{id}/attributes/{key}", s.withDevice(s.deletePostureAttribute))
+}
+
+// withDevice looks up the device in the request path, returning a not found
+// error if it does not exist.
+func (s *Server) withDevice(h func(http.ResponseWriter, *http.Request, *device)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d := s.findDevice(r.PathValue("id"))
+		if d == nil {
+			writeError(w, http.StatusNotFound, "device not found")
+			return
+		}
+		h(w, r, d)
+	}
+}
+
+// deviceFields returns the device with the fields requested by r. Routes and
+// connectivity are only returned when all fields are requested.
+func deviceFields(r *http.Request, d tailscale.Device) tailscale.Device {
+	if r.URL.Query().Get("fields") == string(tailscale.IncludeFieldsAll) {
+		return d
+	}
+	d.AdvertisedRoutes = nil
+	d.EnabledRoutes = nil
+	d.ClientConnectivity = nil
+	return d
+}
+
+func (s *Server) listDevices(w http.ResponseWriter, r *http.Request) {
+	filters := r.URL.Query()
+	filters.Del("fields")
+
+	devices := []tailscale.Device{}
+	for _, d := range s.devices {
+		if matchesDeviceFilters(d.Device, filters) {
+			devices = append(devices, deviceFields(r, d.Device))
+		}
+	}
+	writeJSON(w, map[string][]tailscale.Device{"devices": devices})
+}
+
+// matchesDeviceFilters reports whether every filter matches a top-level
+// property of the device. A filter matches if any of its values equals the
+// property, or an element of it if the property is a list.
+func matchesDeviceFilters(d tailscale.Device, filters map[string][]string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	b, _ := json.Marshal(d)
+	var properties map[string]any
+	_ = json.Unmarshal(b, &properties)
+
+	for name, values := range filters {
+		var actual []string
+		switch v := properties[name].(type) {
+		case []any:
+			for _, e := range v {
+				actual = append(actual, fmt.Sprint(e))
+			}
+		case nil:
+		default:
+			actual = []string{fmt.Sprint(v)}
+		}
+		if !slices.ContainsFunc(values, func(value string) bool { return slices.Contains(actual, value) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) getDevice(w http.ResponseWriter, r *http.Request, d *device) {
+	writeJSON(w, deviceFields(r, d.Device))
+}
+
+func (s *Server) deleteDevice(w http.ResponseWriter, r *http.Request, d *device) {
+	s.devices = slices.DeleteFunc(s.devices, func(other *device) bool { return other == d })
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setDeviceAuthorized(w http.ResponseWriter, r *http.Request, d *device) {
+	var req struct {
+		Authorized bool `json:"authorized"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	d.Authorized = req.Authorized
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setDeviceName(w http.ResponseWriter, r *http.Request, d *device) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	// An empty name resets the name to one generated from the hostname.
+	name := strings.TrimSuffix(strings.TrimSuffix(req.Name, "."), "."+DNSSuffix)
+	if name == "" {
+		name = d.Hostname
+	}
+	if strings.Contains(name, ".") {
+		writeError(w, http.StatusBadRequest, "invalid machine name %q", req.Name)
+		return
+	}
+	for _, other := range s.devices {
+		if other != d && other.Name == name+"."+DNSSuffix {
+			writeError(w, http.StatusConflict, "machine name %q is already in use", name)
+			return
+		}
+	}
+
+	d.Name = name + "." + DNSSuffix
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setDeviceTags(w http.ResponseWriter, r *http.Request, d *device) {
+	var req struct {
+		Tags []string `json:"tags"`
+	}
+	if !readJSON(w, r, &req) || !validTags(w, req.Tags) {
+		return
+	}
+
+	d.Tags = req.Tags
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setDeviceKey(w http.ResponseWriter, r *http.Request, d *device) {
+	var req tailscale.DeviceKey
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	d.KeyExpiryDisabled = req.KeyExpiryDisabled
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) expireDevice(w http.ResponseWriter, r *http.Request, d *device) {
+	d.Expires = tailscale.Time{Time: s.now()}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) setDeviceIPv4Address(w http.ResponseWriter, r *http.Request, d *device) {
+	var req struct {
+		IPv4 string `json:"ipv4"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	addr, err := netip.ParseAddr(req.IPv4)
+	if err != nil || !addr.Is4() || !cgnatRange.Contains(addr) {
+		writeError(w, http.StatusBadRequest, "%q is not an IPv4 address in %s", req.IPv4, cgnatRange)
+		return
+	}
+	for _, other := range s.devices {
+		if other != d && slices.Contains(other.Addresses, addr.String()) {
+			writeError(w, http.StatusConflict, "address %s is already in use", addr)
+			return
+		}
+	}
+
+	addresses := slices.DeleteFunc(slices.Clone(d.Addresses), func(a string) bool {
+		other, err := netip.ParseAddr(a)
+		return err == nil && other.Is4()
+	})
+	d.Addresses = append([]string{addr.String()}, addresses...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getDeviceRoutes(w http.ResponseWriter, r *http.Request, d *device) {
+	writeJSON(w, deviceRoutes(d))
+}
+
+func (s *Server) setDeviceRoutes(w http.ResponseWriter, r *http.Request, d *device) {
+	var req struct {
+		Routes []string `json:"routes"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	routes := []string{}
+	for _, route := range req.Routes {
+		prefix, err := netip.ParsePrefix(route)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid route %q", route)
+			return
+		}
+		routes = append(routes, prefix.Masked().String())
+	}
+
+	d.EnabledRoutes = routes
+	writeJSON(w, deviceRoutes(d))
+}
+
+// deviceRoutes returns the routes of a device, which are never null.
+func deviceRoutes(d *device) tailscale.DeviceRoutes {
+	return tailscale.DeviceRoutes{
+		Advertised: append([]string{}, d.AdvertisedRoutes...),
+		Enabled:    append([]string{}, d.EnabledRoutes...),
+	}
+}
+
+func (s *Server) getPostureAttributes(w http.ResponseWriter, r *http.Request, d *device) {
+	resp := tailscale.DevicePostureAttributes{
+		Attributes: map[string]any{
+			"node:os":        d.OS,
+			"node:tsVersion": d.ClientVersion,
+		},
+		Expiries: map[string]tailscale.Time{},
+	}
+	for key, attr := range d.attributes {
+		if !attr.Expiry.IsZero() && !attr.Expiry.After(s.now()) {
+			continue
+		}
+		resp.Attributes[key] = attr.Value
+		if !attr.Expiry.IsZero() {
+			resp.Expiries[key] = attr.Expiry
+		}
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) setPostureAttribute(w http.ResponseWriter, r *http.Request, d *device) {
+	key := r.PathValue("key")
+	if !strings.HasPrefix(key, "custom:") {
+		writeError(w, http.StatusBadRequest, "posture attribute %q must start with \"custom:\"", key)
+		return
+	}
+
+	var req tailscale.DevicePostureAttributeRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	switch req.Value.(type) {
+	case string, bool, float64:
+	default:
+		writeError(w, http.StatusBadRequest, "posture attribute %q must be a string, number or boolean", key)
+		return
+	}
+
+	d.attributes[key] = postureAttribute{Value: req.Value, Expiry: req.Expiry, Comment: req.Comment}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deletePostureAttribute(w http.ResponseWriter, r *http.Request, d *device) {
+	delete(d.attributes, r.PathValue("key"))
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,196 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"net/http"
+	"net/netip"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// DNSConfiguration returns the DNS configuration of the tailnet, which backs
+// the nameservers, search paths, preferences and split DNS endpoints.
+func (s *Server) DNSConfiguration() tailscale.DNSConfiguration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dns
+}
+
+func (s *Server) registerDNSHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/dns/nameservers", s.getNameservers)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/dns/nameservers", s.setNameservers)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/dns/searchpaths", s.getSearchPaths)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/dns/searchpaths", s.setSearchPaths)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/dns/preferences", s.getDNSPreferences)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/dns/preferences", s.setDNSPreferences)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/dns/split-dns", s.getSplitDNS)
+	mux.HandleFunc("PATCH /api/v2/tailnet/{tailnet}/dns/split-dns", s.updateSplitDNS)
+	mux.HandleFunc("PUT /api/v2/tailnet/{tailnet}/dns/split-dns", s.setSplitDNS)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/dns/configuration", s.getDNSConfiguration)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/dns/configuration", s.setDNSConfiguration)
+}
+
+// resolvers converts nameserver addresses to resolvers, or reports an error
+// response if any of them is invalid.
+func resolvers(w http.ResponseWriter, addresses []string) ([]tailscale.DNSConfigurationResolver, bool) {
+	var out []tailscale.DNSConfigurationResolver
+	for _, address := range addresses {
+		if _, err := netip.ParseAddr(address); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid nameserver %q", address)
+			return nil, false
+		}
+		out = append(out, tailscale.DNSConfigurationResolver{Address: address})
+	}
+	return out, true
+}
+
+// addresses returns the addresses of resolvers, which is never null.
+func addresses(resolvers []tailscale.DNSConfigurationResolver) []string {
+	out := []string{}
+	for _, r := range resolvers {
+		out = append(out, r.Address)
+	}
+	return out
+}
+
+func (s *Server) getNameservers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string][]string{"dns": addresses(s.dns.Nameservers)})
+}
+
+func (s *Server) setNameservers(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DNS []string `json:"dns"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	nameservers, ok := resolvers(w, req.DNS)
+	if !ok {
+		return
+	}
+	s.dns.Nameservers = nameservers
+	writeJSON(w, map[string][]string{"dns": addresses(s.dns.Nameservers)})
+}
+
+func (s *Server) getSearchPaths(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string][]string{"searchPaths": append([]string{}, s.dns.SearchPaths...)})
+}
+
+func (s *Server) setSearchPaths(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SearchPaths []string `json:"searchPaths"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.dns.SearchPaths = req.SearchPaths
+	writeJSON(w, map[string][]string{"searchPaths": append([]string{}, s.dns.SearchPaths...)})
+}
+
+func (s *Server) getDNSPreferences(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, tailscale.DNSPreferences{MagicDNS: s.dns.Preferences.MagicDNS})
+}
+
+func (s *Server) setDNSPreferences(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.DNSPreferences
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.dns.Preferences.MagicDNS = req.MagicDNS
+	writeJSON(w, req)
+}
+
+// splitDNS returns the split DNS configuration in the format of the split DNS
+// endpoints.
+func (s *Server) splitDNS() tailscale.SplitDNSResponse {
+	out := tailscale.SplitDNSResponse{}
+	for domain, nameservers := range s.dns.SplitDNS {
+		out[domain] = addresses(nameservers)
+	}
+	return out
+}
+
+func (s *Server) getSplitDNS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.splitDNS())
+}
+
+func (s *Server) updateSplitDNS(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.SplitDNSRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	updated := make(map[string][]tailscale.DNSConfigurationResolver, len(s.dns.SplitDNS))
+	for domain, nameservers := range s.dns.SplitDNS {
+		updated[domain] = nameservers
+	}
+	for domain, addrs := range req {
+		// Mapping a domain to null removes it.
+		if addrs == nil {
+			delete(updated, domain)
+			continue
+		}
+		nameservers, ok := resolvers(w, addrs)
+		if !ok {
+			return
+		}
+		updated[domain] = nameservers
+	}
+
+	s.dns.SplitDNS = updated
+	writeJSON(w, s.splitDNS())
+}
+
+func (s *Server) setSplitDNS(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.SplitDNSRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	updated := make(map[string][]tailscale.DNSConfigurationResolver, len(req))
+	for domain, addrs := range req {
+		nameservers, ok := resolvers(w, addrs)
+		if !ok {
+			return
+		}
+		updated[domain] = nameservers
+	}
+
+	s.dns.SplitDNS = updated
+	writeJSON(w, s.splitDNS())
+}
+
+func (s *Server) getDNSConfiguration(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.dns)
+}
+
+func (s *Server) setDNSConfiguration(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.DNSConfiguration
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	for _, nameservers := range append([][]tailscale.DNSConfigurationResolver{req.Nameservers}, mapValues(req.SplitDNS)...) {
+		if _, ok := resolvers(w, addresses(nameservers)); !ok {
+			return
+		}
+	}
+
+	s.dns = req
+	writeJSON(w, s.dns)
+}
+
+// mapValues returns the values of m in no particular order.
+func mapValues[K comparable, V any](m map[K]V) []V {
+	out := make([]V, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
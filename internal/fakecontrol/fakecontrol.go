@@ -0,0 +1,185 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+// Package fakecontrol implements a fake Tailscale control plane that serves
+// the parts of the Tailscale API used by the provider from memory.
+//
+// Unlike canned responses, the fake keeps state between requests, so a
+// resource can be created, read, updated, imported and destroyed against it
+// without access to a real tailnet:
+//
+//	server := fakecontrol.NewServer(t)
+//	server.AddDevice(tailscale.Device{Hostname: "web-1"})
+//	t.Setenv("TAILSCALE_BASE_URL", server.URL())
+//
+// The fake models a single tailnet, so every tailnet name in a request path
+// refers to the same state. Devices, webhooks and posture integrations are
+// addressed by ID, as in the real API.
+package fakecontrol
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// DNSSuffix is the MagicDNS suffix of the fake tailnet, which is appended to
+// device names.
+const DNSSuffix = "example.ts.net"
+
+// CreatorLoginName is the login name of the user that creates keys, webhooks
+// and other objects in the fake tailnet.
+const CreatorLoginName = "admin@example.com"
+
+// Server is a fake Tailscale control plane. All methods are safe for
+// concurrent use.
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	now      func() time.Time
+	nextID   int
+	requests []string
+
+	policy         string
+	policyModified bool
+
+	devices             []*device
+	keys                map[string]*tailscale.Key
+	dns                 tailscale.DNSConfiguration
+	webhooks            map[string]*webhook
+	logstreams          map[tailscale.LogType]tailscale.LogstreamConfiguration
+	awsExternalID       *tailscale.AWSExternalID
+	contacts            tailscale.Contacts
+	settings            tailscale.TailnetSettings
+	postureIntegrations map[string]*tailscale.PostureIntegration
+	services            map[string]*tailscale.Service
+}
+
+// NewServer starts a fake control plane with an empty tailnet, which is
+// stopped when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		now:                 func() time.Time { return time.Now().UTC().Truncate(time.Second) },
+		policy:              DefaultPolicy,
+		keys:                make(map[string]*tailscale.Key),
+		webhooks:            make(map[string]*webhook),
+		logstreams:          make(map[tailscale.LogType]tailscale.LogstreamConfiguration),
+		postureIntegrations: make(map[string]*tailscale.PostureIntegration),
+		services:            make(map[string]*tailscale.Service),
+		contacts: tailscale.Contacts{
+			Account:  tailscale.Contact{Email: CreatorLoginName},
+			Support:  tailscale.Contact{Email: CreatorLoginName},
+			Security: tailscale.Contact{Email: CreatorLoginName},
+		},
+		settings: tailscale.TailnetSettings{
+			DevicesKeyDurationDays:                 180,
+			UsersRoleAllowedToJoinExternalTailnets: tailscale.RoleAllowedToJoinExternalTailnetsMember,
+		},
+	}
+
+	mux := http.NewServeMux()
+	s.registerPolicyHandlers(mux)
+	s.registerDeviceHandlers(mux)
+	s.registerKeyHandlers(mux)
+	s.registerDNSHandlers(mux)
+	s.registerWebhookHandlers(mux)
+	s.registerLoggingHandlers(mux)
+	s.registerTailnetHandlers(mux)
+	s.registerPostureHandlers(mux)
+	s.registerServiceHandlers(mux)
+
+	s.srv = httptest.NewServer(s.handler(mux))
+	t.Cleanup(s.srv.Close)
+
+	return s
+}
+
+// URL returns the base URL of the fake control plane, which can be used as
+// the provider's `base_url`.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Requests returns every request served so far, formatted as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// handler serializes requests, so that handlers can access the state of the
+// tailnet without further locking, and returns API errors for unknown routes.
+func (s *Server) handler(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+		if _, pattern := mux.Handler(r); pattern == "" {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// newID returns an ID for a new object with the given prefix. IDs increase
+// monotonically, so that tests are deterministic.
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%dCNTRL", prefix, s.nextID)
+}
+
+// compareIDs orders IDs returned by [Server.newID] by creation.
+func compareIDs(a, b string) int {
+	return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+}
+
+// writeJSON writes v as the JSON body of a successful response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the format of the Tailscale API,
+// which the client returns as a [tailscale.APIError].
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": fmt.Sprintf(format, args...)})
+}
+
+// readJSON decodes the JSON body of r into v, writing an error response and
+// returning false if it is invalid.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %s", err)
+		return false
+	}
+	return true
+}
+
+// validTags reports an error response if any of tags is not a valid tag name.
+func validTags(w http.ResponseWriter, tags []string) bool {
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, "tag:") || len(tag) == len("tag:") {
+			writeError(w, http.StatusBadRequest, "tag %q must start with \"tag:\"", tag)
+			return false
+		}
+	}
+	return true
+}
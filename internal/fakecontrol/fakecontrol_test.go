@@ -0,0 +1,357 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// newClient returns a client for a new fake control plane.
+func newClient(t *testing.T) (*tailscale.Client, *Server) {
+	t.Helper()
+
+	server := NewServer(t)
+	baseURL, err := url.Parse(server.URL())
+	require.NoError(t, err)
+
+	return &tailscale.Client{BaseURL: baseURL, APIKey: "tskey-api-test", Tailnet: "-"}, server
+}
+
+// assertStatus checks that err is an API error with the given status.
+func assertStatus(t *testing.T, status int, err error) {
+	t.Helper()
+
+	var apiErr tailscale.APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, status, apiErr.Status)
+	}
+}
+
+func TestPolicyFile(t *testing.T) {
+	ctx := context.Background()
+	client, server := newClient(t)
+
+	raw, err := client.PolicyFile().Raw(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPolicy, raw.HuJSON)
+
+	// Only the default policy file matches the `ts-default` ETag.
+	const policy = `{"tagOwners": {"tag:web": ["autogroup:admin"]}}`
+	require.NoError(t, client.PolicyFile().Set(ctx, policy, "ts-default"))
+	assertStatus(t, http.StatusPreconditionFailed, client.PolicyFile().Set(ctx, policy, "ts-default"))
+
+	raw, err = client.PolicyFile().Raw(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, policy, raw.HuJSON)
+	_, etag := server.Policy()
+	assert.Equal(t, `"`+etag+`"`, raw.ETag)
+
+	acl, err := client.PolicyFile().Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"tag:web": {"autogroup:admin"}}, acl.TagOwners)
+
+	server.SetPolicy(`{"groups": {"group:ops": ["ops@example.com"]}}`)
+	assertStatus(t, http.StatusPreconditionFailed, client.PolicyFile().Set(ctx, policy, raw.ETag))
+
+	assertStatus(t, http.StatusBadRequest, client.PolicyFile().Set(ctx, `{"acls": `, ""))
+	assertStatus(t, http.StatusBadRequest, client.PolicyFile().Validate(ctx, `{"acls": `))
+	assert.NoError(t, client.PolicyFile().Validate(ctx, policy))
+
+	// An empty policy file resets the policy to its default.
+	require.NoError(t, client.PolicyFile().Set(ctx, "", ""))
+	assert.NoError(t, client.PolicyFile().Set(ctx, policy, "ts-default"))
+}
+
+func TestDevices(t *testing.T) {
+	ctx := context.Background()
+	client, server := newClient(t)
+
+	web := server.AddDevice(tailscale.Device{Hostname: "web", Tags: []string{"tag:web"}})
+	db := server.AddDevice(tailscale.Device{Hostname: "db", AdvertisedRoutes: []string{"10.0.0.0/24"}})
+	assert.Equal(t, "web."+DNSSuffix, web.Name)
+	assert.Empty(t, web.User)
+	assert.Equal(t, CreatorLoginName, db.User)
+
+	devices, err := client.Devices().List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, devices, 2)
+	assert.Nil(t, devices[1].AdvertisedRoutes)
+
+	devices, err = client.Devices().List(ctx, tailscale.WithFilter("tags", []string{"tag:web"}))
+	require.NoError(t, err)
+	if assert.Len(t, devices, 1) {
+		assert.Equal(t, web.NodeID, devices[0].NodeID)
+	}
+
+	device, err := client.Devices().GetWithAllFields(ctx, db.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24"}, device.AdvertisedRoutes)
+
+	require.NoError(t, client.Devices().SetAuthorized(ctx, db.NodeID, true))
+	require.NoError(t, client.Devices().SetKey(ctx, db.NodeID, tailscale.DeviceKey{KeyExpiryDisabled: true}))
+	require.NoError(t, client.Devices().SetTags(ctx, db.NodeID, []string{"tag:db"}))
+	require.NoError(t, client.Devices().SetName(ctx, db.NodeID, "postgres"))
+	require.NoError(t, client.Devices().SetIPv4Address(ctx, db.NodeID, "100.100.1.1"))
+	require.NoError(t, client.Devices().SetSubnetRoutes(ctx, db.NodeID, []string{"10.0.0.1/24"}))
+
+	device, err = client.Devices().Get(ctx, db.NodeID)
+	require.NoError(t, err)
+	assert.True(t, device.Authorized)
+	assert.True(t, device.KeyExpiryDisabled)
+	assert.Equal(t, []string{"tag:db"}, device.Tags)
+	assert.Equal(t, "postgres."+DNSSuffix, device.Name)
+	assert.Equal(t, "100.100.1.1", device.Addresses[0])
+	assert.Len(t, device.Addresses, 2)
+
+	routes, err := client.Devices().SubnetRoutes(ctx, db.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, &tailscale.DeviceRoutes{Advertised: []string{"10.0.0.0/24"}, Enabled: []string{"10.0.0.0/24"}}, routes)
+
+	server.AdvertiseRoutes(db.NodeID, []string{"10.0.0.0/24", "10.1.0.0/24"})
+	routes, err = client.Devices().SubnetRoutes(ctx, db.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24", "10.1.0.0/24"}, routes.Advertised)
+
+	assertStatus(t, http.StatusBadRequest, client.Devices().SetTags(ctx, db.NodeID, []string{"db"}))
+	assertStatus(t, http.StatusBadRequest, client.Devices().SetIPv4Address(ctx, db.NodeID, "192.168.0.1"))
+	assertStatus(t, http.StatusConflict, client.Devices().SetIPv4Address(ctx, db.NodeID, web.Addresses[0]))
+	assertStatus(t, http.StatusConflict, client.Devices().SetName(ctx, db.NodeID, "web"))
+
+	require.NoError(t, client.Devices().Delete(ctx, db.NodeID))
+	_, err = client.Devices().Get(ctx, db.NodeID)
+	assertStatus(t, http.StatusNotFound, err)
+}
+
+func TestDevicePostureAttributes(t *testing.T) {
+	ctx := context.Background()
+	client, server := newClient(t)
+	d := server.AddDevice(tailscale.Device{})
+
+	require.NoError(t, client.Devices().SetPostureAttribute(ctx, d.NodeID, "custom:tier", tailscale.DevicePostureAttributeRequest{Value: "prod"}))
+	assertStatus(t, http.StatusBadRequest, client.Devices().SetPostureAttribute(ctx, d.NodeID, "node:os", tailscale.DevicePostureAttributeRequest{Value: "windows"}))
+
+	attrs, err := client.Devices().GetPostureAttributes(ctx, d.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", attrs.Attributes["custom:tier"])
+	assert.Equal(t, "linux", attrs.Attributes["node:os"])
+
+	require.NoError(t, client.Devices().DeletePostureAttribute(ctx, d.NodeID, "custom:tier"))
+	attrs, err = client.Devices().GetPostureAttributes(ctx, d.NodeID)
+	require.NoError(t, err)
+	assert.NotContains(t, attrs.Attributes, "custom:tier")
+}
+
+func TestKeys(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newClient(t)
+
+	var req tailscale.CreateKeyRequest
+	req.Capabilities.Devices.Create.Tags = []string{"tag:ci"}
+	req.ExpirySeconds = 3600
+	authKey, err := client.Keys().CreateAuthKey(ctx, req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, authKey.Key)
+	assert.Equal(t, int64(3600), int64(*authKey.ExpirySeconds))
+
+	oauthClient, err := client.Keys().CreateOAuthClient(ctx, tailscale.CreateOAuthClientRequest{Scopes: []string{"devices:core"}, Tags: []string{"tag:ci"}})
+	require.NoError(t, err)
+	_, err = client.Keys().SetOAuthClient(ctx, oauthClient.ID, tailscale.SetOAuthClientRequest{Scopes: []string{"all:read"}, Description: "read"})
+	require.NoError(t, err)
+
+	identity, err := client.Keys().CreateFederatedIdentity(ctx, tailscale.CreateFederatedIdentityRequest{Scopes: []string{"all:read"}, Issuer: "https://token.actions.githubusercontent.com", Subject: "repo:example/*"})
+	require.NoError(t, err)
+	assert.Empty(t, identity.Key)
+	assert.NotEmpty(t, identity.Audience)
+
+	key, err := client.Keys().Get(ctx, oauthClient.ID)
+	require.NoError(t, err)
+	assert.Empty(t, key.Key)
+	assert.Equal(t, []string{"all:read"}, key.Scopes)
+	assert.Equal(t, "read", key.Description)
+
+	keys, err := client.Keys().List(ctx, true)
+	require.NoError(t, err)
+	assert.Len(t, keys, 3)
+
+	require.NoError(t, client.Keys().Delete(ctx, authKey.ID))
+	_, err = client.Keys().Get(ctx, authKey.ID)
+	assertStatus(t, http.StatusNotFound, err)
+}
+
+func TestDNS(t *testing.T) {
+	ctx := context.Background()
+	client, server := newClient(t)
+
+	require.NoError(t, client.DNS().SetNameservers(ctx, []string{"8.8.8.8"}))
+	require.NoError(t, client.DNS().SetSearchPaths(ctx, []string{"example.com"}))
+	require.NoError(t, client.DNS().SetPreferences(ctx, tailscale.DNSPreferences{MagicDNS: true}))
+	_, err := client.DNS().UpdateSplitDNS(ctx, tailscale.SplitDNSRequest{"corp.example.com": {"10.0.0.53"}, "lab.example.com": {"10.1.0.53"}})
+	require.NoError(t, err)
+	splitDNS, err := client.DNS().UpdateSplitDNS(ctx, tailscale.SplitDNSRequest{"lab.example.com": nil})
+	require.NoError(t, err)
+	assert.Equal(t, tailscale.SplitDNSResponse{"corp.example.com": {"10.0.0.53"}}, splitDNS)
+
+	config, err := client.DNS().Configuration(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, server.DNSConfiguration(), *config)
+	assert.Equal(t, []tailscale.DNSConfigurationResolver{{Address: "8.8.8.8"}}, config.Nameservers)
+	assert.Equal(t, []string{"example.com"}, config.SearchPaths)
+	assert.True(t, config.Preferences.MagicDNS)
+
+	require.NoError(t, client.DNS().SetConfiguration(ctx, tailscale.DNSConfiguration{SearchPaths: []string{"example.net"}}))
+	nameservers, err := client.DNS().Nameservers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, nameservers)
+	searchPaths, err := client.DNS().SearchPaths(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.net"}, searchPaths)
+
+	assertStatus(t, http.StatusBadRequest, client.DNS().SetNameservers(ctx, []string{"dns.example.com"}))
+}
+
+func TestWebhooks(t *testing.T) {
+	ctx := context.Background()
+	client, server := newClient(t)
+
+	wh, err := client.Webhooks().Create(ctx, tailscale.CreateWebhookRequest{
+		EndpointURL:   "https://example.com/webhook",
+		ProviderType:  "slack",
+		Subscriptions: []tailscale.WebhookSubscriptionType{tailscale.WebhookNodeCreated},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, wh.Secret)
+
+	got, err := client.Webhooks().Get(ctx, wh.EndpointID)
+	require.NoError(t, err)
+	assert.Nil(t, got.Secret)
+
+	got, err = client.Webhooks().Update(ctx, wh.EndpointID, []tailscale.WebhookSubscriptionType{tailscale.WebhookUserDeleted})
+	require.NoError(t, err)
+	assert.Equal(t, []tailscale.WebhookSubscriptionType{tailscale.WebhookUserDeleted}, got.Subscriptions)
+
+	rotated, err := client.Webhooks().RotateSecret(ctx, wh.EndpointID)
+	require.NoError(t, err)
+	assert.NotEqual(t, *wh.Secret, *rotated.Secret)
+
+	require.NoError(t, client.Webhooks().Test(ctx, wh.EndpointID))
+	assert.Equal(t, 1, server.WebhookTestEvents(wh.EndpointID))
+
+	require.NoError(t, client.Webhooks().Delete(ctx, wh.EndpointID))
+	_, err = client.Webhooks().Get(ctx, wh.EndpointID)
+	assertStatus(t, http.StatusNotFound, err)
+}
+
+func TestLogging(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newClient(t)
+
+	_, err := client.Logging().LogstreamConfiguration(ctx, tailscale.LogTypeNetwork)
+	assertStatus(t, http.StatusNotFound, err)
+
+	require.NoError(t, client.Logging().SetLogstreamConfiguration(ctx, tailscale.LogTypeNetwork, tailscale.SetLogstreamConfigurationRequest{
+		DestinationType: tailscale.LogstreamSplunkEndpoint,
+		URL:             "https://splunk.example.com",
+		Token:           "secret",
+	}))
+	config, err := client.Logging().LogstreamConfiguration(ctx, tailscale.LogTypeNetwork)
+	require.NoError(t, err)
+	assert.Equal(t, tailscale.LogTypeNetwork, config.LogType)
+	assert.Equal(t, "https://splunk.example.com", config.URL)
+
+	require.NoError(t, client.Logging().DeleteLogstreamConfiguration(ctx, tailscale.LogTypeNetwork))
+	_, err = client.Logging().LogstreamConfiguration(ctx, tailscale.LogTypeNetwork)
+	assertStatus(t, http.StatusNotFound, err)
+
+	id, err := client.Logging().CreateOrGetAwsExternalId(ctx, true)
+	require.NoError(t, err)
+	reused, err := client.Logging().CreateOrGetAwsExternalId(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, id, reused)
+	assert.NoError(t, client.Logging().ValidateAWSTrustPolicy(ctx, id.ExternalID, "arn:aws:iam::123456789012:role/logs"))
+}
+
+func TestContactsAndSettings(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newClient(t)
+
+	email := "security@example.com"
+	require.NoError(t, client.Contacts().Update(ctx, tailscale.ContactSecurity, tailscale.UpdateContactRequest{Email: &email}))
+	contacts, err := client.Contacts().Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, email, contacts.Security.Email)
+	assert.Equal(t, CreatorLoginName, contacts.Account.Email)
+
+	days := 30
+	require.NoError(t, client.TailnetSettings().Update(ctx, tailscale.UpdateTailnetSettingsRequest{DevicesKeyDurationDays: &days}))
+	settings, err := client.TailnetSettings().Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 30, settings.DevicesKeyDurationDays)
+	assert.Equal(t, tailscale.RoleAllowedToJoinExternalTailnetsMember, settings.UsersRoleAllowedToJoinExternalTailnets)
+
+	days = 365
+	assertStatus(t, http.StatusBadRequest, client.TailnetSettings().Update(ctx, tailscale.UpdateTailnetSettingsRequest{DevicesKeyDurationDays: &days}))
+}
+
+func TestPostureIntegrations(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newClient(t)
+
+	intg, err := client.DevicePosture().CreateIntegration(ctx, tailscale.CreatePostureIntegrationRequest{
+		Provider:     tailscale.PostureIntegrationProviderFalcon,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	})
+	require.NoError(t, err)
+
+	intg, err = client.DevicePosture().UpdateIntegration(ctx, intg.ID, tailscale.UpdatePostureIntegrationRequest{ClientID: "other"})
+	require.NoError(t, err)
+	assert.Equal(t, "other", intg.ClientID)
+
+	integrations, err := client.DevicePosture().ListIntegrations(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []tailscale.PostureIntegration{*intg}, integrations)
+
+	require.NoError(t, client.DevicePosture().DeleteIntegration(ctx, intg.ID))
+	_, err = client.DevicePosture().GetIntegration(ctx, intg.ID)
+	assertStatus(t, http.StatusNotFound, err)
+}
+
+func TestServices(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newClient(t)
+
+	require.NoError(t, client.Services().CreateOrUpdate(ctx, tailscale.Service{Name: "svc:web", Ports: []string{"tcp:443"}}))
+	svc, err := client.Services().Get(ctx, "svc:web")
+	require.NoError(t, err)
+	assert.Len(t, svc.Addrs, 2)
+
+	require.NoError(t, client.Services().CreateOrUpdate(ctx, tailscale.Service{Name: "svc:web", Comment: "updated"}))
+	updated, err := client.Services().Get(ctx, "svc:web")
+	require.NoError(t, err)
+	assert.Equal(t, svc.Addrs, updated.Addrs)
+	assert.Equal(t, "updated", updated.Comment)
+
+	assertStatus(t, http.StatusBadRequest, client.Services().CreateOrUpdate(ctx, tailscale.Service{Name: "web"}))
+
+	require.NoError(t, client.Services().Delete(ctx, "svc:web"))
+	services, err := client.Services().List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services)
+}
+
+func TestUnknownRoute(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newClient(t)
+
+	_, err := client.Users().List(ctx, nil, nil)
+	assertStatus(t, http.StatusNotFound, err)
+}
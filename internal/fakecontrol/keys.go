@@ -0,0 +1,205 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// defaultKeyExpiry is the expiry of auth keys created without one.
+const defaultKeyExpiry = 90 * 24 * time.Hour
+
+// Key returns the key, OAuth client or federated identity with the given ID.
+// The secret of the key is not included.
+func (s *Server) Key(id string) (tailscale.Key, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return tailscale.Key{}, false
+	}
+	return redactKey(*key), true
+}
+
+// redactKey returns the key without its secret, as it is returned by every
+// request except the one that creates it.
+func redactKey(key tailscale.Key) tailscale.Key {
+	key.Key = ""
+	return key
+}
+
+// createKeyRequest is the union of the requests to create a key, OAuth client
+// or federated identity, distinguished by KeyType.
+type createKeyRequest struct {
+	KeyType          string                    `json:"keyType"`
+	Capabilities     tailscale.KeyCapabilities `json:"capabilities"`
+	ExpirySeconds    int64                     `json:"expirySeconds"`
+	Description      string                    `json:"description"`
+	Scopes           []string                  `json:"scopes"`
+	Tags             []string                  `json:"tags"`
+	Audience         string                    `json:"audience"`
+	Issuer           string                    `json:"issuer"`
+	Subject          string                    `json:"subject"`
+	CustomClaimRules map[string]string         `json:"customClaimRules"`
+}
+
+func (s *Server) registerKeyHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/keys", s.listKeys)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/keys", s.createKey)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/keys/{id}", s.withKey(s.getKey))
+	mux.HandleFunc("PUT /api/v2/tailnet/{tailnet}/keys/{id}", s.withKey(s.setKey))
+	mux.HandleFunc("DELETE /api/v2/tailnet/{tailnet}/keys/{id}", s.withKey(s.deleteKey))
+	mux.HandleFunc("POST /api/v2/oauth/token", s.createToken)
+	mux.HandleFunc("POST /api/v2/oauth/token-exchange", s.createToken)
+}
+
+// withKey looks up the key in the request path, returning a not found error
+// if it does not exist.
+func (s *Server) withKey(h func(http.ResponseWriter, *http.Request, *tailscale.Key)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.keys[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "key not found")
+			return
+		}
+		h(w, r, key)
+	}
+}
+
+func (s *Server) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys := []tailscale.Key{}
+	for _, key := range s.keys {
+		keys = append(keys, redactKey(*key))
+	}
+	slices.SortFunc(keys, func(a, b tailscale.Key) int { return compareIDs(a.ID, b.ID) })
+	writeJSON(w, map[string][]tailscale.Key{"keys": keys})
+}
+
+func (s *Server) createKey(w http.ResponseWriter, r *http.Request) {
+	var req createKeyRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	now := s.now()
+	key := &tailscale.Key{
+		KeyType:     req.KeyType,
+		Description: req.Description,
+		Created:     now,
+		Updated:     now,
+		UserID:      "u1CNTRL",
+	}
+	switch req.KeyType {
+	case "", "auth":
+		if !validTags(w, req.Capabilities.Devices.Create.Tags) {
+			return
+		}
+		seconds := int64(defaultKeyExpiry / time.Second)
+		if req.ExpirySeconds > 0 {
+			seconds = req.ExpirySeconds
+		}
+		// The API returns the expiry as a number of seconds, which the client
+		// decodes as a [time.Duration] without converting it.
+		expirySeconds := time.Duration(seconds)
+		key.ID = s.newID("k")
+		key.KeyType = "auth"
+		key.Key = "tskey-auth-" + key.ID + "-secret"
+		key.Capabilities = req.Capabilities
+		key.ExpirySeconds = &expirySeconds
+		key.Expires = now.Add(time.Duration(seconds) * time.Second)
+	case "client", "federated":
+		if len(req.Scopes) == 0 {
+			writeError(w, http.StatusBadRequest, "at least one scope is required")
+			return
+		}
+		if !validTags(w, req.Tags) {
+			return
+		}
+		key.ID = s.newID("k")
+		key.Scopes = req.Scopes
+		key.Tags = req.Tags
+		if req.KeyType == "client" {
+			key.Key = "tskey-client-" + key.ID + "-secret"
+			break
+		}
+		if req.Issuer == "" || req.Subject == "" {
+			writeError(w, http.StatusBadRequest, "issuer and subject are required")
+			return
+		}
+		key.Audience = req.Audience
+		if key.Audience == "" {
+			key.Audience = "api.tailscale.com/" + key.ID
+		}
+		key.Issuer = req.Issuer
+		key.Subject = req.Subject
+		key.CustomClaimRules = req.CustomClaimRules
+	default:
+		writeError(w, http.StatusBadRequest, "unknown key type %q", req.KeyType)
+		return
+	}
+
+	s.keys[key.ID] = key
+	writeJSON(w, key)
+}
+
+func (s *Server) getKey(w http.ResponseWriter, r *http.Request, key *tailscale.Key) {
+	writeJSON(w, redactKey(*key))
+}
+
+func (s *Server) setKey(w http.ResponseWriter, r *http.Request, key *tailscale.Key) {
+	var req createKeyRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.KeyType != key.KeyType {
+		writeError(w, http.StatusBadRequest, "cannot change key type from %q to %q", key.KeyType, req.KeyType)
+		return
+	}
+	if key.KeyType == "auth" {
+		writeError(w, http.StatusBadRequest, "auth keys cannot be updated")
+		return
+	}
+	if !validTags(w, req.Tags) {
+		return
+	}
+
+	key.Description = req.Description
+	key.Scopes = req.Scopes
+	key.Tags = req.Tags
+	if key.KeyType == "federated" {
+		if req.Audience != "" {
+			key.Audience = req.Audience
+		}
+		key.Issuer = req.Issuer
+		key.Subject = req.Subject
+		key.CustomClaimRules = req.CustomClaimRules
+	}
+	key.Updated = s.now()
+	writeJSON(w, redactKey(*key))
+}
+
+func (s *Server) deleteKey(w http.ResponseWriter, r *http.Request, key *tailscale.Key) {
+	delete(s.keys, key.ID)
+	w.WriteHeader(http.StatusOK)
+}
+
+// createToken issues an access token for any OAuth client or federated
+// identity, so that the provider can be configured with either.
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %s", err)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"access_token": "tskey-api-" + s.newID("t") + "-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
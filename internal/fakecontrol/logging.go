@@ -0,0 +1,117 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"net/http"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// awsAccountID is the ID of the AWS account that streams logs to S3.
+const awsAccountID = "123456789012"
+
+// LogstreamConfiguration returns the logstream configuration for the given
+// log type, if it is set.
+func (s *Server) LogstreamConfiguration(logType tailscale.LogType) (tailscale.LogstreamConfiguration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, ok := s.logstreams[logType]
+	return config, ok
+}
+
+func (s *Server) registerLoggingHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/logging/{logType}/stream", s.withLogType(s.getLogstream))
+	mux.HandleFunc("PUT /api/v2/tailnet/{tailnet}/logging/{logType}/stream", s.withLogType(s.setLogstream))
+	mux.HandleFunc("DELETE /api/v2/tailnet/{tailnet}/logging/{logType}/stream", s.withLogType(s.deleteLogstream))
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/aws-external-id", s.createAWSExternalID)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/aws-external-id/{id}/validate-aws-trust-policy", s.validateAWSTrustPolicy)
+}
+
+// withLogType validates the log type in the request path.
+func (s *Server) withLogType(h func(http.ResponseWriter, *http.Request, tailscale.LogType)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logType := tailscale.LogType(r.PathValue("logType"))
+		if logType != tailscale.LogTypeConfig && logType != tailscale.LogTypeNetwork {
+			writeError(w, http.StatusBadRequest, "unknown log type %q", logType)
+			return
+		}
+		h(w, r, logType)
+	}
+}
+
+func (s *Server) getLogstream(w http.ResponseWriter, r *http.Request, logType tailscale.LogType) {
+	config, ok := s.logstreams[logType]
+	if !ok {
+		writeError(w, http.StatusNotFound, "logstream configuration not found")
+		return
+	}
+	writeJSON(w, config)
+}
+
+func (s *Server) setLogstream(w http.ResponseWriter, r *http.Request, logType tailscale.LogType) {
+	var req tailscale.SetLogstreamConfigurationRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.DestinationType == "" {
+		writeError(w, http.StatusBadRequest, "destination type is required")
+		return
+	}
+
+	// The token and S3 secret access key are never returned by the API.
+	s.logstreams[logType] = tailscale.LogstreamConfiguration{
+		LogType:              logType,
+		DestinationType:      req.DestinationType,
+		URL:                  req.URL,
+		User:                 req.User,
+		UploadPeriodMinutes:  req.UploadPeriodMinutes,
+		CompressionFormat:    req.CompressionFormat,
+		S3Bucket:             req.S3Bucket,
+		S3Region:             req.S3Region,
+		S3KeyPrefix:          req.S3KeyPrefix,
+		S3AuthenticationType: req.S3AuthenticationType,
+		S3AccessKeyID:        req.S3AccessKeyID,
+		S3RoleARN:            req.S3RoleARN,
+		S3ExternalID:         req.S3ExternalID,
+		GCSBucket:            req.GCSBucket,
+		GCSKeyPrefix:         req.GCSKeyPrefix,
+		GCSScopes:            req.GCSScopes,
+		GCSCredentials:       req.GCSCredentials,
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteLogstream(w http.ResponseWriter, r *http.Request, logType tailscale.LogType) {
+	delete(s.logstreams, logType)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) createAWSExternalID(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reusable bool `json:"reusable"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	// Reusable external IDs are returned until they are used by a logstream
+	// configuration, which the fake does not track.
+	if !req.Reusable || s.awsExternalID == nil {
+		s.awsExternalID = &tailscale.AWSExternalID{
+			ExternalID:            s.newID("aws-external-id-"),
+			TailscaleAWSAccountID: awsAccountID,
+		}
+	}
+	writeJSON(w, s.awsExternalID)
+}
+
+func (s *Server) validateAWSTrustPolicy(w http.ResponseWriter, r *http.Request) {
+	if s.awsExternalID == nil || r.PathValue("id") != s.awsExternalID.ExternalID {
+		writeError(w, http.StatusNotFound, "AWS external ID not found")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tailscale/hujson"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// DefaultPolicy is the policy file of a new tailnet.
+const DefaultPolicy = `// Example/default ACLs for unrestricted connections.
+{
+	// Define grants that govern access for users, groups, autogroups, tags,
+	// Tailscale IP addresses, and subnet ranges.
+	"grants": [
+		// Allow all connections.
+		{"src": ["*"], "dst": ["*"], "ip": ["*"]},
+	],
+
+	// Define users and devices that can use Tailscale SSH.
+	"ssh": [
+		// Allow all users to SSH into their own devices in check mode.
+		{
+			"action": "check",
+			"src":    ["autogroup:member"],
+			"dst":    ["autogroup:self"],
+			"users":  ["autogroup:nonroot", "root"],
+		},
+	],
+}
+`
+
+// defaultPolicyETag is the ETag that matches the policy file only if it has
+// never been changed from [DefaultPolicy].
+const defaultPolicyETag = "ts-default"
+
+// Policy returns the HuJSON policy file of the tailnet and its ETag.
+func (s *Server) Policy() (policy, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.policy, policyETag(s.policy)
+}
+
+// SetPolicy replaces the policy file of the tailnet, as if it had been edited
+// outside of Terraform.
+func (s *Server) SetPolicy(policy string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policy = policy
+	s.policyModified = true
+}
+
+// policyETag returns the ETag of a policy file, which changes whenever its
+// contents change.
+func policyETag(policy string) string {
+	sum := sha256.Sum256([]byte(policy))
+	return hex.EncodeToString(sum[:])
+}
+
+// parsePolicy checks that policy is a valid policy file, returning it as an
+// [tailscale.ACL].
+func parsePolicy(policy string) (*tailscale.ACL, error) {
+	b, err := hujson.Standardize([]byte(policy))
+	if err != nil {
+		return nil, fmt.Errorf("parsing policy file: %w", err)
+	}
+
+	var acl tailscale.ACL
+	if err := json.Unmarshal(b, &acl); err != nil {
+		return nil, fmt.Errorf("parsing policy file: %w", err)
+	}
+	return &acl, nil
+}
+
+func (s *Server) registerPolicyHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/acl", s.getPolicy)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/acl", s.setPolicy)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/acl/validate", s.validatePolicy)
+}
+
+// writePolicy writes the policy file in the format requested by r, either as
+// HuJSON or as standard JSON.
+func (s *Server) writePolicy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("ETag", fmt.Sprintf("%q", policyETag(s.policy)))
+
+	if r.Header.Get("Accept") == "application/hujson" || r.Header.Get("Content-Type") == "application/hujson" {
+		w.Header().Set("Content-Type", "application/hujson")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, s.policy)
+		return
+	}
+
+	acl, err := parsePolicy(s.policy)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	writeJSON(w, acl)
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, r *http.Request) {
+	s.writePolicy(w, r)
+}
+
+func (s *Server) setPolicy(w http.ResponseWriter, r *http.Request) {
+	if ifMatch := strings.Trim(r.Header.Get("If-Match"), `"`); ifMatch != "" {
+		matches := ifMatch == policyETag(s.policy)
+		if ifMatch == defaultPolicyETag {
+			matches = !s.policyModified
+		}
+		if !matches {
+			writeError(w, http.StatusPreconditionFailed, "precondition failed, invalid old hash")
+			return
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading request body: %s", err)
+		return
+	}
+
+	// An empty policy file resets the policy to its default.
+	policy := string(body)
+	if strings.TrimSpace(policy) == "" {
+		s.policy = DefaultPolicy
+		s.policyModified = false
+		s.writePolicy(w, r)
+		return
+	}
+
+	if _, err := parsePolicy(policy); err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.policy = policy
+	s.policyModified = true
+	s.writePolicy(w, r)
+}
+
+func (s *Server) validatePolicy(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading request body: %s", err)
+		return
+	}
+
+	// A list of tests is validated against the current policy file, which is
+	// always valid.
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if _, err := parsePolicy(string(body)); err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"net/http"
+	"slices"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// PostureIntegration returns the posture integration with the given ID.
+func (s *Server) PostureIntegration(id string) (tailscale.PostureIntegration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intg, ok := s.postureIntegrations[id]
+	if !ok {
+		return tailscale.PostureIntegration{}, false
+	}
+	return *intg, true
+}
+
+func (s *Server) registerPostureHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/posture/integrations", s.listPostureIntegrations)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/posture/integrations", s.createPostureIntegration)
+	mux.HandleFunc("GET /api/v2/posture/integrations/{id}", s.withPostureIntegration(s.getPostureIntegration))
+	mux.HandleFunc("PATCH /api/v2/posture/integrations/{id}", s.withPostureIntegration(s.updatePostureIntegration))
+	mux.HandleFunc("DELETE /api/v2/posture/integrations/{id}", s.withPostureIntegration(s.deletePostureIntegration))
+}
+
+// withPostureIntegration looks up the posture integration in the request
+// path, returning a not found error if it does not exist.
+func (s *Server) withPostureIntegration(h func(http.ResponseWriter, *http.Request, *tailscale.PostureIntegration)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		intg, ok := s.postureIntegrations[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "posture integration not found")
+			return
+		}
+		h(w, r, intg)
+	}
+}
+
+func (s *Server) listPostureIntegrations(w http.ResponseWriter, r *http.Request) {
+	integrations := []tailscale.PostureIntegration{}
+	for _, intg := range s.postureIntegrations {
+		integrations = append(integrations, *intg)
+	}
+	slices.SortFunc(integrations, func(a, b tailscale.PostureIntegration) int { return compareIDs(a.ID, b.ID) })
+	writeJSON(w, map[string][]tailscale.PostureIntegration{"integrations": integrations})
+}
+
+func (s *Server) createPostureIntegration(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.CreatePostureIntegrationRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Provider == "" {
+		writeError(w, http.StatusBadRequest, "provider is required")
+		return
+	}
+	for _, intg := range s.postureIntegrations {
+		if intg.Provider == req.Provider {
+			writeError(w, http.StatusConflict, "an integration with provider %q already exists", req.Provider)
+			return
+		}
+	}
+
+	// The client secret is never returned by the API.
+	intg := &tailscale.PostureIntegration{
+		ID:       s.newID("pi"),
+		Provider: req.Provider,
+		CloudID:  req.CloudID,
+		ClientID: req.ClientID,
+		TenantID: req.TenantID,
+	}
+	s.postureIntegrations[intg.ID] = intg
+	writeJSON(w, intg)
+}
+
+func (s *Server) getPostureIntegration(w http.ResponseWriter, r *http.Request, intg *tailscale.PostureIntegration) {
+	writeJSON(w, intg)
+}
+
+func (s *Server) updatePostureIntegration(w http.ResponseWriter, r *http.Request, intg *tailscale.PostureIntegration) {
+	var req tailscale.UpdatePostureIntegrationRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	intg.CloudID = req.CloudID
+	intg.ClientID = req.ClientID
+	intg.TenantID = req.TenantID
+	writeJSON(w, intg)
+}
+
+func (s *Server) deletePostureIntegration(w http.ResponseWriter, r *http.Request, intg *tailscale.PostureIntegration) {
+	delete(s.postureIntegrations, intg.ID)
+	w.WriteHeader(http.StatusOK)
+}
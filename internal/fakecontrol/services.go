@@ -0,0 +1,98 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// Service returns the Service with the given name, e.g. "svc:web".
+func (s *Server) Service(name string) (tailscale.Service, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[name]
+	if !ok {
+		return tailscale.Service{}, false
+	}
+	return *svc, true
+}
+
+func (s *Server) registerServiceHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/vip-services", s.listServices)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/vip-services/{name}", s.getService)
+	mux.HandleFunc("PUT /api/v2/tailnet/{tailnet}/vip-services/{name}", s.setService)
+	mux.HandleFunc("DELETE /api/v2/tailnet/{tailnet}/vip-services/{name}", s.deleteService)
+}
+
+func (s *Server) listServices(w http.ResponseWriter, r *http.Request) {
+	services := []tailscale.Service{}
+	for _, svc := range s.services {
+		services = append(services, *svc)
+	}
+	slices.SortFunc(services, func(a, b tailscale.Service) int { return strings.Compare(a.Name, b.Name) })
+	writeJSON(w, map[string][]tailscale.Service{"vipServices": services})
+}
+
+func (s *Server) getService(w http.ResponseWriter, r *http.Request) {
+	svc, ok := s.services[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "service not found")
+		return
+	}
+	writeJSON(w, svc)
+}
+
+func (s *Server) setService(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !strings.HasPrefix(name, "svc:") || len(name) == len("svc:") {
+		writeError(w, http.StatusBadRequest, "service name %q must start with \"svc:\"", name)
+		return
+	}
+
+	var req tailscale.Service
+	if !readJSON(w, r, &req) || !validTags(w, req.Tags) {
+		return
+	}
+	for _, port := range req.Ports {
+		if !strings.HasPrefix(port, "tcp:") && !strings.HasPrefix(port, "udp:") && port != "do-not-validate" {
+			writeError(w, http.StatusBadRequest, "invalid port %q", port)
+			return
+		}
+	}
+	req.Name = name
+
+	// Addresses are allocated when the Service is created, unless they are
+	// requested, and kept when it is updated without them.
+	if len(req.Addrs) == 0 {
+		if existing, ok := s.services[name]; ok {
+			req.Addrs = existing.Addrs
+		} else {
+			s.nextID++
+			n := s.nextID
+			req.Addrs = []string{
+				fmt.Sprintf("100.100.0.%d", n),
+				fmt.Sprintf("fd7a:115c:a1e0::ffff:%x", n),
+			}
+		}
+	}
+
+	s.services[name] = &req
+	writeJSON(w, req)
+}
+
+func (s *Server) deleteService(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.services[name]; !ok {
+		writeError(w, http.StatusNotFound, "service not found")
+		return
+	}
+	delete(s.services, name)
+	w.WriteHeader(http.StatusOK)
+}
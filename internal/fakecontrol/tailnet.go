@@ -0,0 +1,119 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"net/http"
+	"net/mail"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// Contacts returns the contacts of the tailnet.
+func (s *Server) Contacts() tailscale.Contacts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.contacts
+}
+
+// TailnetSettings returns the settings of the tailnet.
+func (s *Server) TailnetSettings() tailscale.TailnetSettings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.settings
+}
+
+func (s *Server) registerTailnetHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/contacts", s.getContacts)
+	mux.HandleFunc("PATCH /api/v2/tailnet/{tailnet}/contacts/{type}", s.updateContact)
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/settings", s.getTailnetSettings)
+	mux.HandleFunc("PATCH /api/v2/tailnet/{tailnet}/settings", s.updateTailnetSettings)
+}
+
+func (s *Server) getContacts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.contacts)
+}
+
+func (s *Server) updateContact(w http.ResponseWriter, r *http.Request) {
+	var contact *tailscale.Contact
+	switch tailscale.ContactType(r.PathValue("type")) {
+	case tailscale.ContactAccount:
+		contact = &s.contacts.Account
+	case tailscale.ContactSupport:
+		contact = &s.contacts.Support
+	case tailscale.ContactSecurity:
+		contact = &s.contacts.Security
+	default:
+		writeError(w, http.StatusBadRequest, "unknown contact type %q", r.PathValue("type"))
+		return
+	}
+
+	var req tailscale.UpdateContactRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Email == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if _, err := mail.ParseAddress(*req.Email); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid email address %q", *req.Email)
+		return
+	}
+
+	// Changed email addresses must be verified before they receive email.
+	if contact.Email != *req.Email {
+		contact.Email = *req.Email
+		contact.NeedsVerification = true
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getTailnetSettings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.settings)
+}
+
+func (s *Server) updateTailnetSettings(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.UpdateTailnetSettingsRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	settings := s.settings
+	setIfNotNil(&settings.ACLsExternallyManagedOn, req.ACLsExternallyManagedOn)
+	setIfNotNil(&settings.ACLsExternalLink, req.ACLsExternalLink)
+	setIfNotNil(&settings.DevicesApprovalOn, req.DevicesApprovalOn)
+	setIfNotNil(&settings.DevicesAutoUpdatesOn, req.DevicesAutoUpdatesOn)
+	setIfNotNil(&settings.DevicesKeyDurationDays, req.DevicesKeyDurationDays)
+	setIfNotNil(&settings.UsersApprovalOn, req.UsersApprovalOn)
+	setIfNotNil(&settings.UsersRoleAllowedToJoinExternalTailnets, req.UsersRoleAllowedToJoinExternalTailnets)
+	setIfNotNil(&settings.NetworkFlowLoggingOn, req.NetworkFlowLoggingOn)
+	setIfNotNil(&settings.RegionalRoutingOn, req.RegionalRoutingOn)
+	setIfNotNil(&settings.PostureIdentityCollectionOn, req.PostureIdentityCollectionOn)
+	setIfNotNil(&settings.HTTPSEnabled, req.HTTPSEnabled)
+
+	if settings.DevicesKeyDurationDays < 1 || settings.DevicesKeyDurationDays > 180 {
+		writeError(w, http.StatusBadRequest, "devicesKeyDurationDays must be between 1 and 180")
+		return
+	}
+	switch settings.UsersRoleAllowedToJoinExternalTailnets {
+	case tailscale.RoleAllowedToJoinExternalTailnetsNone, tailscale.RoleAllowedToJoinExternalTailnetsAdmin, tailscale.RoleAllowedToJoinExternalTailnetsMember:
+	default:
+		writeError(w, http.StatusBadRequest, "unknown role %q", settings.UsersRoleAllowedToJoinExternalTailnets)
+		return
+	}
+
+	s.settings = settings
+	w.WriteHeader(http.StatusOK)
+}
+
+// setIfNotNil sets *dst to *src if src is not nil, as PATCH requests leave
+// omitted fields unchanged.
+func setIfNotNil[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
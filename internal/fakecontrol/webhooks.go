@@ -0,0 +1,149 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package fakecontrol
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// webhook is a webhook endpoint in the fake tailnet, with the number of
+// events sent to it.
+type webhook struct {
+	tailscale.Webhook
+	secret     string
+	rotations  int
+	testEvents int
+}
+
+// Webhook returns the webhook endpoint with the given ID, including its
+// current secret.
+func (s *Server) Webhook(id string) (tailscale.Webhook, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wh, ok := s.webhooks[id]
+	if !ok {
+		return tailscale.Webhook{}, false
+	}
+	return wh.withSecret(), true
+}
+
+// WebhookTestEvents returns the number of test events sent to the webhook
+// endpoint with the given ID.
+func (s *Server) WebhookTestEvents(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if wh, ok := s.webhooks[id]; ok {
+		return wh.testEvents
+	}
+	return 0
+}
+
+func (s *Server) registerWebhookHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v2/tailnet/{tailnet}/webhooks", s.listWebhooks)
+	mux.HandleFunc("POST /api/v2/tailnet/{tailnet}/webhooks", s.createWebhook)
+	mux.HandleFunc("GET /api/v2/webhooks/{id}", s.withWebhook(s.getWebhook))
+	mux.HandleFunc("PATCH /api/v2/webhooks/{id}", s.withWebhook(s.updateWebhook))
+	mux.HandleFunc("DELETE /api/v2/webhooks/{id}", s.withWebhook(s.deleteWebhook))
+	mux.HandleFunc("POST /api/v2/webhooks/{id}/test", s.withWebhook(s.testWebhook))
+	mux.HandleFunc("POST /api/v2/webhooks/{id}/rotate", s.withWebhook(s.rotateWebhookSecret))
+}
+
+// withWebhook looks up the webhook endpoint in the request path, returning a
+// not found error if it does not exist.
+func (s *Server) withWebhook(h func(http.ResponseWriter, *http.Request, *webhook)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wh, ok := s.webhooks[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "webhook not found")
+			return
+		}
+		h(w, r, wh)
+	}
+}
+
+// withSecret returns the webhook with its current secret, as it is returned
+// when the webhook is created or its secret is rotated.
+func (wh *webhook) withSecret() tailscale.Webhook {
+	out := wh.Webhook
+	secret := wh.secret
+	out.Secret = &secret
+	return out
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks := []tailscale.Webhook{}
+	for _, wh := range s.webhooks {
+		webhooks = append(webhooks, wh.Webhook)
+	}
+	slices.SortFunc(webhooks, func(a, b tailscale.Webhook) int { return compareIDs(a.EndpointID, b.EndpointID) })
+	writeJSON(w, map[string][]tailscale.Webhook{"webhooks": webhooks})
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req tailscale.CreateWebhookRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if u, err := url.Parse(req.EndpointURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		writeError(w, http.StatusBadRequest, "endpoint URL %q must be an https URL", req.EndpointURL)
+		return
+	}
+
+	now := s.now()
+	wh := &webhook{
+		Webhook: tailscale.Webhook{
+			EndpointID:       s.newID("w"),
+			EndpointURL:      req.EndpointURL,
+			ProviderType:     req.ProviderType,
+			CreatorLoginName: CreatorLoginName,
+			Created:          now,
+			LastModified:     now,
+			Subscriptions:    req.Subscriptions,
+		},
+	}
+	wh.secret = fmt.Sprintf("tskey-webhook-%s-secret%d", wh.EndpointID, wh.rotations)
+	s.webhooks[wh.EndpointID] = wh
+	writeJSON(w, wh.withSecret())
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request, wh *webhook) {
+	writeJSON(w, wh.Webhook)
+}
+
+func (s *Server) updateWebhook(w http.ResponseWriter, r *http.Request, wh *webhook) {
+	var req struct {
+		Subscriptions []tailscale.WebhookSubscriptionType `json:"subscriptions"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	wh.Subscriptions = req.Subscriptions
+	wh.LastModified = s.now()
+	writeJSON(w, wh.Webhook)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request, wh *webhook) {
+	delete(s.webhooks, wh.EndpointID)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) testWebhook(w http.ResponseWriter, r *http.Request, wh *webhook) {
+	wh.testEvents++
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) rotateWebhookSecret(w http.ResponseWriter, r *http.Request, wh *webhook) {
+	wh.rotations++
+	wh.secret = fmt.Sprintf("tskey-webhook-%s-secret%d", wh.EndpointID, wh.rotations)
+	wh.LastModified = s.now()
+	writeJSON(w, wh.withSecret())
+}
@@ -0,0 +1,103 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+// ActionBase is a base struct for all Tailscale actions.
+//
+// All actions should extend this struct, then the authenticated [Client] will
+// be available in their Invoke method.
+type ActionBase struct {
+	Client *tailscale.Client
+
+	providerData *providerData
+}
+
+// Configure attaches the client to the action, so it can be used in the
+// Invoke method.
+func (a *ActionBase) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf(
+				"Expected *providerData, got: %T. Please report this error at https://github.com/tailscale/tailscale.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	a.Client = data.Client
+	a.providerData = data
+}
+
+// ClientForTailnet returns the client for the action's `tailnet` attribute,
+// or the provider's client if it is not set.
+func (a *ActionBase) ClientForTailnet(tailnet types.String) *tailscale.Client {
+	return clientForTailnet(a.providerData, tailnet)
+}
+
+// tailnetActionAttribute returns the optional `tailnet` attribute that is
+// shared by all actions.
+func tailnetActionAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: "The tailnet ID to act in. Defaults to the tailnet configured on the provider. The tailnet must be accessible with the credentials passed to the provider.",
+	}
+}
+
+// deviceActionModel is the configuration of actions on a single device.
+type deviceActionModel struct {
+	Tailnet  types.String `tfsdk:"tailnet"`
+	DeviceID types.String `tfsdk:"device_id"`
+}
+
+// deviceActionSchema returns the schema of actions on a single device, with
+// the given description.
+func deviceActionSchema(description string) schema.Schema {
+	return schema.Schema{
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetActionAttribute(),
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the device, either the node ID (preferred) or the legacy ID.",
+			},
+		},
+	}
+}
+
+// webhookActionModel is the configuration of actions on a webhook endpoint.
+type webhookActionModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+	ID      types.String `tfsdk:"id"`
+}
+
+// webhookActionSchema returns the schema of actions on a webhook endpoint,
+// with the given description.
+func webhookActionSchema(description string) schema.Schema {
+	return schema.Schema{
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetActionAttribute(),
+			"id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the webhook endpoint, e.g. the `id` of a tailscale_webhook resource.",
+			},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ action.Action              = &deviceAuthorizeAction{}
+	_ action.ActionWithConfigure = &deviceAuthorizeAction{}
+)
+
+type deviceAuthorizeActionModel struct {
+	Tailnet    types.String `tfsdk:"tailnet"`
+	DeviceID   types.String `tfsdk:"device_id"`
+	Authorized types.Bool   `tfsdk:"authorized"`
+}
+
+// NewDeviceAuthorizeAction returns a new device authorize action.
+func NewDeviceAuthorizeAction() action.Action {
+	return &deviceAuthorizeAction{}
+}
+
+type deviceAuthorizeAction struct {
+	ActionBase
+}
+
+// Metadata defines the action name as it appears in Terraform configurations.
+func (a *deviceAuthorizeAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_authorize"
+}
+
+// Schema defines a schema describing what fields can be defined in the action.
+func (a *deviceAuthorizeAction) Schema(ctx context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = deviceActionSchema("The device_authorize action approves a device, or revokes its approval, without managing its authorization in state as the tailscale_device_authorization resource does. See https://tailscale.com/kb/1099/device-authorization for more information.")
+	resp.Schema.Attributes["authorized"] = schema.BoolAttribute{
+		Optional:    true,
+		Description: "Whether the device should be authorized. Defaults to `true`.",
+	}
+}
+
+// Invoke sets the authorization of the device.
+func (a *deviceAuthorizeAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config deviceAuthorizeActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := config.DeviceID.ValueString()
+	authorized := config.Authorized.IsNull() || config.Authorized.ValueBool()
+	if err := a.ClientForTailnet(config.Tailnet).Devices().SetAuthorized(ctx, deviceID, authorized); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to update device authorization",
+			"Failed to update authorization for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	if authorized {
+		resp.SendProgress(action.InvokeProgressEvent{Message: "Authorized device " + deviceID})
+	} else {
+		resp.SendProgress(action.InvokeProgressEvent{Message: "Deauthorized device " + deviceID})
+	}
+}
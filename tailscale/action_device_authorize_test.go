@@ -0,0 +1,43 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDeviceAuthorizeAction(t *testing.T) {
+	runActionTests(t, NewDeviceAuthorizeAction, []actionTestCase{
+		{
+			name:         "authorizes-by-default",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusOK},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/device/n123/authorized",
+			expectBody:   `{"authorized": true}`,
+		},
+		{
+			name: "deauthorizes",
+			config: map[string]tftypes.Value{
+				"device_id":  tftypes.NewValue(tftypes.String, "n123"),
+				"authorized": tftypes.NewValue(tftypes.Bool, false),
+			},
+			response:     TestResponse{Code: http.StatusOK},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/device/n123/authorized",
+			expectBody:   `{"authorized": false}`,
+		},
+		{
+			name:         "not-found",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusNotFound, Body: map[string]string{"message": "device not found"}},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/device/n123/authorized",
+			expectError:  "device not found",
+		},
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+var (
+	_ action.Action              = &deviceDeleteAction{}
+	_ action.ActionWithConfigure = &deviceDeleteAction{}
+)
+
+// NewDeviceDeleteAction returns a new device delete action.
+func NewDeviceDeleteAction() action.Action {
+	return &deviceDeleteAction{}
+}
+
+type deviceDeleteAction struct {
+	ActionBase
+}
+
+// Metadata defines the action name as it appears in Terraform configurations.
+func (a *deviceDeleteAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_delete"
+}
+
+// Schema defines a schema describing what fields can be defined in the action.
+func (a *deviceDeleteAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = deviceActionSchema("The device_delete action removes a device from the tailnet, such as a stale ephemeral node. Devices that have already been removed are ignored. The device must re-authenticate to rejoin the tailnet.")
+}
+
+// Invoke deletes the device.
+func (a *deviceDeleteAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config deviceActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := config.DeviceID.ValueString()
+	err := a.ClientForTailnet(config.Tailnet).Devices().Delete(ctx, deviceID)
+	if tailscale.IsNotFound(err) {
+		resp.SendProgress(action.InvokeProgressEvent{Message: "Device " + deviceID + " was already deleted"})
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to delete device",
+			"Failed to delete device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Deleted device " + deviceID})
+}
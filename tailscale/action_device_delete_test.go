@@ -0,0 +1,38 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDeviceDeleteAction(t *testing.T) {
+	runActionTests(t, NewDeviceDeleteAction, []actionTestCase{
+		{
+			name:         "deletes-device",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusOK},
+			expectMethod: http.MethodDelete,
+			expectPath:   "/api/v2/device/n123",
+		},
+		{
+			name:         "already-deleted",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusNotFound, Body: map[string]string{"message": "not found"}},
+			expectMethod: http.MethodDelete,
+			expectPath:   "/api/v2/device/n123",
+		},
+		{
+			name:         "forbidden",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusForbidden, Body: map[string]string{"message": "access denied"}},
+			expectMethod: http.MethodDelete,
+			expectPath:   "/api/v2/device/n123",
+			expectError:  "access denied",
+		},
+	})
+}
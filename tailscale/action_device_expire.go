@@ -0,0 +1,55 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+var (
+	_ action.Action              = &deviceExpireAction{}
+	_ action.ActionWithConfigure = &deviceExpireAction{}
+)
+
+// NewDeviceExpireAction returns a new device expire action.
+func NewDeviceExpireAction() action.Action {
+	return &deviceExpireAction{}
+}
+
+type deviceExpireAction struct {
+	ActionBase
+}
+
+// Metadata defines the action name as it appears in Terraform configurations.
+func (a *deviceExpireAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_expire"
+}
+
+// Schema defines a schema describing what fields can be defined in the action.
+func (a *deviceExpireAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = deviceActionSchema("The device_expire action expires the key of a device immediately, so that it must be re-authenticated before it can reconnect to the tailnet. See https://tailscale.com/kb/1028/key-expiry for more information.")
+}
+
+// Invoke expires the key of the device.
+func (a *deviceExpireAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config deviceActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := config.DeviceID.ValueString()
+	if err := doAPIRequest(ctx, a.ClientForTailnet(config.Tailnet), http.MethodPost, "device", deviceID, "expire"); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to expire device",
+			"Failed to expire key of device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Expired key of device " + deviceID})
+}
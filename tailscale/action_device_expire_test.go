@@ -0,0 +1,31 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDeviceExpireAction(t *testing.T) {
+	runActionTests(t, NewDeviceExpireAction, []actionTestCase{
+		{
+			name:         "expires-device",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusOK},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/device/n123/expire",
+		},
+		{
+			name:         "not-found",
+			config:       map[string]tftypes.Value{"device_id": tftypes.NewValue(tftypes.String, "n123")},
+			response:     TestResponse{Code: http.StatusNotFound, Body: map[string]string{"message": "device not found"}},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/device/n123/expire",
+			expectError:  "device not found",
+		},
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+var (
+	_ action.Action              = &webhookRotateSecretAction{}
+	_ action.ActionWithConfigure = &webhookRotateSecretAction{}
+)
+
+const actionWebhookRotateSecretDescription = `The webhook_rotate_secret action rotates the secret used to sign the events sent to a webhook endpoint, for example after the secret has been exposed. See https://tailscale.com/kb/1213/webhooks for more information.
+
+The existing secret is invalidated immediately. Actions cannot return values, so the new secret is not available to Terraform, and the ` + "`secret`" + ` attribute of the corresponding tailscale_webhook resource is not updated.`
+
+// NewWebhookRotateSecretAction returns a new webhook rotate secret action.
+func NewWebhookRotateSecretAction() action.Action {
+	return &webhookRotateSecretAction{}
+}
+
+type webhookRotateSecretAction struct {
+	ActionBase
+}
+
+// Metadata defines the action name as it appears in Terraform configurations.
+func (a *webhookRotateSecretAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhook_rotate_secret"
+}
+
+// Schema defines a schema describing what fields can be defined in the action.
+func (a *webhookRotateSecretAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = webhookActionSchema(actionWebhookRotateSecretDescription)
+}
+
+// Invoke rotates the secret of the webhook endpoint.
+func (a *webhookRotateSecretAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config webhookActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	endpointID := config.ID.ValueString()
+	if _, err := a.ClientForTailnet(config.Tailnet).Webhooks().RotateSecret(ctx, endpointID); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to rotate webhook secret",
+			"Failed to rotate secret of webhook with ID "+endpointID+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Rotated secret of webhook " + endpointID})
+}
@@ -0,0 +1,34 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWebhookRotateSecretAction(t *testing.T) {
+	runActionTests(t, NewWebhookRotateSecretAction, []actionTestCase{
+		{
+			name: "rotates-secret",
+			config: map[string]tftypes.Value{
+				"tailnet": tftypes.NewValue(tftypes.String, "example.com"),
+				"id":      tftypes.NewValue(tftypes.String, "w123"),
+			},
+			response:     TestResponse{Code: http.StatusOK, Body: map[string]string{"endpointId": "w123", "secret": "tskey-webhook-xyz"}},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/webhooks/w123/rotate",
+		},
+		{
+			name:         "not-found",
+			config:       map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "w123")},
+			response:     TestResponse{Code: http.StatusNotFound, Body: map[string]string{"message": "webhook not found"}},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/webhooks/w123/rotate",
+			expectError:  "webhook not found",
+		},
+	})
+}
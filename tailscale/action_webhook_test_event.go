@@ -0,0 +1,54 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+)
+
+var (
+	_ action.Action              = &webhookTestEventAction{}
+	_ action.ActionWithConfigure = &webhookTestEventAction{}
+)
+
+// NewWebhookTestEventAction returns a new webhook test event action.
+func NewWebhookTestEventAction() action.Action {
+	return &webhookTestEventAction{}
+}
+
+type webhookTestEventAction struct {
+	ActionBase
+}
+
+// Metadata defines the action name as it appears in Terraform configurations.
+func (a *webhookTestEventAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhook_test_event"
+}
+
+// Schema defines a schema describing what fields can be defined in the action.
+func (a *webhookTestEventAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = webhookActionSchema("The webhook_test_event action sends a test event to a webhook endpoint, to check that the endpoint receives events. See https://tailscale.com/kb/1213/webhooks for more information.")
+}
+
+// Invoke sends a test event to the webhook endpoint.
+func (a *webhookTestEventAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config webhookActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	endpointID := config.ID.ValueString()
+	if err := a.ClientForTailnet(config.Tailnet).Webhooks().Test(ctx, endpointID); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to send webhook test event",
+			"Failed to send test event to webhook with ID "+endpointID+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{Message: "Sent test event to webhook " + endpointID})
+}
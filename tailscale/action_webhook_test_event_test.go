@@ -0,0 +1,31 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestWebhookTestEventAction(t *testing.T) {
+	runActionTests(t, NewWebhookTestEventAction, []actionTestCase{
+		{
+			name:         "sends-test-event",
+			config:       map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "w123")},
+			response:     TestResponse{Code: http.StatusAccepted},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/webhooks/w123/test",
+		},
+		{
+			name:         "not-found",
+			config:       map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "w123")},
+			response:     TestResponse{Code: http.StatusNotFound, Body: map[string]string{"message": "webhook not found"}},
+			expectMethod: http.MethodPost,
+			expectPath:   "/api/v2/webhooks/w123/test",
+			expectError:  "webhook not found",
+		},
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// doAPIRequest sends a request without a body to an API endpoint that the
+// client does not support yet, such as `device/<id>/expire`. The request is
+// sent with the client's HTTP client and credentials, and errors are returned
+// as [tailscale.APIError] so that they can be handled like those of the client.
+func doAPIRequest(ctx context.Context, client *tailscale.Client, method string, pathElements ...string) error {
+	// Accessing a resource initializes the client, including the HTTP client
+	// that authenticates with OAuth or federated identity credentials.
+	client.Devices()
+
+	uri := client.BaseURL.JoinPath(append([]string{"api", "v2"}, pathElements...)...)
+	req, err := http.NewRequestWithContext(ctx, method, uri.String(), nil)
+	if err != nil {
+		return err
+	}
+	if client.UserAgent != "" {
+		req.Header.Set("User-Agent", client.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+	if client.APIKey != "" {
+		req.SetBasicAuth(client.APIKey, "")
+	}
+
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	apiErr := tailscale.APIError{Message: http.StatusText(resp.StatusCode)}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &apiErr); err != nil {
+			return fmt.Errorf("%s (%d)", http.StatusText(resp.StatusCode), resp.StatusCode)
+		}
+	}
+	apiErr.Status = resp.StatusCode
+	return apiErr
+}
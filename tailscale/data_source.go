@@ -8,15 +8,21 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"tailscale.com/client/tailscale/v2"
 )
 
 // DataSourceBase is a base struct for all Tailscale data sources.
 //
 // All data sources should extend this struct, then the authenticated [Client]
-// will be available in their [datasource.DataSource.Read] method.
+// will be available in their [datasource.DataSource.Read] method. Data sources
+// that read from a tailnet other than the provider's should use
+// [DataSourceBase.ClientForTailnet] instead.
 type DataSourceBase struct {
 	Client *tailscale.Client
+
+	providerData *providerData
 }
 
 // Configure attaches the client to the data source, so it can be used in the
@@ -26,16 +32,32 @@ func (d *DataSourceBase) Configure(ctx context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*tailscale.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
 			fmt.Sprintf(
-				"Expected *tailscale.Client, got: %T. Please report this error at https://github.com/tailscale/tailscale.",
+				"Expected *providerData, got: %T. Please report this error at https://github.com/tailscale/tailscale.",
 				req.ProviderData),
 		)
 		return
 	}
 
-	d.Client = client
+	d.Client = data.Client
+	d.providerData = data
+}
+
+// ClientForTailnet returns the client for the data source's `tailnet`
+// attribute, or the provider's client if it is not set.
+func (d *DataSourceBase) ClientForTailnet(tailnet types.String) *tailscale.Client {
+	return clientForTailnet(d.providerData, tailnet)
+}
+
+// tailnetDataSourceAttribute returns the optional `tailnet` attribute that is
+// shared by all data sources.
+func tailnetDataSourceAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: "The tailnet ID to read from. Defaults to the tailnet configured on the provider. The tailnet must be accessible with the credentials passed to the provider.",
+	}
 }
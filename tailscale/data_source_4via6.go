@@ -5,6 +5,8 @@ package tailscale
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"net/netip"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
@@ -78,18 +80,7 @@ func (d dataSource4via6) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	site := uint32(data.Site.ValueInt32())
-
-	cidr, err := netip.ParsePrefix(data.CIDR.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid CIDR",
-			"The provided CIDR "+data.CIDR.ValueString()+" is invalid: "+err.Error(),
-		)
-		return
-	}
-
-	via, err := tsaddr.MapVia(site, cidr)
+	via, err := map4Via6(uint32(data.Site.ValueInt32()), data.CIDR.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Calculation Error",
@@ -104,3 +95,38 @@ func (d dataSource4via6) Read(ctx context.Context, req datasource.ReadRequest, r
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// map4Via6 maps the given site ID and IPv4 CIDR to its 4via6 IPv6 prefix.
+func map4Via6(site uint32, cidr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+
+	return tsaddr.MapVia(site, prefix)
+}
+
+// unmap4Via6 reverses [map4Via6], returning the site ID and IPv4 CIDR embedded
+// in the given 4via6 IPv6 prefix or address.
+func unmap4Via6(via string) (uint32, netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(via)
+	if err != nil {
+		addr, addrErr := netip.ParseAddr(via)
+		if addrErr != nil {
+			return 0, netip.Prefix{}, fmt.Errorf("invalid IPv6 address or prefix %q", via)
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+
+	if !prefix.Addr().Is6() || !tsaddr.TailscaleViaRange().Contains(prefix.Addr()) {
+		return 0, netip.Prefix{}, fmt.Errorf("%q is not within the 4via6 range %s", via, tsaddr.TailscaleViaRange())
+	}
+	if prefix.Bits() < 96 {
+		return 0, netip.Prefix{}, fmt.Errorf("prefix length of %q must be at least /96", via)
+	}
+
+	raw := prefix.Addr().As16()
+	site := binary.BigEndian.Uint32(raw[8:12])
+	cidr := netip.PrefixFrom(tsaddr.UnmapVia(prefix.Addr()), prefix.Bits()-96).Masked()
+	return site, cidr, nil
+}
@@ -5,7 +5,11 @@ package tailscale
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -29,9 +33,32 @@ type aclDataSource struct {
 }
 
 type aclDataSourceModel struct {
-	ID     types.String `tfsdk:"id"`
-	JSON   types.String `tfsdk:"json"`
-	HuJSON types.String `tfsdk:"hujson"`
+	ID            types.String `tfsdk:"id"`
+	JSON          types.String `tfsdk:"json"`
+	HuJSON        types.String `tfsdk:"hujson"`
+	Groups        types.Map    `tfsdk:"groups"`
+	TagOwners     types.Map    `tfsdk:"tag_owners"`
+	Hosts         types.Map    `tfsdk:"hosts"`
+	Postures      types.Map    `tfsdk:"postures"`
+	IPSets        types.Map    `tfsdk:"ipsets"`
+	AutoApprovers types.Object `tfsdk:"auto_approvers"`
+	Tags          types.List   `tfsdk:"tags"`
+	GroupsForUser types.Map    `tfsdk:"groups_for_user"`
+	Tailnet       types.String `tfsdk:"tailnet"`
+}
+
+type aclAutoApproversModel struct {
+	Routes   types.Map  `tfsdk:"routes"`
+	ExitNode types.List `tfsdk:"exit_node"`
+	Services types.Map  `tfsdk:"services"`
+}
+
+// aclAutoApproversAttributeTypes are the attribute types of the
+// auto_approvers object of the acl data source.
+var aclAutoApproversAttributeTypes = map[string]attr.Type{
+	"routes":    types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+	"exit_node": types.ListType{ElemType: types.StringType},
+	"services":  types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
 }
 
 // Metadata defines the data source name as it appears in Terraform configurations.
@@ -44,6 +71,7 @@ func (d *aclDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Description: "Returns the Tailscale policy file for a tailnet.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetDataSourceAttribute(),
 			"json": schema.StringAttribute{
 				Computed:    true,
 				Description: "The contents of the policy file as a JSON string.",
@@ -52,6 +80,46 @@ func (d *aclDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 				Computed:    true,
 				Description: "The contents of the policy file as a HuJSON string.",
 			},
+			"groups": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The members of each group in the policy file, by group name.",
+			},
+			"tag_owners": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The owners of each tag in the policy file, by tag name.",
+			},
+			"hosts": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The IP address or range of each host in the policy file, by host name.",
+			},
+			"postures": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The rules of each device posture in the policy file, by posture name.",
+			},
+			"ipsets": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The entries of each IP set in the policy file, by IP set name.",
+			},
+			"tags": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Every tag mentioned in the policy file, in sorted order.",
+			},
+			"groups_for_user": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The groups that each user is a member of, in sorted order, by the user's login name.",
+			},
+			"auto_approvers": schema.ObjectAttribute{
+				Computed:       true,
+				AttributeTypes: aclAutoApproversAttributeTypes,
+				Description:    "The users, groups and tags that can approve routes, exit nodes and services without manual approval. `routes` and `services` are the approvers of each subnet route by CIDR and of each service by service name, and `exit_node` the approvers of exit nodes.",
+			},
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -76,18 +144,112 @@ func toAclDataSourceModel(acl *tailscale.RawACL) (*aclDataSourceModel, *diag.Err
 	huj.Minimize()
 	jsonString := huj.String()
 
+	doc, _, err := parsePolicyDocument(hujsonString)
+	if err != nil {
+		diagnostic := diag.NewErrorDiagnostic("Failed to parse ACL", err.Error())
+		return nil, &diagnostic
+	}
+	autoApprovers := doc.AutoApprovers
+	if autoApprovers == nil {
+		autoApprovers = &policyAutoApprovers{}
+	}
+
 	data := aclDataSourceModel{
-		ID:     types.StringValue(createUUID()),
-		HuJSON: types.StringValue(hujsonString),
-		JSON:   types.StringValue(jsonString),
+		ID:        types.StringValue(createUUID()),
+		HuJSON:    types.StringValue(hujsonString),
+		JSON:      types.StringValue(jsonString),
+		Groups:    aclStringListMapValue(doc.Groups),
+		TagOwners: aclStringListMapValue(doc.TagOwners),
+		Hosts:     aclStringMapValue(doc.Hosts),
+		Postures:  aclStringListMapValue(doc.Postures),
+		IPSets:    aclStringListMapValue(doc.IPSets),
+		AutoApprovers: types.ObjectValueMust(aclAutoApproversAttributeTypes, map[string]attr.Value{
+			"routes":    aclStringListMapValue(autoApprovers.Routes),
+			"exit_node": aclStringListValue(autoApprovers.ExitNode),
+			"services":  aclStringListMapValue(autoApprovers.Services),
+		}),
+		Tags:          aclStringListValue(policyTags(&huj)),
+		GroupsForUser: aclStringListMapValue(groupsForUser(doc.Groups)),
 	}
 
 	return &data, nil
 }
 
+// policyTags returns every tag that is mentioned in a policy file, in sorted
+// order, including tags that are followed by ports such as tag:web:443.
+func policyTags(v *hujson.Value) []string {
+	tags := make(map[string]bool)
+	addTag := func(s string) {
+		if name, ok := strings.CutPrefix(s, "tag:"); ok && name != "" {
+			name, _, _ = strings.Cut(name, ":")
+			tags["tag:"+name] = true
+		}
+	}
+
+	for value := range v.All() {
+		switch value := value.Value.(type) {
+		case hujson.Literal:
+			if value.Kind() == '"' {
+				addTag(value.String())
+			}
+		case *hujson.Object:
+			for _, m := range value.Members {
+				addTag(objectMemberName(m))
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(tags))
+}
+
+// groupsForUser returns the groups that each member of a group is in, by
+// member.
+func groupsForUser(groups map[string][]string) map[string][]string {
+	users := make(map[string][]string)
+	for _, group := range slices.Sorted(maps.Keys(groups)) {
+		for _, member := range groups[group] {
+			if !slices.Contains(users[member], group) {
+				users[member] = append(users[member], group)
+			}
+		}
+	}
+	return users
+}
+
+// aclStringListValue returns a list of strings, which is empty rather than
+// null if there are no values.
+func aclStringListValue(values []string) types.List {
+	return types.ListValueMust(types.StringType, policyStringValues(values))
+}
+
+// aclStringListMapValue returns a map of lists of strings, which is empty
+// rather than null if there are no values.
+func aclStringListMapValue(m map[string][]string) types.Map {
+	elems := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		elems[k] = aclStringListValue(v)
+	}
+	return types.MapValueMust(types.ListType{ElemType: types.StringType}, elems)
+}
+
+// aclStringMapValue returns a map of strings, which is empty rather than null
+// if there are no values.
+func aclStringMapValue(m map[string]string) types.Map {
+	elems := make(map[string]attr.Value, len(m))
+	for k, v := range m {
+		elems[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
+
 // Read fetches the data from the Tailscale API.
 func (d *aclDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	acl, err := d.Client.PolicyFile().Raw(ctx)
+	var config aclDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	acl, err := d.ClientForTailnet(config.Tailnet).PolicyFile().Raw(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch ACL", err.Error())
 		return
@@ -97,6 +259,7 @@ func (d *aclDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		resp.Diagnostics.Append(diag)
 		return
 	} else {
+		data.Tailnet = config.Tailnet
 		resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
 	}
 }
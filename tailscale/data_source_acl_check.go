@@ -0,0 +1,136 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSourceWithConfigure = &aclCheckDataSource{}
+)
+
+// NewACLCheckDataSource returns a new ACL check data source.
+func NewACLCheckDataSource() datasource.DataSource {
+	return &aclCheckDataSource{}
+}
+
+type aclCheckDataSource struct {
+	DataSourceBase
+}
+
+type aclCheckDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Policy  types.String `tfsdk:"policy"`
+	Source  types.String `tfsdk:"src"`
+	Dest    types.String `tfsdk:"dst"`
+	Proto   types.String `tfsdk:"proto"`
+	Allowed types.Bool   `tfsdk:"allowed"`
+	Tailnet types.String `tfsdk:"tailnet"`
+}
+
+// Metadata defines the data source name as it appears in Terraform configurations.
+func (d *aclCheckDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acl_check"
+}
+
+// Schema defines a schema describing what data is available in the data source response.
+func (d *aclCheckDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Checks whether a policy file allows a source to connect to a destination and port, in the same way as the "tests" section of the policy file.
+
+The policy file is evaluated locally. Access that depends on the state of the tailnet, such as the members of autogroup:admin or the IP addresses of devices, can't be evaluated and results in an error.`,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetDataSourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "The HuJSON policy file to evaluate. Defaults to the current policy file of the tailnet.",
+				Validators: []validator.String{
+					aclHuJSONValidator{},
+				},
+			},
+			"src": schema.StringAttribute{
+				Required:    true,
+				Description: "The source of the connection: a user's login name, a tag, a host defined in the policy file, or an IP address.",
+			},
+			"dst": schema.StringAttribute{
+				Required:    true,
+				Description: "The destination and port of the connection, such as `tag:web:443`. The destination can be a user's login name, a tag, a host defined in the policy file, or an IP address.",
+			},
+			"proto": schema.StringAttribute{
+				Optional:    true,
+				Description: "The IP protocol of the connection, either as a name such as `udp` or as an IANA protocol number. Defaults to `tcp`.",
+			},
+			"allowed": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the policy file allows the connection.",
+			},
+		},
+	}
+}
+
+// Read evaluates the policy file.
+func (d *aclCheckDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data aclCheckDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy := data.Policy.ValueString()
+	if data.Policy.IsNull() {
+		acl, err := d.ClientForTailnet(data.Tailnet).PolicyFile().Raw(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to fetch ACL", err.Error())
+			return
+		}
+		policy = acl.HuJSON
+	}
+
+	evaluator, err := newPolicyEvaluator(policy)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("policy"), "Failed to parse policy file", err.Error())
+		return
+	}
+
+	src, ok := evaluator.principal(data.Source.ValueString())
+	if !ok {
+		resp.Diagnostics.AddAttributeError(path.Root("src"), "Invalid source",
+			fmt.Sprintf("%q is not a user, a tag, a host defined in the policy file, or an IP address.", data.Source.ValueString()))
+	}
+	dst, port, ok := evaluator.destination(data.Dest.ValueString())
+	if !ok {
+		resp.Diagnostics.AddAttributeError(path.Root("dst"), "Invalid destination",
+			fmt.Sprintf("%q is not of the form host:port, where host is a user, a tag, a host defined in the policy file, or an IP address.", data.Dest.ValueString()))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch evaluator.canAccess(src, dst, port, data.Proto.ValueString()) {
+	case policyMatchFound:
+		data.Allowed = types.BoolValue(true)
+	case policyNoMatch:
+		data.Allowed = types.BoolValue(false)
+	default:
+		resp.Diagnostics.AddError("Failed to evaluate policy file",
+			fmt.Sprintf("Whether %s can access %s depends on the state of the tailnet, which can't be evaluated locally: %s",
+				data.Source.ValueString(), data.Dest.ValueString(), strings.Join(evaluator.unknownSelectors(), ", ")))
+		return
+	}
+
+	data.ID = types.StringValue(data.Source.ValueString() + " -> " + data.Dest.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestProvider_TailscaleACLCheck(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{
+		"groups": {"group:dev": ["alice@example.com"]},
+		"grants": [
+			{"src": ["group:dev"], "dst": ["tag:web"], "ip": ["443"]},
+			{"src": ["autogroup:admin"], "dst": ["tag:prod"], "ip": ["*"]},
+		],
+	}`)
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "tailscale_acl_check" "allowed" {
+						src = "alice@example.com"
+						dst = "tag:web:443"
+					}
+
+					data "tailscale_acl_check" "denied" {
+						src = "bob@example.com"
+						dst = "tag:web:443"
+					}
+
+					data "tailscale_acl_check" "policy" {
+						policy = jsonencode({ grants = [{ src = ["*"], dst = ["*"], ip = ["udp:53"] }] })
+						src    = "tag:web"
+						dst    = "10.0.0.53:53"
+						proto  = "udp"
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tailscale_acl_check.allowed", "allowed", "true"),
+					resource.TestCheckResourceAttr("data.tailscale_acl_check.denied", "allowed", "false"),
+					resource.TestCheckResourceAttr("data.tailscale_acl_check.policy", "allowed", "true"),
+				),
+			},
+			{
+				Config: `
+					data "tailscale_acl_check" "admin" {
+						src = "alice@example.com"
+						dst = "tag:prod:22"
+					}`,
+				ExpectError: regexp.MustCompile(`autogroup:admin`),
+			},
+		},
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 
@@ -111,3 +112,58 @@ func TestToAclDataSourceModelFailsIfInvalidHuJSON(t *testing.T) {
 		t.Fatalf("expected diag to be a HuJSON parsing failure, got %v", data)
 	}
 }
+
+// TestToAclDataSourceModelParsesPolicy checks that the sections of the policy
+// file are exposed as typed attributes.
+func TestToAclDataSourceModelParsesPolicy(t *testing.T) {
+	acl := tailscale.RawACL{HuJSON: `{
+		"groups": {
+			"group:dev": ["alice@example.com", "bob@example.com"],
+			"group:ops": ["bob@example.com"],
+		},
+		"tagOwners": {"tag:web": ["group:dev"]},
+		"hosts": {"db": "100.64.0.10"},
+		"autoApprovers": {
+			"routes": {"10.0.0.0/8": ["tag:router"]},
+			"exitNode": ["group:ops"],
+		},
+		"acls": [{"action": "accept", "src": ["group:dev"], "dst": ["tag:db:5432"]}],
+	}`}
+
+	data, diag := toAclDataSourceModel(&acl)
+	if diag != nil {
+		t.Fatalf("expected diag to be nil, got %v", diag)
+	}
+
+	var tags []string
+	data.Tags.ElementsAs(context.Background(), &tags, false)
+	if diff := cmp.Diff([]string{"tag:db", "tag:router", "tag:web"}, tags); diff != "" {
+		t.Fatalf("incorrect tags (-want, +got):\n%s", diff)
+	}
+
+	var groupsForUser map[string][]string
+	data.GroupsForUser.ElementsAs(context.Background(), &groupsForUser, false)
+	if diff := cmp.Diff(map[string][]string{
+		"alice@example.com": {"group:dev"},
+		"bob@example.com":   {"group:dev", "group:ops"},
+	}, groupsForUser); diff != "" {
+		t.Fatalf("incorrect groups_for_user (-want, +got):\n%s", diff)
+	}
+
+	var hosts map[string]string
+	data.Hosts.ElementsAs(context.Background(), &hosts, false)
+	if diff := cmp.Diff(map[string]string{"db": "100.64.0.10"}, hosts); diff != "" {
+		t.Fatalf("incorrect hosts (-want, +got):\n%s", diff)
+	}
+
+	var autoApprovers aclAutoApproversModel
+	data.AutoApprovers.As(context.Background(), &autoApprovers, basetypes.ObjectAsOptions{})
+	var exitNode []string
+	autoApprovers.ExitNode.ElementsAs(context.Background(), &exitNode, false)
+	if diff := cmp.Diff([]string{"group:ops"}, exitNode); diff != "" {
+		t.Fatalf("incorrect auto_approvers.exit_node (-want, +got):\n%s", diff)
+	}
+	if data.IPSets.IsNull() || len(data.IPSets.Elements()) != 0 {
+		t.Fatalf("expected ipsets to be empty, got %v", data.IPSets)
+	}
+}
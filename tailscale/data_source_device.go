@@ -35,6 +35,7 @@ type singleDeviceDataSourceModel struct {
 	deviceDataSourceModel
 
 	WaitFor types.String `tfsdk:"wait_for"`
+	Tailnet types.String `tfsdk:"tailnet"`
 }
 
 // Metadata defines the data source name as it appears in Terraform configurations.
@@ -63,6 +64,7 @@ func (d singleDeviceDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				retryDeadlineValidator{},
 			},
 		},
+		"tailnet": tailnetDataSourceAttribute(),
 	}
 	maps.Copy(attributes, deviceSchema)
 
@@ -105,7 +107,7 @@ func (d singleDeviceDataSource) Read(ctx context.Context, req datasource.ReadReq
 
 	var selected *tailscale.Device
 	poll := func(ctx context.Context) error {
-		devices, err := d.Client.Devices().List(ctx, filter)
+		devices, err := d.ClientForTailnet(device.Tailnet).Devices().List(ctx, filter)
 		if err != nil {
 			return err
 		}
@@ -6,10 +6,19 @@ package tailscale
 import (
 	"context"
 	"maps"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
@@ -30,10 +39,28 @@ type multipleDevicesDataSource struct {
 }
 
 type multipleDevicesDataSourceModel struct {
-	ID         types.String            `tfsdk:"id"`
-	NamePrefix types.String            `tfsdk:"name_prefix"`
-	Filters    []filterModel           `tfsdk:"filter"`
-	Devices    []deviceDataSourceModel `tfsdk:"devices"`
+	ID              types.String            `tfsdk:"id"`
+	NamePrefix      types.String            `tfsdk:"name_prefix"`
+	NameRegex       types.String            `tfsdk:"name_regex"`
+	HostnameRegex   types.String            `tfsdk:"hostname_regex"`
+	TagsAny         types.Set               `tfsdk:"tags_any"`
+	TagsAll         types.Set               `tfsdk:"tags_all"`
+	TagsNone        types.Set               `tfsdk:"tags_none"`
+	OS              types.Set               `tfsdk:"os"`
+	ClientVersion   types.String            `tfsdk:"client_version"`
+	LastSeenBefore  types.String            `tfsdk:"last_seen_before"`
+	LastSeenAfter   types.String            `tfsdk:"last_seen_after"`
+	ExpiresWithin   types.String            `tfsdk:"expires_within"`
+	Authorized      types.Bool              `tfsdk:"authorized"`
+	UpdateAvailable types.Bool              `tfsdk:"update_available"`
+	IsExternal      types.Bool              `tfsdk:"is_external"`
+	SortBy          types.String            `tfsdk:"sort_by"`
+	Limit           types.Int64             `tfsdk:"limit"`
+	Filters         []filterModel           `tfsdk:"filter"`
+	Devices         []deviceDataSourceModel `tfsdk:"devices"`
+	IDs             types.List              `tfsdk:"ids"`
+	AddressesByName types.Map               `tfsdk:"addresses_by_name"`
+	Tailnet         types.String            `tfsdk:"tailnet"`
 }
 
 type filterModel struct {
@@ -64,6 +91,7 @@ func (d multipleDevicesDataSource) Schema(_ context.Context, _ datasource.Schema
 	resp.Schema = schema.Schema{
 		Description: "The devices data source describes a list of devices in a tailnet",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetDataSourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -71,6 +99,92 @@ func (d multipleDevicesDataSource) Schema(_ context.Context, _ datasource.Schema
 				Optional:    true,
 				Description: "Filters the device list to elements whose name has the provided prefix",
 			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements whose full name matches the provided regular expression, using RE2 syntax",
+			},
+			"hostname_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements whose hostname matches the provided regular expression, using RE2 syntax",
+			},
+			"tags_any": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Filters the device list to elements that have at least one of the provided tags",
+			},
+			"tags_all": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Filters the device list to elements that have all of the provided tags",
+			},
+			"tags_none": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Filters the device list to elements that have none of the provided tags",
+			},
+			"os": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Filters the device list to elements running one of the provided operating systems, such as `linux` or `windows`. Matching is case-insensitive.",
+			},
+			"client_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements whose Tailscale client version satisfies the provided version constraint, such as `>= 1.60, < 1.70`. Build suffixes of client versions are ignored.",
+			},
+			"last_seen_before": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements last seen before the provided RFC3339 timestamp. Devices that are connected are never matched.",
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
+			"last_seen_after": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements last seen after the provided RFC3339 timestamp, or that are connected.",
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
+			"expires_within": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements whose key expires within the provided duration from now, such as `720h`, including keys that have already expired. Devices with key expiry disabled are never matched.",
+			},
+			"authorized": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements that are or are not authorized",
+			},
+			"update_available": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements that do or do not have a Tailscale client update available",
+			},
+			"is_external": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements that are or are not shared in from another tailnet",
+			},
+			"sort_by": schema.StringAttribute{
+				Optional:    true,
+				Description: "Sorts the device list in ascending order of the provided attribute, one of `name`, `hostname`, `created`, `last_seen`, `expires` or `client_version`. Devices that sort equally are ordered by node ID. Connected devices sort as most recently seen. Defaults to the order returned by the API.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("name", "hostname", "created", "last_seen", "expires", "client_version"),
+				},
+			},
+			"limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Limits the device list to the provided number of elements, after filtering and sorting",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"ids": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The node IDs of the devices in the device list, in order",
+			},
+			"addresses_by_name": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				Description: "The Tailscale IP addresses of the devices in the device list, by full name",
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"filter": schema.SetNestedBlock{
@@ -120,29 +234,255 @@ func (d multipleDevicesDataSource) Read(ctx context.Context, req datasource.Read
 		opts = append(opts, tailscale.WithFilter(f.Name.ValueString(), values))
 	}
 
-	devices, err := d.Client.Devices().List(ctx, opts...)
+	query := newDeviceQuery(ctx, &data, time.Now(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	devices, err := d.ClientForTailnet(data.Tailnet).Devices().List(ctx, opts...)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch devices", err.Error())
 		return
 	}
 
-	prefix := data.NamePrefix.ValueString()
 	data.Devices = make([]deviceDataSourceModel, 0)
 
 	for _, dev := range devices {
-		if prefix != "" && !strings.HasPrefix(dev.Name, prefix) {
-			continue
-		}
-
 		deviceModel, diagnostics := toDeviceDataSourceModel(ctx, &dev)
 		if diagnostics.HasError() {
 			resp.Diagnostics.Append(diagnostics...)
 			return
 		}
 
-		data.Devices = append(data.Devices, deviceModel)
+		if query.matches(ctx, deviceModel) {
+			data.Devices = append(data.Devices, deviceModel)
+		}
+	}
+
+	if !data.SortBy.IsNull() {
+		sortDevices(data.Devices, data.SortBy.ValueString())
+	}
+	if !data.Limit.IsNull() && int64(len(data.Devices)) > data.Limit.ValueInt64() {
+		data.Devices = data.Devices[:data.Limit.ValueInt64()]
+	}
+
+	ids := make([]string, 0, len(data.Devices))
+	addressesByName := make(map[string]types.List, len(data.Devices))
+	for _, dev := range data.Devices {
+		ids = append(ids, dev.NodeID.ValueString())
+		addressesByName[dev.Name.ValueString()] = dev.Addresses
+	}
+
+	var diags diag.Diagnostics
+	data.IDs, diags = types.ListValueFrom(ctx, types.StringType, ids)
+	resp.Diagnostics.Append(diags...)
+	data.AddressesByName, diags = types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, addressesByName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	data.ID = types.StringValue(createUUID())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// deviceQuery filters the devices of the tailscale_devices data source on the
+// client side, as configured by its filtering attributes.
+type deviceQuery struct {
+	namePrefix      string
+	nameRegex       *regexp.Regexp
+	hostnameRegex   *regexp.Regexp
+	tagsAny         []string
+	tagsAll         []string
+	tagsNone        []string
+	os              []string
+	clientVersion   version.Constraints
+	lastSeenBefore  time.Time
+	lastSeenAfter   time.Time
+	expiresBefore   time.Time
+	authorized      types.Bool
+	updateAvailable types.Bool
+	isExternal      types.Bool
+}
+
+// newDeviceQuery returns the query configured by data, with expires_within
+// relative to now.
+func newDeviceQuery(ctx context.Context, data *multipleDevicesDataSourceModel, now time.Time, diags *diag.Diagnostics) *deviceQuery {
+	q := &deviceQuery{
+		namePrefix:      data.NamePrefix.ValueString(),
+		authorized:      data.Authorized,
+		updateAvailable: data.UpdateAvailable,
+		isExternal:      data.IsExternal,
+	}
+
+	compile := func(attr string, v types.String) *regexp.Regexp {
+		if v.IsNull() {
+			return nil
+		}
+		re, err := regexp.Compile(v.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root(attr), "Invalid regular expression", err.Error())
+		}
+		return re
+	}
+	q.nameRegex = compile("name_regex", data.NameRegex)
+	q.hostnameRegex = compile("hostname_regex", data.HostnameRegex)
+
+	for _, set := range []struct {
+		value types.Set
+		dst   *[]string
+	}{
+		{data.TagsAny, &q.tagsAny},
+		{data.TagsAll, &q.tagsAll},
+		{data.TagsNone, &q.tagsNone},
+		{data.OS, &q.os},
+	} {
+		if !set.value.IsNull() {
+			diags.Append(set.value.ElementsAs(ctx, set.dst, false)...)
+		}
+	}
+
+	if !data.ClientVersion.IsNull() {
+		constraints, err := version.NewConstraint(data.ClientVersion.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("client_version"), "Invalid version constraint", err.Error())
+		}
+		q.clientVersion = constraints
+	}
+
+	// The timestamps have already been validated.
+	if !data.LastSeenBefore.IsNull() {
+		q.lastSeenBefore, _ = time.Parse(time.RFC3339, data.LastSeenBefore.ValueString())
+	}
+	if !data.LastSeenAfter.IsNull() {
+		q.lastSeenAfter, _ = time.Parse(time.RFC3339, data.LastSeenAfter.ValueString())
+	}
+
+	if !data.ExpiresWithin.IsNull() {
+		within, err := time.ParseDuration(data.ExpiresWithin.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("expires_within"), "Invalid duration", err.Error())
+		}
+		q.expiresBefore = now.Add(within)
+	}
+
+	return q
+}
+
+// matches reports whether the device matches all of the query's filters.
+func (q *deviceQuery) matches(ctx context.Context, d deviceDataSourceModel) bool {
+	name, hostname := d.Name.ValueString(), d.Hostname.ValueString()
+	switch {
+	case !strings.HasPrefix(name, q.namePrefix),
+		q.nameRegex != nil && !q.nameRegex.MatchString(name),
+		q.hostnameRegex != nil && !q.hostnameRegex.MatchString(hostname),
+		!q.authorized.IsNull() && q.authorized.ValueBool() != d.Authorized.ValueBool(),
+		!q.updateAvailable.IsNull() && q.updateAvailable.ValueBool() != d.UpdateAvailable.ValueBool(),
+		!q.isExternal.IsNull() && q.isExternal.ValueBool() != d.IsExternal.ValueBool(),
+		len(q.os) > 0 && !slices.ContainsFunc(q.os, func(os string) bool { return strings.EqualFold(os, d.OS.ValueString()) }):
+		return false
+	}
+
+	var tags []string
+	d.Tags.ElementsAs(ctx, &tags, false)
+	hasTag := func(tag string) bool { return slices.Contains(tags, tag) }
+	switch {
+	case len(q.tagsAny) > 0 && !slices.ContainsFunc(q.tagsAny, hasTag),
+		slices.ContainsFunc(q.tagsAll, func(tag string) bool { return !hasTag(tag) }),
+		slices.ContainsFunc(q.tagsNone, hasTag):
+		return false
+	}
+
+	if q.clientVersion != nil {
+		v, err := deviceClientVersion(d)
+		if err != nil || !q.clientVersion.Check(v) {
+			return false
+		}
+	}
+
+	// Devices that are connected have no last seen time.
+	if !q.lastSeenBefore.IsZero() || !q.lastSeenAfter.IsZero() {
+		lastSeen, err := time.Parse(time.RFC3339, d.LastSeen.ValueString())
+		connected := d.LastSeen.ValueString() == ""
+		switch {
+		case !connected && err != nil,
+			!q.lastSeenBefore.IsZero() && (connected || !lastSeen.Before(q.lastSeenBefore)),
+			!q.lastSeenAfter.IsZero() && !connected && !lastSeen.After(q.lastSeenAfter):
+			return false
+		}
+	}
+
+	if !q.expiresBefore.IsZero() {
+		expires, err := time.Parse(time.RFC3339, d.Expires.ValueString())
+		if d.KeyExpiryDisabled.ValueBool() || err != nil || !expires.Before(q.expiresBefore) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// deviceClientVersion returns the Tailscale client version of the device,
+// without the build suffix, such as `1.62.0` for `1.62.0-t1234abcd-g5678ef`.
+func deviceClientVersion(d deviceDataSourceModel) (*version.Version, error) {
+	v, err := version.NewVersion(d.ClientVersion.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	return v.Core(), nil
+}
+
+// sortDevices sorts the devices in ascending order of the attribute sortBy,
+// then by node ID. Devices without a valid time or version sort first, except
+// that connected devices sort as most recently seen.
+func sortDevices(devices []deviceDataSourceModel, sortBy string) {
+	parseTime := func(s types.String) time.Time {
+		t, _ := time.Parse(time.RFC3339, s.ValueString())
+		return t
+	}
+
+	var compare func(a, b deviceDataSourceModel) int
+	switch sortBy {
+	case "name":
+		compare = func(a, b deviceDataSourceModel) int {
+			return strings.Compare(a.Name.ValueString(), b.Name.ValueString())
+		}
+	case "hostname":
+		compare = func(a, b deviceDataSourceModel) int {
+			return strings.Compare(a.Hostname.ValueString(), b.Hostname.ValueString())
+		}
+	case "created":
+		compare = func(a, b deviceDataSourceModel) int { return parseTime(a.Created).Compare(parseTime(b.Created)) }
+	case "expires":
+		compare = func(a, b deviceDataSourceModel) int { return parseTime(a.Expires).Compare(parseTime(b.Expires)) }
+	case "last_seen":
+		lastSeen := func(d deviceDataSourceModel) time.Time {
+			if d.LastSeen.ValueString() == "" {
+				return time.Unix(1<<62, 0)
+			}
+			return parseTime(d.LastSeen)
+		}
+		compare = func(a, b deviceDataSourceModel) int { return lastSeen(a).Compare(lastSeen(b)) }
+	case "client_version":
+		compare = func(a, b deviceDataSourceModel) int {
+			va, errA := deviceClientVersion(a)
+			vb, errB := deviceClientVersion(b)
+			switch {
+			case errA != nil && errB != nil:
+				return 0
+			case errA != nil:
+				return -1
+			case errB != nil:
+				return 1
+			}
+			return va.Compare(vb)
+		}
+	}
+
+	slices.SortStableFunc(devices, func(a, b deviceDataSourceModel) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.NodeID.ValueString(), b.NodeID.ValueString())
+	})
+}
@@ -7,9 +7,14 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 
@@ -212,3 +217,253 @@ func assertDeviceAttributesEqual(t *testing.T, rs *terraform.InstanceState, gotI
 		}
 	}
 }
+
+func TestProvider_TailscaleDevicesQuery(t *testing.T) {
+	const dataSourceName = "data.tailscale_devices.web"
+
+	factories, server := testFakeControlProviderFactories(t)
+	web2 := server.AddDevice(tailscale.Device{Hostname: "web-2", Tags: []string{"tag:web"}})
+	web1 := server.AddDevice(tailscale.Device{Hostname: "web-1", Tags: []string{"tag:web"}})
+	server.AddDevice(tailscale.Device{Hostname: "db-1", Tags: []string{"tag:db"}})
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "tailscale_devices" "web" {
+						tags_any = ["tag:web"]
+						sort_by  = "hostname"
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "devices.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "ids.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "ids.0", web1.NodeID),
+					resource.TestCheckResourceAttr(dataSourceName, "ids.1", web2.NodeID),
+					resource.TestCheckResourceAttr(dataSourceName, "addresses_by_name.%", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "addresses_by_name."+web1.Name+".0", web1.Addresses[0]),
+				),
+			},
+			{
+				Config: `
+					data "tailscale_devices" "web" {
+						hostname_regex = "^web-"
+						sort_by        = "hostname"
+						limit          = 1
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "ids.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "ids.0", web1.NodeID),
+				),
+			},
+		},
+	})
+}
+
+func TestDeviceQuery(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *tailscale.Time { return &tailscale.Time{Time: now.Add(-d)} }
+
+	devices := []tailscale.Device{
+		{
+			NodeID:        "web1",
+			Name:          "web-1.example.ts.net",
+			Hostname:      "ip-10-0-0-1",
+			OS:            "linux",
+			ClientVersion: "1.62.0-t1234abcd-g5678ef",
+			Tags:          []string{"tag:web", "tag:prod"},
+			Authorized:    true,
+			Expires:       tailscale.Time{Time: now.Add(24 * time.Hour)},
+		},
+		{
+			NodeID:        "web2",
+			Name:          "web-2.example.ts.net",
+			Hostname:      "ip-10-0-0-2",
+			OS:            "linux",
+			ClientVersion: "1.70.0",
+			Tags:          []string{"tag:web"},
+			LastSeen:      ago(48 * time.Hour),
+			Expires:       tailscale.Time{Time: now.Add(-time.Hour)},
+		},
+		{
+			NodeID:            "laptop",
+			Name:              "laptop.example.ts.net",
+			Hostname:          "Laptop",
+			OS:                "macOS",
+			ClientVersion:     "1.56.1",
+			LastSeen:          ago(time.Hour),
+			KeyExpiryDisabled: true,
+			UpdateAvailable:   true,
+		},
+	}
+	models := make([]deviceDataSourceModel, 0, len(devices))
+	for _, d := range devices {
+		m, diags := toDeviceDataSourceModel(ctx, &d)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		models = append(models, m)
+	}
+
+	set := func(values ...string) types.Set {
+		return types.SetValueMust(types.StringType, func() []attr.Value {
+			var elems []attr.Value
+			for _, v := range values {
+				elems = append(elems, types.StringValue(v))
+			}
+			return elems
+		}())
+	}
+
+	tests := []struct {
+		name    string
+		data    multipleDevicesDataSourceModel
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no filters",
+			want: []string{"web1", "web2", "laptop"},
+		},
+		{
+			name: "name prefix and hostname regex",
+			data: multipleDevicesDataSourceModel{
+				NamePrefix:    types.StringValue("web-"),
+				HostnameRegex: types.StringValue(`-1$`),
+			},
+			want: []string{"web1"},
+		},
+		{
+			name: "name regex",
+			data: multipleDevicesDataSourceModel{NameRegex: types.StringValue(`^(laptop|web-2)\.`)},
+			want: []string{"web2", "laptop"},
+		},
+		{
+			name:    "invalid regex",
+			data:    multipleDevicesDataSourceModel{NameRegex: types.StringValue(`(`)},
+			wantErr: true,
+		},
+		{
+			name: "tags any",
+			data: multipleDevicesDataSourceModel{TagsAny: set("tag:prod", "tag:other")},
+			want: []string{"web1"},
+		},
+		{
+			name: "tags all",
+			data: multipleDevicesDataSourceModel{TagsAll: set("tag:web", "tag:prod")},
+			want: []string{"web1"},
+		},
+		{
+			name: "tags none",
+			data: multipleDevicesDataSourceModel{TagsNone: set("tag:prod")},
+			want: []string{"web2", "laptop"},
+		},
+		{
+			name: "os is case insensitive",
+			data: multipleDevicesDataSourceModel{OS: set("macos")},
+			want: []string{"laptop"},
+		},
+		{
+			name: "client version ignores build suffix",
+			data: multipleDevicesDataSourceModel{ClientVersion: types.StringValue(">= 1.60, < 1.70")},
+			want: []string{"web1"},
+		},
+		{
+			name:    "invalid client version constraint",
+			data:    multipleDevicesDataSourceModel{ClientVersion: types.StringValue("latest")},
+			wantErr: true,
+		},
+		{
+			name: "last seen before excludes connected devices",
+			data: multipleDevicesDataSourceModel{LastSeenBefore: types.StringValue(now.Add(-2 * time.Hour).Format(time.RFC3339))},
+			want: []string{"web2"},
+		},
+		{
+			name: "last seen after includes connected devices",
+			data: multipleDevicesDataSourceModel{LastSeenAfter: types.StringValue(now.Add(-2 * time.Hour).Format(time.RFC3339))},
+			want: []string{"web1", "laptop"},
+		},
+		{
+			name: "expires within includes expired keys",
+			data: multipleDevicesDataSourceModel{ExpiresWithin: types.StringValue("1h")},
+			want: []string{"web2"},
+		},
+		{
+			name:    "invalid expires within",
+			data:    multipleDevicesDataSourceModel{ExpiresWithin: types.StringValue("30d")},
+			wantErr: true,
+		},
+		{
+			name: "booleans",
+			data: multipleDevicesDataSourceModel{
+				Authorized:      types.BoolValue(false),
+				UpdateAvailable: types.BoolValue(true),
+				IsExternal:      types.BoolValue(false),
+			},
+			want: []string{"laptop"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			query := newDeviceQuery(ctx, &tt.data, now, &diags)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []string{}
+			for _, m := range models {
+				if query.matches(ctx, m) {
+					got = append(got, m.NodeID.ValueString())
+				}
+			}
+			if want := tt.want; !slices.Equal(got, want) {
+				t.Errorf("got devices %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSortDevices(t *testing.T) {
+	device := func(nodeID, name, lastSeen, clientVersion string) deviceDataSourceModel {
+		return deviceDataSourceModel{
+			NodeID:        types.StringValue(nodeID),
+			Name:          types.StringValue(name),
+			LastSeen:      types.StringValue(lastSeen),
+			ClientVersion: types.StringValue(clientVersion),
+		}
+	}
+	devices := []deviceDataSourceModel{
+		device("c", "b", "", "1.10.0"),
+		device("b", "b", "2026-01-01T00:00:00Z", "1.9.0-t123"),
+		device("a", "a", "2026-02-01T00:00:00Z", "unknown"),
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{"name", []string{"a", "b", "c"}},
+		{"last_seen", []string{"b", "a", "c"}},
+		{"client_version", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			sorted := slices.Clone(devices)
+			sortDevices(sorted, tt.sortBy)
+
+			var got []string
+			for _, d := range sorted {
+				got = append(got, d.NodeID.ValueString())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got order %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ func (d dataSourceService) Schema(_ context.Context, _ datasource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "The Service data source describes a single Service in a tailnet. See https://tailscale.com/docs/features/tailscale-services for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetDataSourceAttribute(),
 			"name": schema.StringAttribute{
 				Description: "The name of the Service (e.g. `svc:my-service`).",
 				Required:    true,
@@ -72,6 +73,7 @@ type dataSourceServiceModel struct {
 	Comment types.String `tfsdk:"comment"`
 	Ports   types.List   `tfsdk:"ports"`
 	Tags    types.Set    `tfsdk:"tags"`
+	Tailnet types.String `tfsdk:"tailnet"`
 }
 
 // Read fetches the data from the Tailscale API.
@@ -83,7 +85,7 @@ func (d dataSourceService) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	name := data.Name.ValueString()
-	svc, err := d.Client.VIPServices().Get(ctx, name)
+	svc, err := d.ClientForTailnet(data.Tailnet).VIPServices().Get(ctx, name)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch service", err.Error())
 		return
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"tailscale.com/client/tailscale/v2"
 )
@@ -29,6 +30,12 @@ type singleUserDataSource struct {
 	DataSourceBase
 }
 
+type singleUserDataSourceModel struct {
+	userDataSourceModel
+
+	Tailnet types.String `tfsdk:"tailnet"`
+}
+
 // Metadata defines the data source name as it appears in Terraform configurations.
 func (d singleUserDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_user"
@@ -48,6 +55,7 @@ func (d singleUserDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 			Description: "The emailish login name of the user.",
 			Optional:    true,
 		},
+		"tailnet": tailnetDataSourceAttribute(),
 	}
 
 	maps.Copy(attributes, userSchema)
@@ -60,7 +68,7 @@ func (d singleUserDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 
 // Read fetches the data from the Tailscale API.
 func (d singleUserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var data userDataSourceModel
+	var data singleUserDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -70,13 +78,13 @@ func (d singleUserDataSource) Read(ctx context.Context, req datasource.ReadReque
 	var err error
 
 	if !data.ID.IsNull() {
-		user, err = d.Client.Users().Get(ctx, data.ID.ValueString())
+		user, err = d.ClientForTailnet(data.Tailnet).Users().Get(ctx, data.ID.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("Failed to fetch user by ID", err.Error())
 			return
 		}
 	} else if !data.LoginName.IsNull() {
-		users, err := d.Client.Users().List(ctx, nil, nil)
+		users, err := d.ClientForTailnet(data.Tailnet).Users().List(ctx, nil, nil)
 		if err != nil {
 			resp.Diagnostics.AddError("Failed to fetch users", err.Error())
 		}
@@ -99,6 +107,6 @@ func (d singleUserDataSource) Read(ctx context.Context, req datasource.ReadReque
 		panic("unreachable!")
 	}
 
-	data = toUserDataSourceModel(user)
+	data.userDataSourceModel = toUserDataSourceModel(user)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -29,10 +29,11 @@ type multipleUsersDataSource struct {
 }
 
 type multipleUsersDataSourceModel struct {
-	ID    types.String          `tfsdk:"id"`
-	Type  types.String          `tfsdk:"type"`
-	Role  types.String          `tfsdk:"role"`
-	Users []userDataSourceModel `tfsdk:"users"`
+	ID      types.String          `tfsdk:"id"`
+	Type    types.String          `tfsdk:"type"`
+	Role    types.String          `tfsdk:"role"`
+	Users   []userDataSourceModel `tfsdk:"users"`
+	Tailnet types.String          `tfsdk:"tailnet"`
 }
 
 // Metadata defines the data source name as it appears in Terraform configurations.
@@ -57,6 +58,7 @@ func (d multipleUsersDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 	resp.Schema = schema.Schema{
 		Description: "The users data source describes a list of users in a tailnet",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetDataSourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -115,7 +117,7 @@ func (d multipleUsersDataSource) Read(ctx context.Context, req datasource.ReadRe
 		userRole = new(tailscale.UserRole(data.Role.ValueString()))
 	}
 
-	users, err := d.Client.Users().List(ctx, userType, userRole)
+	users, err := d.ClientForTailnet(data.Tailnet).Users().List(ctx, userType, userRole)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch users", err.Error())
 		return
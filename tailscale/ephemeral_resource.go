@@ -0,0 +1,51 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+// EphemeralResourceBase is a base struct for all Tailscale ephemeral resources.
+//
+// All ephemeral resources should extend this struct, then the authenticated
+// [Client] will be available in their Open, Renew and Close methods.
+type EphemeralResourceBase struct {
+	Client *tailscale.Client
+
+	providerData *providerData
+}
+
+// Configure attaches the client to the ephemeral resource, so it can be used in
+// the Open, Renew and Close methods.
+func (e *EphemeralResourceBase) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf(
+				"Expected *providerData, got: %T. Please report this error at https://github.com/tailscale/tailscale.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	e.Client = data.Client
+	e.providerData = data
+}
+
+// ClientForTailnet returns the client for the ephemeral resource's `tailnet`
+// attribute, or the provider's client if it is not set.
+func (e *EphemeralResourceBase) ClientForTailnet(tailnet types.String) *tailscale.Client {
+	return clientForTailnet(e.providerData, tailnet)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &tailnetKeyEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &tailnetKeyEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &tailnetKeyEphemeralResource{}
+)
+
+const ephemeralTailnetKeyDescription = `The tailnet_key ephemeral resource allows you to create pre-authentication keys that can register new nodes without needing to sign in via a web browser. See https://tailscale.com/kb/1085/auth-keys for more information.
+
+Unlike the tailscale_tailnet_key resource, the key is never persisted in the Terraform plan or state. It is intended to be passed to write-only arguments of other resources, such as the user data of a virtual machine.`
+
+// tailnetKeyPrivateDataKey is the private data key under which the key
+// details required by Close are stored.
+const tailnetKeyPrivateDataKey = "tailnet_key"
+
+type tailnetKeyEphemeralResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Reusable      types.Bool   `tfsdk:"reusable"`
+	Ephemeral     types.Bool   `tfsdk:"ephemeral"`
+	Tags          types.Set    `tfsdk:"tags"`
+	Preauthorized types.Bool   `tfsdk:"preauthorized"`
+	Key           types.String `tfsdk:"key"`
+	Expiry        types.Int64  `tfsdk:"expiry"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	ExpiresAt     types.String `tfsdk:"expires_at"`
+	Description   types.String `tfsdk:"description"`
+	DeleteOnClose types.Bool   `tfsdk:"delete_on_close"`
+	Tailnet       types.String `tfsdk:"tailnet"`
+}
+
+// tailnetKeyPrivateData is the private data passed from Open to Close.
+type tailnetKeyPrivateData struct {
+	ID            string `json:"id"`
+	Tailnet       string `json:"tailnet,omitempty"`
+	DeleteOnClose bool   `json:"delete_on_close"`
+}
+
+// NewTailnetKeyEphemeralResource returns a new tailnet key ephemeral resource.
+func NewTailnetKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &tailnetKeyEphemeralResource{}
+}
+
+type tailnetKeyEphemeralResource struct {
+	EphemeralResourceBase
+}
+
+// Metadata defines the ephemeral resource name as it appears in Terraform configurations.
+func (e *tailnetKeyEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tailnet_key"
+}
+
+// Schema defines a schema describing what fields can be defined in the ephemeral resource.
+func (e *tailnetKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: ephemeralTailnetKeyDescription,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the key.",
+				Computed:    true,
+			},
+			"reusable": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Indicates if the key is reusable or single-use. Defaults to `false`.",
+			},
+			"ephemeral": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Indicates if the key is ephemeral. Defaults to `false`.",
+			},
+			"tags": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "List of tags to apply to the machines authenticated by the key.",
+			},
+			"preauthorized": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Determines whether or not the machines authenticated by the key will be authorized for the tailnet by default. Defaults to `false`.",
+			},
+			"key": schema.StringAttribute{
+				Description: "The authentication key",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"expiry": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The expiry of the key in seconds. Defaults to `7776000` (90 days).",
+			},
+			"created_at": schema.StringAttribute{
+				Description: "The creation timestamp of the key in RFC3339 format",
+				Computed:    true,
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "The expiry timestamp of the key in RFC3339 format",
+				Computed:    true,
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "A description of the key consisting of alphanumeric characters.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(50),
+				},
+			},
+			"tailnet": schema.StringAttribute{
+				Optional:    true,
+				Description: tailnetAttributeDescription,
+			},
+			"delete_on_close": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If true, the key is deleted when Terraform closes the ephemeral resource at the end of the run. Only enable this when the key is consumed during the run itself, as keys passed to machines that boot later will no longer be valid. Defaults to `false`.",
+			},
+		},
+	}
+}
+
+// Open creates a new auth key. The key is only returned to Terraform for the
+// duration of the run and is never stored in the plan or state.
+func (e *tailnetKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data tailnetKeyEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var createKeyRequest tailscale.CreateKeyRequest
+	createKeyRequest.Capabilities.Devices.Create.Reusable = data.Reusable.ValueBool()
+	createKeyRequest.Capabilities.Devices.Create.Ephemeral = data.Ephemeral.ValueBool()
+
+	var tags []string
+	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createKeyRequest.Capabilities.Devices.Create.Tags = tags
+	createKeyRequest.Capabilities.Devices.Create.Preauthorized = data.Preauthorized.ValueBool()
+	createKeyRequest.ExpirySeconds = data.Expiry.ValueInt64()
+	createKeyRequest.Description = data.Description.ValueString()
+
+	key, err := e.ClientForTailnet(data.Tailnet).Keys().CreateAuthKey(ctx, createKeyRequest)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create key", fmt.Sprintf("Error creating tailnet key: %s", err.Error()))
+		return
+	}
+
+	data.ID = types.StringValue(key.ID)
+	data.Key = types.StringValue(key.Key)
+	data.CreatedAt = types.StringValue(key.Created.Format(time.RFC3339))
+	data.ExpiresAt = types.StringValue(key.Expires.Format(time.RFC3339))
+	data.Expiry = types.Int64PointerValue((*int64)(key.ExpirySeconds))
+
+	private, err := json.Marshal(tailnetKeyPrivateData{
+		ID:            key.ID,
+		DeleteOnClose: data.DeleteOnClose.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to store key details", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, tailnetKeyPrivateDataKey, private)...)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Close deletes the auth key created in Open if `delete_on_close` is set.
+func (e *tailnetKeyEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	raw, diags := req.Private.GetKey(ctx, tailnetKeyPrivateDataKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || raw == nil {
+		return
+	}
+
+	var private tailnetKeyPrivateData
+	if err := json.Unmarshal(raw, &private); err != nil {
+		resp.Diagnostics.AddError("Failed to read key details", err.Error())
+		return
+	}
+
+	if !private.DeleteOnClose {
+		return
+	}
+
+	err := e.ClientForTailnet(types.StringValue(private.Tailnet)).Keys().Delete(ctx, private.ID)
+	// Single-use keys may already have been consumed, so we can ignore deletions that fail due to not-found errors.
+	if err != nil && !tailscale.IsNotFound(err) {
+		resp.Diagnostics.AddError("Failed to delete key", fmt.Sprintf("Error deleting tailnet key with id %q: %s", private.ID, err.Error()))
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/tailscale/hujson"
+)
+
+var _ function.Function = &hujsonFormatFunction{}
+
+// NewHuJSONFormatFunction returns a new hujson_format function.
+func NewHuJSONFormatFunction() function.Function {
+	return &hujsonFormatFunction{}
+}
+
+type hujsonFormatFunction struct{}
+
+// Metadata defines the function name as it appears in Terraform configurations.
+func (f *hujsonFormatFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "hujson_format"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *hujsonFormatFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Formats a HuJSON document",
+		MarkdownDescription: "Formats a [HuJSON](https://github.com/tailscale/hujson) document, such as a Tailscale policy file, using the canonical HuJSON formatting while preserving comments. This is the same normalization the `tailscale_acl` resource uses to suppress formatting-only diffs.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "hujson",
+				Description: "The HuJSON document to format",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run formats the HuJSON document.
+func (f *hujsonFormatFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	resp.Error = req.Arguments.Get(ctx, &input)
+	if resp.Error != nil {
+		return
+	}
+
+	formatted, err := hujson.Format([]byte(input))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to format HuJSON: "+err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, string(formatted))
+}
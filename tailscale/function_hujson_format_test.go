@@ -0,0 +1,33 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHuJSONFormatFunction(t *testing.T) {
+	testCases := []functionTestCase{
+		{
+			name:      "formats-and-keeps-comments",
+			arguments: []attr.Value{types.StringValue("{\n// Comment\n    \"wheels\": 6,\n\"seats\":8}")},
+			expected:  types.StringValue("{\n\t// Comment\n\t\"wheels\": 6,\n\t\"seats\":  8,\n}\n"),
+		},
+		{
+			name:      "already-formatted",
+			arguments: []attr.Value{types.StringValue("{\"wheels\": 6}\n")},
+			expected:  types.StringValue("{\"wheels\": 6}\n"),
+		},
+		{
+			name:        "invalid",
+			arguments:   []attr.Value{types.StringValue("<xml>not json</xml>")},
+			expectError: "Failed to format HuJSON",
+		},
+	}
+
+	runFunctionTests(t, NewHuJSONFormatFunction(), testCases)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/tailscale/hujson"
+)
+
+var _ function.Function = &hujsonToJSONFunction{}
+
+// NewHuJSONToJSONFunction returns a new hujson_to_json function.
+func NewHuJSONToJSONFunction() function.Function {
+	return &hujsonToJSONFunction{}
+}
+
+type hujsonToJSONFunction struct{}
+
+// Metadata defines the function name as it appears in Terraform configurations.
+func (f *hujsonToJSONFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "hujson_to_json"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *hujsonToJSONFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Converts a HuJSON document to compact standard JSON",
+		MarkdownDescription: "Converts a [HuJSON](https://github.com/tailscale/hujson) document, such as a Tailscale policy file, to compact standard JSON by removing comments, trailing commas and insignificant whitespace. The result can be passed to `jsondecode`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "hujson",
+				Description: "The HuJSON document to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run converts the HuJSON document to JSON.
+func (f *hujsonToJSONFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+	resp.Error = req.Arguments.Get(ctx, &input)
+	if resp.Error != nil {
+		return
+	}
+
+	value, err := hujson.Parse([]byte(input))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to parse HuJSON: "+err.Error())
+		return
+	}
+	value.Standardize()
+	value.Minimize()
+
+	resp.Error = resp.Result.Set(ctx, string(value.Pack()))
+}
@@ -0,0 +1,38 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHuJSONToJSONFunction(t *testing.T) {
+	testCases := []functionTestCase{
+		{
+			name: "comments-and-trailing-commas",
+			arguments: []attr.Value{types.StringValue(`{
+				// Allow all traffic.
+				"acls": [
+					{"action": "accept", "src": ["*"], "dst": ["*:*"]},
+				],
+			}`)},
+			expected: types.StringValue(`{"acls":[{"action":"accept","src":["*"],"dst":["*:*"]}]}`),
+		},
+		{
+			name:      "standard-json",
+			arguments: []attr.Value{types.StringValue(`{"wheels": 3}`)},
+			expected:  types.StringValue(`{"wheels":3}`),
+		},
+		{
+			name:        "invalid",
+			arguments:   []attr.Value{types.StringValue("<xml>not json</xml>")},
+			expectError: "Failed to parse HuJSON",
+		},
+	}
+
+	runFunctionTests(t, NewHuJSONToJSONFunction(), testCases)
+}
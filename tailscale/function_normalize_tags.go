@@ -0,0 +1,82 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &normalizeTagsFunction{}
+
+// NewNormalizeTagsFunction returns a new normalize_tags function.
+func NewNormalizeTagsFunction() function.Function {
+	return &normalizeTagsFunction{}
+}
+
+type normalizeTagsFunction struct{}
+
+// Metadata defines the function name as it appears in Terraform configurations.
+func (f *normalizeTagsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_tags"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *normalizeTagsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Normalizes a list of Tailscale tags",
+		MarkdownDescription: "Normalizes a list of Tailscale tags by trimming whitespace, adding the `tag:` prefix where it is missing, removing duplicates and sorting the result. Useful for comparing tags from different sources, or passing tags to resources such as `tailscale_device_tags`.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "tags",
+				Description: "The tags to normalize",
+				ElementType: types.StringType,
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+// Run normalizes the list of tags.
+func (f *normalizeTagsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var tags []*string
+	resp.Error = req.Arguments.Get(ctx, &tags)
+	if resp.Error != nil {
+		return
+	}
+
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, normalized)
+}
+
+// normalizeTags trims, prefixes, de-duplicates and sorts the given tags.
+func normalizeTags(tags []*string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for i, tag := range tags {
+		if tag == nil {
+			return nil, fmt.Errorf("tag at index %d must not be null", i)
+		}
+
+		name := strings.TrimPrefix(strings.TrimSpace(*tag), "tag:")
+		if name == "" {
+			return nil, fmt.Errorf("tag at index %d must not be empty", i)
+		}
+		normalized = append(normalized, "tag:"+name)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
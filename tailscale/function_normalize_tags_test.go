@@ -0,0 +1,46 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNormalizeTagsFunction(t *testing.T) {
+	tags := func(values ...attr.Value) attr.Value {
+		return types.ListValueMust(types.StringType, values)
+	}
+
+	testCases := []functionTestCase{
+		{
+			name: "normalizes",
+			arguments: []attr.Value{tags(
+				types.StringValue("tag:web"),
+				types.StringValue(" server "),
+				types.StringValue("web"),
+			)},
+			expected: tags(types.StringValue("tag:server"), types.StringValue("tag:web")),
+		},
+		{
+			name:      "empty",
+			arguments: []attr.Value{tags()},
+			expected:  tags(),
+		},
+		{
+			name:        "empty-tag",
+			arguments:   []attr.Value{tags(types.StringValue("tag:"))},
+			expectError: "tag at index 0 must not be empty",
+		},
+		{
+			name:        "null-tag",
+			arguments:   []attr.Value{tags(types.StringValue("tag:web"), types.StringNull())},
+			expectError: "tag at index 1 must not be null",
+		},
+	}
+
+	runFunctionTests(t, NewNormalizeTagsFunction(), testCases)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &via6Function{}
+
+// NewVia6Function returns a new via6 function.
+func NewVia6Function() function.Function {
+	return &via6Function{}
+}
+
+type via6Function struct{}
+
+// Metadata defines the function name as it appears in Terraform configurations.
+func (f *via6Function) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "via6"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *via6Function) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Calculates the 4via6 IPv6 prefix for a site ID and IPv4 CIDR",
+		MarkdownDescription: "Calculates the IPv6 prefix for a given site ID and IPv4 CIDR. This is equivalent to the `tailscale_4via6` data source. See Tailscale documentation for [4via6 subnets](https://tailscale.com/kb/1201/4via6-subnets/) for more details.",
+		Parameters: []function.Parameter{
+			function.Int32Parameter{
+				Name:        "site",
+				Description: "Site ID (between 0 and 65535)",
+				Validators: []function.Int32ParameterValidator{
+					int32validator.Between(0, 65535),
+				},
+			},
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The IPv4 CIDR to map",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run maps the IPv4 CIDR into the 4via6 range for the given site.
+func (f *via6Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var site int32
+	var cidr string
+	resp.Error = req.Arguments.Get(ctx, &site, &cidr)
+	if resp.Error != nil {
+		return
+	}
+
+	via, err := map4Via6(uint32(site), cidr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Failed to map 4via6 address: "+err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, via.String())
+}
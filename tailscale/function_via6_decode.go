@@ -0,0 +1,75 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &via6DecodeFunction{}
+
+// via6DecodeAttributeTypes describes the object returned by the via6_decode function.
+var via6DecodeAttributeTypes = map[string]attr.Type{
+	"site": types.Int64Type,
+	"cidr": types.StringType,
+}
+
+// NewVia6DecodeFunction returns a new via6_decode function.
+func NewVia6DecodeFunction() function.Function {
+	return &via6DecodeFunction{}
+}
+
+type via6DecodeFunction struct{}
+
+// Metadata defines the function name as it appears in Terraform configurations.
+func (f *via6DecodeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "via6_decode"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *via6DecodeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Extracts the site ID and IPv4 CIDR from a 4via6 IPv6 prefix",
+		MarkdownDescription: "Extracts the site ID and IPv4 CIDR from a 4via6 IPv6 prefix or address, reversing `via6`. Returns an object with `site` and `cidr` attributes. A bare address is treated as a single-address prefix. See Tailscale documentation for [4via6 subnets](https://tailscale.com/kb/1201/4via6-subnets/) for more details.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "ipv6",
+				Description: "The 4via6 mapped prefix or address",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: via6DecodeAttributeTypes,
+		},
+	}
+}
+
+// Run extracts the site ID and IPv4 CIDR from the 4via6 prefix.
+func (f *via6DecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var via string
+	resp.Error = req.Arguments.Get(ctx, &via)
+	if resp.Error != nil {
+		return
+	}
+
+	site, cidr, err := unmap4Via6(via)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to decode 4via6 address: "+err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(via6DecodeAttributeTypes, map[string]attr.Value{
+		"site": types.Int64Value(int64(site)),
+		"cidr": types.StringValue(cidr.String()),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, result)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVia6DecodeFunction(t *testing.T) {
+	decoded := func(site int64, cidr string) attr.Value {
+		return types.ObjectValueMust(via6DecodeAttributeTypes, map[string]attr.Value{
+			"site": types.Int64Value(site),
+			"cidr": types.StringValue(cidr),
+		})
+	}
+
+	testCases := []functionTestCase{
+		{
+			name:      "prefix",
+			arguments: []attr.Value{types.StringValue("fd7a:115c:a1e0:b1a:0:7:a01:100/120")},
+			expected:  decoded(7, "10.1.1.0/24"),
+		},
+		{
+			name:      "address",
+			arguments: []attr.Value{types.StringValue("fd7a:115c:a1e0:b1a:0:ffff:c0a8:101")},
+			expected:  decoded(65535, "192.168.1.1/32"),
+		},
+		{
+			name:        "outside-via-range",
+			arguments:   []attr.Value{types.StringValue("fd7a:115c:a1e0::1/128")},
+			expectError: "is not within the 4via6 range",
+		},
+		{
+			name:        "prefix-too-short",
+			arguments:   []attr.Value{types.StringValue("fd7a:115c:a1e0:b1a::/64")},
+			expectError: "must be at least /96",
+		},
+		{
+			name:        "invalid",
+			arguments:   []attr.Value{types.StringValue("not-an-address")},
+			expectError: `invalid IPv6 address or prefix "not-an-address"`,
+		},
+	}
+
+	runFunctionTests(t, NewVia6DecodeFunction(), testCases)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestVia6Function(t *testing.T) {
+	testCases := []functionTestCase{
+		{
+			name:      "valid",
+			arguments: []attr.Value{types.Int32Value(7), types.StringValue("10.1.1.0/24")},
+			expected:  types.StringValue("fd7a:115c:a1e0:b1a:0:7:a01:100/120"),
+		},
+		{
+			name:      "single-address",
+			arguments: []attr.Value{types.Int32Value(65535), types.StringValue("192.168.1.1/32")},
+			expected:  types.StringValue("fd7a:115c:a1e0:b1a:0:ffff:c0a8:101/128"),
+		},
+		{
+			name:        "invalid-cidr",
+			arguments:   []attr.Value{types.Int32Value(7), types.StringValue("not-a-cidr")},
+			expectError: `invalid CIDR "not-a-cidr"`,
+		},
+		{
+			name:        "ipv6-cidr",
+			arguments:   []attr.Value{types.Int32Value(7), types.StringValue("fd00::/64")},
+			expectError: "want IPv4 CIDR with a site ID",
+		},
+	}
+
+	runFunctionTests(t, NewVia6Function(), testCases)
+}
+
+func TestProvider_FunctionVia6(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		IsUnitTest: true,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV5ProviderFactories: testProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					output "via" {
+						value = provider::tailscale::via6(7, "10.1.1.0/24")
+					}
+
+					output "decoded" {
+						value = provider::tailscale::via6_decode(provider::tailscale::via6(7, "10.1.1.0/24"))
+					}`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("via", knownvalue.StringExact("fd7a:115c:a1e0:b1a:0:7:a01:100/120")),
+					statecheck.ExpectKnownOutputValue("decoded", knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"site": knownvalue.Int64Exact(7),
+						"cidr": knownvalue.StringExact("10.1.1.0/24"),
+					})),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tailnetIdentityModel is the identity of resources that manage a setting of
+// which there is one per tailnet.
+type tailnetIdentityModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+}
+
+// deviceIdentityModel is the identity of resources that manage a property of
+// a single device.
+type deviceIdentityModel struct {
+	Tailnet  types.String `tfsdk:"tailnet"`
+	DeviceID types.String `tfsdk:"device_id"`
+}
+
+// deviceTagIdentityModel is the identity of a single tag of a device.
+type deviceTagIdentityModel struct {
+	Tailnet  types.String `tfsdk:"tailnet"`
+	DeviceID types.String `tfsdk:"device_id"`
+	Tag      types.String `tfsdk:"tag"`
+}
+
+// deviceSubnetRouteIdentityModel is the identity of a single subnet route of a
+// device.
+type deviceSubnetRouteIdentityModel struct {
+	Tailnet  types.String `tfsdk:"tailnet"`
+	DeviceID types.String `tfsdk:"device_id"`
+	Route    types.String `tfsdk:"route"`
+}
+
+// idIdentityModel is the identity of resources that manage an object with an
+// ID assigned by Tailscale, such as a key, webhook or posture integration.
+type idIdentityModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+	ID      types.String `tfsdk:"id"`
+}
+
+// serviceIdentityModel is the identity of a Tailscale Service.
+type serviceIdentityModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+	Name    types.String `tfsdk:"name"`
+}
+
+// logstreamIdentityModel is the identity of a log streaming configuration.
+type logstreamIdentityModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+	LogType types.String `tfsdk:"log_type"`
+}
+
+// splitNameserversIdentityModel is the identity of the split DNS nameservers
+// for a domain.
+type splitNameserversIdentityModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+	Domain  types.String `tfsdk:"domain"`
+}
+
+// tailnetIdentityAttribute is the `tailnet` attribute shared by all identities.
+func tailnetIdentityAttribute() identityschema.StringAttribute {
+	return identityschema.StringAttribute{
+		Description:       "The tailnet ID that the object belongs to. `-` refers to the tailnet that owns the provider's credentials.",
+		RequiredForImport: true,
+	}
+}
+
+// identitySchema returns an identity schema made up of the tailnet and the
+// attribute that identifies an object within it.
+func identitySchema(idAttribute, idDescription string) identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"tailnet": tailnetIdentityAttribute(),
+			idAttribute: identityschema.StringAttribute{
+				Description:       idDescription,
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// identityTailnet returns the tailnet to record in an identity for an object
+// with the given tailnet attribute. Objects in the provider's tailnet record
+// the provider's tailnet, so that identities are always fully specified.
+func identityTailnet(data *providerData, tailnet types.String) types.String {
+	if !tailnet.IsNull() && !tailnet.IsUnknown() {
+		return tailnet
+	}
+	if data == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(data.Client.Tailnet)
+}
+
+// importStateWithIdentity imports a resource whose `id` attribute is also
+// stored in its identity as idAttribute. Resources can be imported with an
+// ID of the form `<id>` or `<tailnet>/<id>`, or by identity.
+func importStateWithIdentity(ctx context.Context, data *providerData, idAttribute string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != "" {
+		id := importTailnetAndID(ctx, req, resp)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+		return
+	}
+
+	var id types.String
+	resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root(idAttribute), &id)...)
+	importIdentityTailnet(ctx, data, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// importIdentityTailnet sets the `tailnet` attribute of a resource being
+// imported by identity. The attribute is left unset for objects in the
+// provider's tailnet, matching how they would be configured.
+func importIdentityTailnet(ctx context.Context, data *providerData, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var tailnet types.String
+	resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root("tailnet"), &tailnet)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data == nil || tailnet.ValueString() != data.Client.Tailnet {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tailnet"), tailnet)...)
+	}
+}
+
+// deviceMemberIdentitySchema returns the identity schema of resources that
+// manage a single element of a device's set, such as one of its tags, which
+// is stored in memberAttribute.
+func deviceMemberIdentitySchema(memberAttribute, memberDescription string) identityschema.Schema {
+	schema := identitySchema("device_id", "The device that the "+memberAttribute+" belongs to.")
+	schema.Attributes[memberAttribute] = identityschema.StringAttribute{
+		Description:       memberDescription,
+		RequiredForImport: true,
+	}
+	return schema
+}
+
+// importDeviceMember imports a resource that manages a single element of a
+// device's set, whose `id` attribute is `<device_id>:<member>`. Resources can
+// be imported with an ID of that form, optionally prefixed with a tailnet as
+// `<tailnet>/<device_id>:<member>`, or by identity.
+func importDeviceMember(ctx context.Context, data *providerData, memberAttribute string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var deviceID, member string
+	if req.ID != "" {
+		// Members such as routes may contain a slash, so the ID is split on
+		// the first colon before looking for a tailnet.
+		prefix, rest, ok := strings.Cut(req.ID, ":")
+		if !ok || prefix == "" || rest == "" {
+			resp.Diagnostics.AddError(
+				"Invalid import ID",
+				"Expected an import ID of the form <device_id>:<"+memberAttribute+"> or <tailnet>/<device_id>:<"+memberAttribute+">, got: "+req.ID,
+			)
+			return
+		}
+		deviceID = importTailnetAndID(ctx, resource.ImportStateRequest{ID: prefix}, resp)
+		member = rest
+	} else {
+		var id, m types.String
+		resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root("device_id"), &id)...)
+		resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root(memberAttribute), &m)...)
+		importIdentityTailnet(ctx, data, req, resp)
+		deviceID, member = id.ValueString(), m.ValueString()
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), deviceID+":"+member)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("device_id"), deviceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(memberAttribute), member)...)
+}
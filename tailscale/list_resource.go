@@ -0,0 +1,257 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// deviceListResourceModel is the configuration of list resources that list
+// devices. It matches the arguments of the devices data source.
+type deviceListResourceModel struct {
+	Tailnet    types.String      `tfsdk:"tailnet"`
+	NamePrefix types.String      `tfsdk:"name_prefix"`
+	Filters    []listFilterModel `tfsdk:"filter"`
+}
+
+// keyListResourceModel is the configuration of list resources that list keys.
+type keyListResourceModel struct {
+	Tailnet types.String      `tfsdk:"tailnet"`
+	Filters []listFilterModel `tfsdk:"filter"`
+}
+
+// listFilterModel is a filter block in a list resource. It mirrors the
+// filter block of the devices data source, but with a list of values as list
+// resource schemas do not support sets.
+type listFilterModel struct {
+	Name   types.String `tfsdk:"name"`
+	Values types.List   `tfsdk:"values"`
+}
+
+// tailnetListResourceAttribute returns the optional `tailnet` attribute that
+// is shared by all list resources.
+func tailnetListResourceAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: "The tailnet ID to list objects in. Defaults to the tailnet configured on the provider. The tailnet must be accessible with the credentials passed to the provider.",
+	}
+}
+
+// listFilterBlock returns the `filter` block of a list resource, where
+// properties are the kind of objects being listed, e.g. "device".
+func listFilterBlock(properties string) schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "Filters the results to those whose fields match the provided values. Results must match every filter.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Description: fmt.Sprintf("The name must be a top-level %s property, e.g. tags, description, etc.", properties),
+					Required:    true,
+				},
+				"values": schema.ListAttribute{
+					Description: "The list of values to filter for. Values are matched as exact matches.",
+					ElementType: types.StringType,
+					Required:    true,
+				},
+			},
+		},
+	}
+}
+
+// deviceListResourceSchema returns the schema of list resources that list
+// devices, with the given description.
+func deviceListResourceSchema(description string) schema.Schema {
+	return schema.Schema{
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetListResourceAttribute(),
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the device list to elements whose name has the provided prefix",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"filter": listFilterBlock("device"),
+		},
+	}
+}
+
+// keyListResourceSchema returns the schema of list resources that list keys,
+// with the given description.
+func keyListResourceSchema(description string) schema.Schema {
+	return schema.Schema{
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetListResourceAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"filter": listFilterBlock("key"),
+		},
+	}
+}
+
+// filterValues returns the values of each filter, keyed by the filter name.
+func filterValues(ctx context.Context, filters []listFilterModel, diags *diag.Diagnostics) map[string][]string {
+	values := make(map[string][]string, len(filters))
+	for _, f := range filters {
+		var v []string
+		diags.Append(f.Values.ElementsAs(ctx, &v, false)...)
+		values[f.Name.ValueString()] = v
+	}
+	return values
+}
+
+// matchesFilters reports whether obj matches every filter. Filters are matched
+// against the top-level properties of obj as they appear in the API. A list
+// property matches if any of its elements match.
+func matchesFilters(obj any, filters map[string][]string) (bool, error) {
+	if len(filters) == 0 {
+		return true, nil
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return false, err
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &properties); err != nil {
+		return false, err
+	}
+
+	for name, values := range filters {
+		property, ok := properties[name]
+		if !ok {
+			return false, nil
+		}
+
+		var elements []json.RawMessage
+		if json.Unmarshal(property, &elements) != nil {
+			elements = []json.RawMessage{property}
+		}
+
+		if !slices.ContainsFunc(elements, func(element json.RawMessage) bool {
+			return slices.Contains(values, filterValueString(element))
+		}) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// filterValueString returns the string form of a JSON value for comparison
+// with filter values, so that strings are compared without quotes and other
+// values are compared as written, e.g. `true` or `42`.
+func filterValueString(v json.RawMessage) string {
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		return s
+	}
+	return string(v)
+}
+
+// listDevices returns the devices matching the configuration of a device list
+// resource. As with the devices data source, filters are applied by the API.
+func listDevices(ctx context.Context, client *tailscale.Client, config deviceListResourceModel, diags *diag.Diagnostics) []tailscale.Device {
+	filters := filterValues(ctx, config.Filters, diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	opts := make([]tailscale.ListDevicesOptions, 0, len(filters))
+	for name, values := range filters {
+		opts = append(opts, tailscale.WithFilter(name, values))
+	}
+
+	devices, err := client.Devices().List(ctx, opts...)
+	if err != nil {
+		diags.AddError("Failed to fetch devices", err.Error())
+		return nil
+	}
+
+	prefix := config.NamePrefix.ValueString()
+	return slices.DeleteFunc(devices, func(device tailscale.Device) bool {
+		return !strings.HasPrefix(device.Name, prefix)
+	})
+}
+
+// listKeys returns the keys of the given type that match the filters. Listing
+// keys only returns their IDs, so each key is fetched as it is yielded.
+func listKeys(ctx context.Context, client *tailscale.Client, keyType string, filters map[string][]string) iter.Seq2[*tailscale.Key, error] {
+	return func(yield func(*tailscale.Key, error) bool) {
+		keys, err := client.Keys().List(ctx, true)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for _, k := range keys {
+			key, err := client.Keys().Get(ctx, k.ID)
+			if tailscale.IsNotFound(err) {
+				// The key was deleted since it was listed.
+				continue
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if key.KeyType != keyType {
+				continue
+			}
+
+			ok, err := matchesFilters(key, filters)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if ok && !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+// limitResults stops results after limit have been pushed, if limit is set.
+func limitResults(results iter.Seq[list.ListResult], limit int64) iter.Seq[list.ListResult] {
+	if limit <= 0 {
+		return results
+	}
+	return func(push func(list.ListResult) bool) {
+		var n int64
+		for result := range results {
+			if !push(result) {
+				return
+			}
+			if n++; n >= limit {
+				return
+			}
+		}
+	}
+}
+
+// listError returns a list result holding a single error diagnostic.
+func listError(summary string, err error) list.ListResult {
+	var diags diag.Diagnostics
+	diags.AddError(summary, err.Error())
+	return list.ListResult{Diagnostics: diags}
+}
+
+// keyDisplayName returns a human-readable name for a listed key.
+func keyDisplayName(key *tailscale.Key) string {
+	if key.Description == "" {
+		return key.ID
+	}
+	return fmt.Sprintf("%s (%s)", key.Description, key.ID)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &deviceKeyListResource{}
+	_ list.ListResourceWithConfigure = &deviceKeyListResource{}
+)
+
+// NewDeviceKeyListResource returns a new device key list resource.
+func NewDeviceKeyListResource() list.ListResource {
+	return &deviceKeyListResource{}
+}
+
+// deviceKeyListResource lists the key properties of devices, so that they can
+// be imported as tailscale_device_key resources.
+type deviceKeyListResource struct {
+	deviceKeyResource
+}
+
+// ListResourceConfigSchema defines the arguments of the list resource.
+func (d *deviceKeyListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = deviceListResourceSchema("Lists devices in a tailnet, so that their key settings can be imported as tailscale_device_key resources.")
+}
+
+// List lists the key properties of every device that matches the configuration.
+func (d *deviceKeyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config deviceListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	devices := listDevices(ctx, d.ClientForTailnet(config.Tailnet), config, &diags)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = limitResults(func(push func(list.ListResult) bool) {
+		for _, device := range devices {
+			result := req.NewListResult(ctx)
+			result.DisplayName = device.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, deviceIdentityModel{
+				Tailnet:  d.IdentityTailnet(config.Tailnet),
+				DeviceID: types.StringValue(device.NodeID),
+			})...)
+
+			if req.IncludeResource {
+				result.Diagnostics.Append(result.Resource.Set(ctx, deviceKeyResourceModel{
+					ID:                types.StringValue(device.NodeID),
+					DeviceID:          types.StringValue(device.NodeID),
+					KeyExpiryDisabled: types.BoolValue(device.KeyExpiryDisabled),
+					Tailnet:           config.Tailnet,
+				})...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}, req.Limit)
+}
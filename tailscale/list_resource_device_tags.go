@@ -0,0 +1,75 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &deviceTagsListResource{}
+	_ list.ListResourceWithConfigure = &deviceTagsListResource{}
+)
+
+// NewDeviceTagsListResource returns a new device tags list resource.
+func NewDeviceTagsListResource() list.ListResource {
+	return &deviceTagsListResource{}
+}
+
+// deviceTagsListResource lists the tags of devices, so that they can be
+// imported as tailscale_device_tags resources.
+type deviceTagsListResource struct {
+	deviceTagsResource
+}
+
+// ListResourceConfigSchema defines the arguments of the list resource.
+func (d *deviceTagsListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = deviceListResourceSchema("Lists devices in a tailnet, so that their tags can be imported as tailscale_device_tags resources.")
+}
+
+// List lists the tags of every device that matches the configuration.
+func (d *deviceTagsListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config deviceListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	devices := listDevices(ctx, d.ClientForTailnet(config.Tailnet), config, &diags)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = limitResults(func(push func(list.ListResult) bool) {
+		for _, device := range devices {
+			result := req.NewListResult(ctx)
+			result.DisplayName = device.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, deviceIdentityModel{
+				Tailnet:  d.IdentityTailnet(config.Tailnet),
+				DeviceID: types.StringValue(device.NodeID),
+			})...)
+
+			if req.IncludeResource {
+				if device.Tags == nil {
+					device.Tags = []string{}
+				}
+				result.Diagnostics.Append(result.Resource.Set(ctx, deviceTagsResourceModel{
+					ID:       types.StringValue(device.NodeID),
+					DeviceID: types.StringValue(device.NodeID),
+					Tags:     SetOfStringValue(ctx, device.Tags, &result.Diagnostics),
+					Tailnet:  config.Tailnet,
+				})...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}, req.Limit)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &federatedIdentityListResource{}
+	_ list.ListResourceWithConfigure = &federatedIdentityListResource{}
+)
+
+// NewFederatedIdentityListResource returns a new federated identity list resource.
+func NewFederatedIdentityListResource() list.ListResource {
+	return &federatedIdentityListResource{}
+}
+
+// federatedIdentityListResource lists federated identities, so that they can be imported as
+// tailscale_federated_identity resources.
+type federatedIdentityListResource struct {
+	federatedIdentityResource
+}
+
+// ListResourceConfigSchema defines the arguments of the list resource.
+func (r *federatedIdentityListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = keyListResourceSchema("Lists federated identities in a tailnet, so that they can be imported as tailscale_federated_identity resources.")
+}
+
+// List lists every federated identity that matches the configuration.
+func (r *federatedIdentityListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config keyListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	filters := filterValues(ctx, config.Filters, &diags)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	keys := listKeys(ctx, r.ClientForTailnet(config.Tailnet), "federated", filters)
+	stream.Results = limitResults(func(push func(list.ListResult) bool) {
+		for key, err := range keys {
+			if err != nil {
+				push(listError("Failed to list federated identities", err))
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = keyDisplayName(key)
+			result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{
+				Tailnet: r.IdentityTailnet(config.Tailnet),
+				ID:      types.StringValue(key.ID),
+			})...)
+
+			if req.IncludeResource {
+				model := federatedIdentityResourceModel{Tailnet: config.Tailnet}
+				result.Diagnostics.Append(r.populateFromKey(ctx, &model, key)...)
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}, req.Limit)
+}
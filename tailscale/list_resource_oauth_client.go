@@ -0,0 +1,70 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &oauthClientListResource{}
+	_ list.ListResourceWithConfigure = &oauthClientListResource{}
+)
+
+// NewOAuthClientListResource returns a new OAuth client list resource.
+func NewOAuthClientListResource() list.ListResource {
+	return &oauthClientListResource{}
+}
+
+// oauthClientListResource lists OAuth clients, so that they can be imported as
+// tailscale_oauth_client resources.
+type oauthClientListResource struct {
+	oauthClientResource
+}
+
+// ListResourceConfigSchema defines the arguments of the list resource.
+func (r *oauthClientListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = keyListResourceSchema("Lists OAuth clients in a tailnet, so that they can be imported as tailscale_oauth_client resources.")
+}
+
+// List lists every OAuth client that matches the configuration.
+func (r *oauthClientListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config keyListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	filters := filterValues(ctx, config.Filters, &diags)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	keys := listKeys(ctx, r.ClientForTailnet(config.Tailnet), "client", filters)
+	stream.Results = limitResults(func(push func(list.ListResult) bool) {
+		for key, err := range keys {
+			if err != nil {
+				push(listError("Failed to list OAuth clients", err))
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = keyDisplayName(key)
+			result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{
+				Tailnet: r.IdentityTailnet(config.Tailnet),
+				ID:      types.StringValue(key.ID),
+			})...)
+
+			if req.IncludeResource {
+				model := oauthClientResourceModel{Tailnet: config.Tailnet}
+				model.populateFromKey(ctx, key, &result.Diagnostics)
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}, req.Limit)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &serviceListResource{}
+	_ list.ListResourceWithConfigure = &serviceListResource{}
+)
+
+type serviceListResourceModel struct {
+	Tailnet    types.String      `tfsdk:"tailnet"`
+	NamePrefix types.String      `tfsdk:"name_prefix"`
+	Filters    []listFilterModel `tfsdk:"filter"`
+}
+
+// NewServiceListResource returns a new Service list resource.
+func NewServiceListResource() list.ListResource {
+	return &serviceListResource{}
+}
+
+// serviceListResource lists Tailscale Services, so that they can be imported
+// as tailscale_service resources.
+type serviceListResource struct {
+	serviceResource
+}
+
+// ListResourceConfigSchema defines the arguments of the list resource.
+func (r *serviceListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists Tailscale Services in a tailnet, so that they can be imported as tailscale_service resources.",
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetListResourceAttribute(),
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Filters the Service list to elements whose name has the provided prefix, e.g. `svc:web-`.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"filter": listFilterBlock("Service"),
+		},
+	}
+}
+
+// List lists every Service that matches the configuration.
+func (r *serviceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config serviceListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	filters := filterValues(ctx, config.Filters, &diags)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	services, err := r.ClientForTailnet(config.Tailnet).VIPServices().List(ctx)
+	if err != nil {
+		diags.AddError("Failed to fetch Services", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	prefix := config.NamePrefix.ValueString()
+	stream.Results = limitResults(func(push func(list.ListResult) bool) {
+		for _, svc := range services {
+			if !strings.HasPrefix(svc.Name, prefix) {
+				continue
+			}
+			ok, err := matchesFilters(svc, filters)
+			if err != nil {
+				push(listError("Failed to filter Services", err))
+				return
+			}
+			if !ok {
+				continue
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = svc.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, serviceIdentityModel{
+				Tailnet: r.IdentityTailnet(config.Tailnet),
+				Name:    types.StringValue(svc.Name),
+			})...)
+
+			if req.IncludeResource {
+				model := serviceResourceModel{Tailnet: config.Tailnet}
+				model.populateFromService(ctx, &svc, &result.Diagnostics)
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}, req.Limit)
+}
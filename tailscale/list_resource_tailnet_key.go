@@ -0,0 +1,70 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &tailnetKeyListResource{}
+	_ list.ListResourceWithConfigure = &tailnetKeyListResource{}
+)
+
+// NewTailnetKeyListResource returns a new tailnet key list resource.
+func NewTailnetKeyListResource() list.ListResource {
+	return &tailnetKeyListResource{}
+}
+
+// tailnetKeyListResource lists auth keys, so that they can be imported as
+// tailscale_tailnet_key resources.
+type tailnetKeyListResource struct {
+	tailnetKeyResource
+}
+
+// ListResourceConfigSchema defines the arguments of the list resource.
+func (r *tailnetKeyListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = keyListResourceSchema("Lists auth keys in a tailnet, so that they can be imported as tailscale_tailnet_key resources.")
+}
+
+// List lists every tailnet key that matches the configuration.
+func (r *tailnetKeyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config keyListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	filters := filterValues(ctx, config.Filters, &diags)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	keys := listKeys(ctx, r.ClientForTailnet(config.Tailnet), "auth", filters)
+	stream.Results = limitResults(func(push func(list.ListResult) bool) {
+		for key, err := range keys {
+			if err != nil {
+				push(listError("Failed to list keys", err))
+				return
+			}
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = keyDisplayName(key)
+			result.Diagnostics.Append(result.Identity.Set(ctx, idIdentityModel{
+				Tailnet: r.IdentityTailnet(config.Tailnet),
+				ID:      types.StringValue(key.ID),
+			})...)
+
+			if req.IncludeResource {
+				model := tailnetKeyResourceModel{Tailnet: config.Tailnet}
+				model.populateFromKey(ctx, key, &result.Diagnostics)
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}, req.Limit)
+}
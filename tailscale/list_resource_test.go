@@ -0,0 +1,139 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+func TestMatchesFilters(t *testing.T) {
+	key := tailscale.Key{
+		ID:          "k123",
+		Description: "ci runners",
+		Invalid:     false,
+		Tags:        []string{"tag:ci", "tag:server"},
+	}
+	key.Capabilities.Devices.Create.Reusable = true
+
+	testCases := []struct {
+		name     string
+		filters  map[string][]string
+		expected bool
+	}{
+		{
+			name:     "no-filters",
+			filters:  nil,
+			expected: true,
+		},
+		{
+			name:     "string-property",
+			filters:  map[string][]string{"description": {"ci runners"}},
+			expected: true,
+		},
+		{
+			name:     "any-value",
+			filters:  map[string][]string{"id": {"k456", "k123"}},
+			expected: true,
+		},
+		{
+			name:     "list-property",
+			filters:  map[string][]string{"tags": {"tag:server"}},
+			expected: true,
+		},
+		{
+			name:     "bool-property",
+			filters:  map[string][]string{"invalid": {"false"}},
+			expected: true,
+		},
+		{
+			name:     "no-match",
+			filters:  map[string][]string{"tags": {"tag:prod"}},
+			expected: false,
+		},
+		{
+			name:     "every-filter",
+			filters:  map[string][]string{"tags": {"tag:ci"}, "id": {"k456"}},
+			expected: false,
+		},
+		{
+			name:     "unknown-property",
+			filters:  map[string][]string{"hostname": {"ci"}},
+			expected: false,
+		},
+		{
+			name:     "nested-property",
+			filters:  map[string][]string{"reusable": {"true"}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := matchesFilters(key, tt.filters)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestProvider_TailscaleDeviceTagsList(t *testing.T) {
+	const testDeviceTagsList = `
+		provider "tailscale" {}
+
+		list "tailscale_device_tags" "test" {
+			provider = tailscale
+
+			config {
+				name_prefix = "web"
+			}
+		}`
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest: true,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		PreCheck: func() {
+			testServer.HandleRequest = func(method, path string) TestResponse {
+				return TestResponse{
+					Code: http.StatusOK,
+					Body: map[string][]tailscale.Device{
+						"devices": {
+							{NodeID: "n1CNTRL", Name: "web-1.example.ts.net", Tags: []string{"tag:web"}},
+							{NodeID: "n2CNTRL", Name: "web-2.example.ts.net", Tags: []string{"tag:web"}},
+							{NodeID: "n3CNTRL", Name: "db-1.example.ts.net", Tags: []string{"tag:db"}},
+						},
+					},
+				}
+			}
+		},
+		ProtoV5ProviderFactories: testProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Query:  true,
+				Config: testDeviceTagsList,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("tailscale_device_tags.test", 2),
+					querycheck.ExpectIdentity("tailscale_device_tags.test", map[string]knownvalue.Check{
+						"tailnet":   knownvalue.StringExact("-"),
+						"device_id": knownvalue.StringExact("n1CNTRL"),
+					}),
+					querycheck.ExpectIdentity("tailscale_device_tags.test", map[string]knownvalue.Check{
+						"tailnet":   knownvalue.StringExact("-"),
+						"device_id": knownvalue.StringExact("n2CNTRL"),
+					}),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tailscale/hujson"
+)
+
+// apiLogSubsystem is the tflog subsystem that API requests are logged to. Its
+// level can be set independently with the TF_LOG_PROVIDER_TAILSCALE_API
+// environment variable.
+const apiLogSubsystem = "api"
+
+// requestIDHeader is the response header holding the ID of an API request.
+const requestIDHeader = "X-Request-Id"
+
+// redactedValue replaces the values of sensitive fields in logged bodies.
+const redactedValue = "***"
+
+// sensitiveBodyFields are the fields whose values are masked in logged bodies.
+// Names are normalized with [normalizeFieldName], so that both snake_case and
+// camelCase variants are matched.
+var sensitiveBodyFields = map[string]bool{
+	"key":               true,
+	"secret":            true,
+	"token":             true,
+	"clientsecret":      true,
+	"s3secretaccesskey": true,
+	"gcscredentials":    true,
+	"accesstoken":       true,
+	"idtoken":           true,
+	"refreshtoken":      true,
+	"jwt":               true,
+}
+
+// withAPILogging returns a copy of client whose requests are logged to the
+// [apiLogSubsystem]. A nil client is treated as [http.DefaultClient].
+func withAPILogging(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	logged := *client
+	logged.Transport = &loggingTransport{base: base}
+	return &logged
+}
+
+// loggingTransport is an [http.RoundTripper] that logs each request and its
+// response. Summaries are logged at DEBUG level, and bodies with any sensitive
+// fields masked are logged at TRACE level.
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip logs and sends the request.
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := newAPILogContext(req.Context())
+
+	fields := map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ := io.ReadAll(body)
+			body.Close()
+			tflog.SubsystemTrace(ctx, apiLogSubsystem, "Tailscale API request body", map[string]any{
+				"method": req.Method,
+				"path":   req.URL.Path,
+				"body":   redactBody(reqBody),
+			})
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["latency"] = time.Since(start).String()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "Tailscale API request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	if id := resp.Header.Get(requestIDHeader); id != "" {
+		fields["request_id"] = id
+	}
+	tflog.SubsystemDebug(ctx, apiLogSubsystem, "Tailscale API request", fields)
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return resp, nil
+	}
+
+	tflog.SubsystemTrace(ctx, apiLogSubsystem, "Tailscale API response body", map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+		"status": resp.StatusCode,
+		"body":   redactBody(respBody),
+	})
+	return resp, nil
+}
+
+// newAPILogContext returns a context for logging to the [apiLogSubsystem].
+func newAPILogContext(ctx context.Context) context.Context {
+	return tflog.NewSubsystem(ctx, apiLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_TAILSCALE", apiLogSubsystem))
+}
+
+// redactBody returns the body as a string for logging, with the values of any
+// sensitive fields masked. Bodies that cannot be parsed as JSON or HuJSON are
+// omitted, as they cannot be safely redacted.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	standardized, err := hujson.Standardize(bytes.Clone(body))
+	if err != nil {
+		return "[unparseable body omitted]"
+	}
+
+	var value any
+	if err := json.Unmarshal(standardized, &value); err != nil {
+		return "[unparseable body omitted]"
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[unparseable body omitted]"
+	}
+	return string(redacted)
+}
+
+// redactValue masks the values of sensitive fields in a decoded JSON value.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for field, fieldValue := range v {
+			if sensitiveBodyFields[normalizeFieldName(field)] {
+				v[field] = redactedValue
+				continue
+			}
+			v[field] = redactValue(fieldValue)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// normalizeFieldName lowercases a field name and removes underscores, so that
+// `client_secret` and `clientSecret` are treated the same.
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
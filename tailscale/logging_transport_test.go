@@ -0,0 +1,171 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+func TestRedactBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "empty",
+			body:     "",
+			expected: "",
+		},
+		{
+			name:     "auth-key",
+			body:     `{"id":"k123","key":"tskey-auth-secret","tags":["tag:server"]}`,
+			expected: `{"id":"k123","key":"***","tags":["tag:server"]}`,
+		},
+		{
+			name:     "nested-camel-case",
+			body:     `{"config":{"clientSecret":"s","s3SecretAccessKey":"s","gcsCredentials":"s","token":"s"},"items":[{"secret":"s"}]}`,
+			expected: `{"config":{"clientSecret":"***","gcsCredentials":"***","s3SecretAccessKey":"***","token":"***"},"items":[{"secret":"***"}]}`,
+		},
+		{
+			name:     "snake-case",
+			body:     `{"client_secret":"s","s3_secret_access_key":"s","gcs_credentials":"s"}`,
+			expected: `{"client_secret":"***","gcs_credentials":"***","s3_secret_access_key":"***"}`,
+		},
+		{
+			name:     "oauth-tokens",
+			body:     `{"access_token":"s","id_token":"s","refresh_token":"s","jwt":"s","token_type":"Bearer","expires_in":3600}`,
+			expected: `{"access_token":"***","expires_in":3600,"id_token":"***","jwt":"***","refresh_token":"***","token_type":"Bearer"}`,
+		},
+		{
+			name:     "hujson",
+			body:     "{\n// Comment\n\"groups\": {\"group:eng\": [\"alice@example.com\"],},\n}",
+			expected: `{"groups":{"group:eng":["alice@example.com"]}}`,
+		},
+		{
+			name:     "unparseable",
+			body:     "tskey-auth-secret",
+			expected: "[unparseable body omitted]",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactBody([]byte(tt.body)))
+		})
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	baseURL, server := NewTestHarness(t)
+	parsedBaseURL, err := url.Parse(baseURL)
+	assert.NoError(t, err)
+
+	server.SetResponses([]TestResponse{
+		{
+			Code:   http.StatusOK,
+			Body:   tailscale.Key{ID: "k123", Key: "tskey-auth-secret"},
+			Header: http.Header{requestIDHeader: {"req-123"}},
+		},
+	})
+
+	client := tailscale.Client{
+		BaseURL: parsedBaseURL,
+		APIKey:  "api_123",
+		HTTP:    withAPILogging(nil),
+	}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	key, err := client.Keys().CreateAuthKey(ctx, tailscale.CreateKeyRequest{Description: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tskey-auth-secret", key.Key, "the response body should be passed through unchanged")
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	assert.NoError(t, err)
+
+	byMessage := make(map[string]map[string]any)
+	for _, entry := range entries {
+		assert.Equal(t, "provider."+apiLogSubsystem, entry["@module"], "entries should be logged to the API subsystem")
+		byMessage[entry["@message"].(string)] = entry
+	}
+
+	summary := byMessage["Tailscale API request"]
+	if assert.NotNil(t, summary) {
+		assert.Equal(t, "debug", summary["@level"])
+		assert.Equal(t, http.MethodPost, summary["method"])
+		assert.Equal(t, "/api/v2/tailnet/-/keys", summary["path"])
+		assert.Equal(t, float64(http.StatusOK), summary["status"])
+		assert.Equal(t, "req-123", summary["request_id"])
+		assert.NotEmpty(t, summary["latency"])
+	}
+
+	requestBody := byMessage["Tailscale API request body"]
+	if assert.NotNil(t, requestBody) {
+		assert.Equal(t, "trace", requestBody["@level"])
+		assert.Contains(t, requestBody["body"], `"description":"test"`)
+	}
+
+	responseBody := byMessage["Tailscale API response body"]
+	if assert.NotNil(t, responseBody) {
+		assert.Equal(t, "trace", responseBody["@level"])
+		assert.Contains(t, responseBody["body"], `"key":"***"`)
+	}
+
+	assert.NotContains(t, output.String(), "tskey-auth-secret")
+}
+
+func TestLoggingTransportTokenExchange(t *testing.T) {
+	baseURL, server := NewTestHarness(t)
+
+	server.SetResponses([]TestResponse{
+		{
+			Code: http.StatusOK,
+			Body: map[string]any{
+				"access_token": "tskey-api-secret",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			},
+		},
+	})
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	form := url.Values{"client_id": {"client123"}, "jwt": {"id-token-secret"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/v2/oauth/token-exchange", strings.NewReader(form.Encode()))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := withAPILogging(nil).Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	assert.NoError(t, err)
+
+	var responseBody map[string]any
+	for _, entry := range entries {
+		if entry["@message"] == "Tailscale API response body" {
+			responseBody = entry
+		}
+	}
+	if assert.NotNil(t, responseBody) {
+		assert.Contains(t, responseBody["body"], `"access_token":"***"`)
+	}
+
+	assert.NotContains(t, output.String(), "tskey-api-secret")
+	assert.NotContains(t, output.String(), "id-token-secret")
+}
@@ -0,0 +1,287 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// policyDocument is the subset of a tailnet policy file that the provider
+// understands. Unlike [tailscale.ACL], the order of its fields is the order in
+// which sections are rendered, and values are kept as they are written in the
+// policy file, so that a policy can be rendered deterministically and parsed
+// back without changes.
+type policyDocument struct {
+	Groups        map[string][]string  `json:"groups,omitempty"`
+	TagOwners     map[string][]string  `json:"tagOwners,omitempty"`
+	Hosts         map[string]string    `json:"hosts,omitempty"`
+	IPSets        map[string][]string  `json:"ipsets,omitempty"`
+	Postures      map[string][]string  `json:"postures,omitempty"`
+	ACLs          []policyACL          `json:"acls,omitempty"`
+	Grants        []policyGrant        `json:"grants,omitempty"`
+	SSH           []policySSH          `json:"ssh,omitempty"`
+	NodeAttrs     []policyNodeAttr     `json:"nodeAttrs,omitempty"`
+	AutoApprovers *policyAutoApprovers `json:"autoApprovers,omitempty"`
+	Tests         []policyTest         `json:"tests,omitempty"`
+}
+
+type policyACL struct {
+	Action     string   `json:"action"`
+	Source     []string `json:"src"`
+	Proto      string   `json:"proto,omitempty"`
+	Dest       []string `json:"dst"`
+	SrcPosture []string `json:"srcPosture,omitempty"`
+
+	// Users and Ports are legacy names for Source and Dest, which are
+	// merged into them when a policy is parsed.
+	Users []string `json:"users,omitempty"`
+	Ports []string `json:"ports,omitempty"`
+}
+
+type policyGrant struct {
+	Source     []string        `json:"src"`
+	Dest       []string        `json:"dst"`
+	IP         []string        `json:"ip,omitempty"`
+	Via        []string        `json:"via,omitempty"`
+	SrcPosture []string        `json:"srcPosture,omitempty"`
+	App        json.RawMessage `json:"app,omitempty"`
+}
+
+type policySSH struct {
+	Action          string   `json:"action"`
+	Source          []string `json:"src"`
+	Dest            []string `json:"dst"`
+	Users           []string `json:"users"`
+	CheckPeriod     string   `json:"checkPeriod,omitempty"`
+	AcceptEnv       []string `json:"acceptEnv,omitempty"`
+	Recorder        []string `json:"recorder,omitempty"`
+	EnforceRecorder bool     `json:"enforceRecorder,omitempty"`
+	SrcPosture      []string `json:"srcPosture,omitempty"`
+}
+
+type policyNodeAttr struct {
+	Target []string        `json:"target"`
+	Attr   []string        `json:"attr,omitempty"`
+	IPPool []string        `json:"ipPool,omitempty"`
+	App    json.RawMessage `json:"app,omitempty"`
+}
+
+type policyAutoApprovers struct {
+	Routes   map[string][]string `json:"routes,omitempty"`
+	ExitNode []string            `json:"exitNode,omitempty"`
+	Services map[string][]string `json:"services,omitempty"`
+}
+
+type policyTest struct {
+	Source string   `json:"src"`
+	Proto  string   `json:"proto,omitempty"`
+	Accept []string `json:"accept,omitempty"`
+	Deny   []string `json:"deny,omitempty"`
+
+	// User and Allow are legacy names for Source and Accept.
+	User  string   `json:"user,omitempty"`
+	Allow []string `json:"allow,omitempty"`
+}
+
+// policyDocumentSections are the top-level keys of a policy file that are
+// represented in a [policyDocument].
+var policyDocumentSections = []string{
+	"groups", "tagOwners", "hosts", "ipsets", "postures", "acls", "grants",
+	"ssh", "nodeAttrs", "autoApprovers", "tests",
+}
+
+// policyLegacyFields are the legacy names of the fields of the rules in each
+// section of a policy file, by the name of the field they are merged into.
+var policyLegacyFields = map[string]map[string]string{
+	"acls":  {"users": "src", "ports": "dst"},
+	"tests": {"user": "src", "allow": "accept"},
+}
+
+// parsePolicyDocument parses a HuJSON policy file. It also returns the paths of
+// the fields of the policy file that are not represented in a
+// [policyDocument], such as `derpMap` or `ssh[0].sessionDuration`, which would
+// be lost if it was rendered again.
+func parsePolicyDocument(policy string) (*policyDocument, []string, error) {
+	b, err := hujson.Standardize([]byte(policy))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var doc policyDocument
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range doc.ACLs {
+		acl := &doc.ACLs[i]
+		acl.Source = append(acl.Source, acl.Users...)
+		acl.Dest = append(acl.Dest, acl.Ports...)
+		acl.Users, acl.Ports = nil, nil
+	}
+	for i := range doc.Tests {
+		test := &doc.Tests[i]
+		if test.Source == "" {
+			test.Source = test.User
+		}
+		test.Accept = append(test.Accept, test.Allow...)
+		test.User, test.Allow = "", nil
+	}
+
+	unsupported, err := doc.droppedFields(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &doc, unsupported, nil
+}
+
+// droppedFields compares the standardized policy file that doc was parsed from
+// with the policy file rendered from doc, and returns the paths of the fields
+// that were dropped.
+func (doc *policyDocument) droppedFields(original []byte) ([]string, error) {
+	rendered, err := doc.render()
+	if err != nil {
+		return nil, err
+	}
+	b, err := hujson.Standardize([]byte(rendered))
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to any
+	if err := json.Unmarshal(original, &from); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &to); err != nil {
+		return nil, err
+	}
+
+	dropped := droppedPolicyFields("", nil, from, to)
+	slices.Sort(dropped)
+	return dropped, nil
+}
+
+// droppedPolicyFields returns the paths of the fields of from that are missing
+// in to. Keys are case-insensitive, as they are for [json.Unmarshal], fields
+// that are missing under one of their legacy names are looked up by the name
+// they are merged into, and empty fields are ignored, as they are not
+// rendered.
+func droppedPolicyFields(path string, legacy map[string]string, from, to any) []string {
+	var dropped []string
+	switch from := from.(type) {
+	case map[string]any:
+		to, _ := to.(map[string]any)
+		for key, value := range from {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			rendered, ok := lookupPolicyField(to, key)
+			if !ok {
+				if name, isLegacy := legacy[strings.ToLower(key)]; isLegacy {
+					_, ok = lookupPolicyField(to, name)
+				}
+			}
+			switch {
+			case !ok && !isEmptyPolicyValue(value):
+				dropped = append(dropped, fieldPath)
+			case ok && path == "":
+				// The rules in each section have their own legacy names.
+				dropped = append(dropped, droppedPolicyFields(fieldPath, policyLegacyFields[strings.ToLower(key)], value, rendered)...)
+			case ok:
+				dropped = append(dropped, droppedPolicyFields(fieldPath, nil, value, rendered)...)
+			}
+		}
+	case []any:
+		to, _ := to.([]any)
+		for i, value := range from {
+			if i < len(to) {
+				dropped = append(dropped, droppedPolicyFields(fmt.Sprintf("%s[%d]", path, i), legacy, value, to[i])...)
+			}
+		}
+	}
+	return dropped
+}
+
+// lookupPolicyField returns the value of the field of object with the given
+// case-insensitive name.
+func lookupPolicyField(object map[string]any, name string) (any, bool) {
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// isEmptyPolicyValue reports whether value is null, false, or an empty string,
+// list or object, which are omitted when a policy file is rendered.
+func isEmptyPolicyValue(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !value
+	case string:
+		return value == ""
+	case []any:
+		return len(value) == 0
+	case map[string]any:
+		return len(value) == 0
+	}
+	return false
+}
+
+// render returns the policy as HuJSON. The output only depends on the content
+// of the policy: sections and map keys are always in the same order, objects
+// are spread over multiple lines and lists of strings are kept on one line.
+func (doc *policyDocument) render() (string, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	v, err := hujson.Parse(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse rendered policy: %w", err)
+	}
+	expandPolicyValue(&v)
+	v.Format()
+
+	return v.String(), nil
+}
+
+// expandPolicyValue spreads objects, and lists of objects, over multiple lines
+// by adding a newline before each of their elements. [hujson.Value.Format]
+// keeps values on a single line unless they already span multiple lines.
+func expandPolicyValue(v *hujson.Value) {
+	newline := hujson.Extra("\n")
+
+	switch value := v.Value.(type) {
+	case *hujson.Object:
+		if len(value.Members) == 0 {
+			return
+		}
+		for i := range value.Members {
+			value.Members[i].Name.BeforeExtra = newline
+			expandPolicyValue(&value.Members[i].Value)
+		}
+		value.AfterExtra = newline
+	case *hujson.Array:
+		if len(value.Elements) == 0 || value.Elements[0].Value.Kind() != '{' {
+			return
+		}
+		for i := range value.Elements {
+			value.Elements[i].BeforeExtra = newline
+			expandPolicyValue(&value.Elements[i])
+		}
+		value.AfterExtra = newline
+	}
+}
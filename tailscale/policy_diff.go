@@ -0,0 +1,221 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// maxPolicyDiffLines is the maximum number of changes listed by
+// [formatPolicyDiff], so that rewriting a large policy file doesn't bury the
+// rest of the plan.
+const maxPolicyDiffLines = 50
+
+// diffPolicyDocuments summarises the changes between two policy files as one
+// line per added, removed or changed group, tag owner, ACL rule, grant and SSH
+// rule, such as "grant added: group:eng -> tag:db:5432". SSH rules are
+// evaluated in order, so changes to their order are also reported. Changes to
+// comments, formatting, the order of map keys and the order of other rules are
+// ignored.
+func diffPolicyDocuments(from, to *policyDocument) []string {
+	var changes []string
+	changes = append(changes, diffPolicyMap("group", "members", from.Groups, to.Groups)...)
+	changes = append(changes, diffPolicyMap("tag", "owners", from.TagOwners, to.TagOwners)...)
+	changes = append(changes, diffPolicyRules("ACL rule", from.ACLs, to.ACLs, func(acl policyACL) []string { return acl.Source }, describePolicyACL)...)
+	changes = append(changes, diffPolicyRules("grant", from.Grants, to.Grants, func(grant policyGrant) []string { return grant.Source }, describePolicyGrant)...)
+	changes = append(changes, diffPolicyRules("SSH rule", from.SSH, to.SSH, func(ssh policySSH) []string { return ssh.Source }, describePolicySSH)...)
+	if policyRulesReordered(from.SSH, to.SSH) {
+		changes = append(changes, "SSH rules reordered")
+	}
+	return changes
+}
+
+// policyFileChanges summarises the changes between two HuJSON policy files, or
+// returns nil if either of them can't be parsed.
+func policyFileChanges(from, to string) []string {
+	if from == to {
+		return nil
+	}
+	fromDoc, _, err := parsePolicyDocument(from)
+	if err != nil {
+		return nil
+	}
+	toDoc, _, err := parsePolicyDocument(to)
+	if err != nil {
+		return nil
+	}
+	return diffPolicyDocuments(fromDoc, toDoc)
+}
+
+// formatPolicyDiff formats the changes returned by [diffPolicyDocuments] as a
+// list.
+func formatPolicyDiff(changes []string) string {
+	var b strings.Builder
+	for i, change := range changes {
+		if i == maxPolicyDiffLines {
+			fmt.Fprintf(&b, "- ... and %d more\n", len(changes)-i)
+			break
+		}
+		fmt.Fprintf(&b, "- %s\n", change)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// diffPolicyMap summarises the changes to a section of the policy file that
+// maps names to lists, such as groups to their members.
+func diffPolicyMap(kind, values string, from, to map[string][]string) []string {
+	var changes []string
+	for _, name := range slices.Sorted(maps.Keys(from)) {
+		if _, ok := to[name]; !ok {
+			changes = append(changes, fmt.Sprintf("%s removed: %s", kind, name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(to)) {
+		old, ok := from[name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s added: %s (%s: %s)", kind, name, values, describePolicyList(to[name])))
+			continue
+		}
+
+		var details []string
+		if added := policyListDifference(to[name], old); len(added) > 0 {
+			details = append(details, fmt.Sprintf("%s added: %s", values, strings.Join(added, ", ")))
+		}
+		if removed := policyListDifference(old, to[name]); len(removed) > 0 {
+			details = append(details, fmt.Sprintf("%s removed: %s", values, strings.Join(removed, ", ")))
+		}
+		if len(details) > 0 {
+			changes = append(changes, fmt.Sprintf("%s changed: %s (%s)", kind, name, strings.Join(details, "; ")))
+		}
+	}
+	return changes
+}
+
+// diffPolicyRules summarises the changes to a list of rules. Rules have no
+// identity, so a rule that is removed and a rule with the same sources that
+// is added are reported as a change of that rule.
+func diffPolicyRules[T any](kind string, from, to []T, source func(T) []string, describe func(T) string) []string {
+	difference := func(a, b []T) []T {
+		counts := make(map[string]int)
+		for _, rule := range b {
+			counts[policyRuleKey(rule)]++
+		}
+		var diff []T
+		for _, rule := range a {
+			if k := policyRuleKey(rule); counts[k] > 0 {
+				counts[k]--
+			} else {
+				diff = append(diff, rule)
+			}
+		}
+		return diff
+	}
+	removed, added := difference(from, to), difference(to, from)
+
+	var changes []string
+	for _, old := range removed {
+		i := slices.IndexFunc(added, func(rule T) bool {
+			return slices.Equal(source(rule), source(old))
+		})
+		if i < 0 {
+			changes = append(changes, fmt.Sprintf("%s removed: %s", kind, describe(old)))
+			continue
+		}
+
+		if was, now := describe(old), describe(added[i]); was != now {
+			changes = append(changes, fmt.Sprintf("%s changed: %s (was %s)", kind, now, was))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s changed: %s", kind, now))
+		}
+		added = slices.Delete(added, i, i+1)
+	}
+	for _, rule := range added {
+		changes = append(changes, fmt.Sprintf("%s added: %s", kind, describe(rule)))
+	}
+	return changes
+}
+
+// policyRulesReordered reports whether the rules that are in both from and to
+// are in a different order in each of them.
+func policyRulesReordered[T any](from, to []T) bool {
+	common := func(a, b []T) []string {
+		counts := make(map[string]int)
+		for _, rule := range b {
+			counts[policyRuleKey(rule)]++
+		}
+		var keys []string
+		for _, rule := range a {
+			if k := policyRuleKey(rule); counts[k] > 0 {
+				counts[k]--
+				keys = append(keys, k)
+			}
+		}
+		return keys
+	}
+	return !slices.Equal(common(from, to), common(to, from))
+}
+
+// policyRuleKey returns a key that is the same for rules that are equal.
+func policyRuleKey[T any](rule T) string {
+	b, _ := json.Marshal(rule)
+	return string(b)
+}
+
+func describePolicyACL(acl policyACL) string {
+	s := fmt.Sprintf("%s -> %s", describePolicyList(acl.Source), describePolicyList(acl.Dest))
+	if acl.Proto != "" {
+		s += fmt.Sprintf(" (proto %s)", acl.Proto)
+	}
+	return s
+}
+
+func describePolicyGrant(grant policyGrant) string {
+	// Destinations are described with their ports, such as tag:db:5432, in
+	// the same way as the destinations of ACL rules.
+	dests := grant.Dest
+	if len(grant.IP) > 0 {
+		dests = nil
+		for _, dst := range grant.Dest {
+			for _, ip := range grant.IP {
+				if proto, ports, ok := strings.Cut(ip, ":"); ok {
+					dests = append(dests, fmt.Sprintf("%s:%s/%s", dst, ports, proto))
+				} else {
+					dests = append(dests, dst+":"+ip)
+				}
+			}
+		}
+	}
+
+	s := fmt.Sprintf("%s -> %s", describePolicyList(grant.Source), describePolicyList(dests))
+	if len(grant.App) > 0 {
+		s += " (with app capabilities)"
+	}
+	return s
+}
+
+func describePolicySSH(ssh policySSH) string {
+	return fmt.Sprintf("%s %s -> %s as %s", ssh.Action, describePolicyList(ssh.Source), describePolicyList(ssh.Dest), describePolicyList(ssh.Users))
+}
+
+func describePolicyList(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+// policyListDifference returns the values of a that are not in b.
+func policyListDifference(a, b []string) []string {
+	var diff []string
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,93 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyFileChanges(t *testing.T) {
+	const from = `{
+		"groups": {
+			"group:eng": ["alice@example.com", "bob@example.com"],
+			"group:ops": ["carol@example.com"],
+		},
+		"tagOwners": {"tag:web": ["group:eng"]},
+		"acls": [
+			{"action": "accept", "src": ["group:ops"], "dst": ["*:22"]},
+		],
+		"grants": [
+			{"src": ["group:eng"], "dst": ["tag:web"], "ip": ["443"]},
+			{"src": ["*"], "dst": ["tag:web"], "ip": ["tcp:80"]},
+		],
+		"ssh": [
+			{"action": "check", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root"]},
+		],
+	}`
+
+	// Comments, formatting and the order of map keys are not changes.
+	assert.Empty(t, policyFileChanges(from, `{
+		// Grants.
+		"grants": [{"src": ["group:eng"], "dst": ["tag:web"], "ip": ["443"]}, {"src": ["*"], "dst": ["tag:web"], "ip": ["tcp:80"]}],
+		"tagOwners": {"tag:web": ["group:eng"]},
+		"ssh": [{"action": "check", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root"]}],
+		"acls": [{"action": "accept", "src": ["group:ops"], "dst": ["*:22"]}],
+		"groups": {"group:ops": ["carol@example.com"], "group:eng": ["alice@example.com", "bob@example.com"]},
+	}`))
+
+	assert.Equal(t, []string{
+		"group removed: group:ops",
+		"group changed: group:eng (members added: dave@example.com; members removed: bob@example.com)",
+		"tag added: tag:db (owners: group:eng)",
+		"ACL rule removed: group:ops -> *:22",
+		"grant changed: group:eng -> tag:web:443, tag:web:8443 (was group:eng -> tag:web:443)",
+		"grant added: group:eng -> tag:db:5432",
+		"SSH rule changed: accept group:ops -> tag:web as root (was check group:ops -> tag:web as root)",
+	}, policyFileChanges(from, `{
+		"groups": {
+			"group:eng": ["alice@example.com", "dave@example.com"],
+		},
+		"tagOwners": {"tag:web": ["group:eng"], "tag:db": ["group:eng"]},
+		"grants": [
+			{"src": ["*"], "dst": ["tag:web"], "ip": ["tcp:80"]},
+			{"src": ["group:eng"], "dst": ["tag:web"], "ip": ["443", "8443"]},
+			{"src": ["group:eng"], "dst": ["tag:db"], "ip": ["5432"]},
+		],
+		"ssh": [
+			{"action": "accept", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root"]},
+		],
+	}`))
+
+	// SSH rules are evaluated in order, so reordering them is a change.
+	assert.Equal(t, []string{"SSH rules reordered"}, policyFileChanges(`{
+		"ssh": [
+			{"action": "check", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root"]},
+			{"action": "accept", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root", "ubuntu"]},
+		],
+	}`, `{
+		"ssh": [
+			{"action": "accept", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root", "ubuntu"]},
+			{"action": "check", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root"]},
+		],
+	}`))
+
+	// Policy files that can't be parsed are not compared.
+	assert.Empty(t, policyFileChanges(from, `{"grants": "invalid"}`))
+}
+
+func TestFormatPolicyDiff(t *testing.T) {
+	var changes []string
+	for i := range maxPolicyDiffLines + 2 {
+		changes = append(changes, fmt.Sprintf("group added: group:%d (members: (none))", i))
+	}
+
+	lines := strings.Split(formatPolicyDiff(changes), "\n")
+	assert.Len(t, lines, maxPolicyDiffLines+1)
+	assert.Equal(t, "- group added: group:0 (members: (none))", lines[0])
+	assert.Equal(t, "- ... and 2 more", lines[maxPolicyDiffLines])
+}
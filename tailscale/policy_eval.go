@@ -0,0 +1,524 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// policyMatch is the result of evaluating part of a policy file offline. Some
+// parts of a policy file, such as most autogroups or the addresses of devices,
+// depend on the state of the tailnet and cannot be evaluated without it, so
+// their result is unknown.
+//
+// Results are ordered, so that the result of any of several conditions is the
+// greatest of their results and the result of all of them is the least.
+type policyMatch int
+
+const (
+	policyNoMatch policyMatch = iota
+	policyMatchUnknown
+	policyMatchFound
+)
+
+func boolMatch(b bool) policyMatch {
+	if b {
+		return policyMatchFound
+	}
+	return policyNoMatch
+}
+
+// policySSHTest is an entry of the sshTests section of a policy file.
+type policySSHTest struct {
+	Source string   `json:"src"`
+	Dest   []string `json:"dst"`
+	Accept []string `json:"accept,omitempty"`
+	Check  []string `json:"check,omitempty"`
+	Deny   []string `json:"deny,omitempty"`
+}
+
+// policyPrincipal is the source or destination of a connection: a user, a
+// tagged device, or an address in the tailnet.
+type policyPrincipal struct {
+	User     string
+	Tags     []string
+	Prefixes []netip.Prefix
+}
+
+// policyEvaluator evaluates the acls, grants and ssh sections of a policy file
+// without access to the Tailscale API, in order to run the tests of a policy
+// file and answer access queries offline.
+//
+// Users, tags, groups, hosts, ipsets and IP addresses are evaluated as they
+// are written in the policy file. Selectors that depend on the state of the
+// tailnet, such as autogroup:admin or the IP addresses of a user's devices,
+// have unknown results; they are recorded in unknown so that they can be
+// reported.
+type policyEvaluator struct {
+	doc      *policyDocument
+	sshTests []policySSHTest
+
+	unknown map[string]bool
+}
+
+// newPolicyEvaluator parses a HuJSON policy file for evaluation.
+func newPolicyEvaluator(policy string) (*policyEvaluator, error) {
+	doc, _, err := parsePolicyDocument(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := hujson.Standardize([]byte(policy))
+	if err != nil {
+		return nil, err
+	}
+	var tests struct {
+		SSHTests []policySSHTest `json:"sshTests"`
+	}
+	if err := json.Unmarshal(b, &tests); err != nil {
+		return nil, err
+	}
+
+	return &policyEvaluator{
+		doc:      doc,
+		sshTests: tests.SSHTests,
+		unknown:  make(map[string]bool),
+	}, nil
+}
+
+// unknownSelectors returns the selectors that could not be evaluated offline,
+// in sorted order.
+func (e *policyEvaluator) unknownSelectors() []string {
+	var selectors []string
+	for s := range e.unknown {
+		selectors = append(selectors, s)
+	}
+	slices.Sort(selectors)
+	return selectors
+}
+
+func (e *policyEvaluator) unknownSelector(selector string) policyMatch {
+	e.unknown[selector] = true
+	return policyMatchUnknown
+}
+
+// evalRule evaluates a rule of the policy file, and only records the selectors
+// that could not be evaluated if they make the result of the rule unknown.
+func (e *policyEvaluator) evalRule(eval func() policyMatch) policyMatch {
+	unknown := e.unknown
+	e.unknown = make(map[string]bool)
+	result := eval()
+	if result == policyMatchUnknown {
+		maps.Copy(unknown, e.unknown)
+	}
+	e.unknown = unknown
+	return result
+}
+
+// principal returns the principal for a user login, a tag, a host or an IP
+// address or range, as they are written in the src of a test.
+func (e *policyEvaluator) principal(name string) (policyPrincipal, bool) {
+	switch {
+	case strings.HasPrefix(name, "tag:"):
+		return policyPrincipal{Tags: []string{name}}, true
+	case strings.Contains(name, "@"):
+		return policyPrincipal{User: name}, true
+	}
+	if prefixes, ok := e.prefixes(name); ok {
+		return policyPrincipal{Prefixes: prefixes}, true
+	}
+	return policyPrincipal{}, false
+}
+
+// destination parses a destination of the form "host:port", as written in the
+// accept and deny lists of a test.
+func (e *policyEvaluator) destination(dest string) (policyPrincipal, int, bool) {
+	host, portStr, ok := cutLast(dest, ":")
+	if !ok {
+		return policyPrincipal{}, 0, false
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 0 || port > 65535 {
+		return policyPrincipal{}, 0, false
+	}
+	p, ok := e.principal(strings.Trim(host, "[]"))
+	return p, port, ok
+}
+
+// prefixes returns the IP ranges of a host alias, an ipset, or an IP address
+// or range.
+func (e *policyEvaluator) prefixes(name string) ([]netip.Prefix, bool) {
+	if host, ok := e.doc.Hosts[name]; ok {
+		name = host
+	}
+	if entries, ok := e.doc.IPSets[name]; ok && strings.HasPrefix(name, "ipset:") {
+		var prefixes []netip.Prefix
+		for _, entry := range entries {
+			// Entries that remove ranges from an ipset, or that refer to
+			// other ipsets, are not supported.
+			entry = strings.TrimPrefix(entry, "add:")
+			if strings.HasPrefix(entry, "remove:") || strings.HasPrefix(entry, "ipset:") {
+				return nil, false
+			}
+			p, ok := e.prefixes(entry)
+			if !ok {
+				return nil, false
+			}
+			prefixes = append(prefixes, p...)
+		}
+		return prefixes, true
+	}
+
+	if prefix, err := netip.ParsePrefix(name); err == nil {
+		return []netip.Prefix{prefix.Masked()}, true
+	}
+	if addr, err := netip.ParseAddr(name); err == nil {
+		return []netip.Prefix{netip.PrefixFrom(addr, addr.BitLen())}, true
+	}
+	return nil, false
+}
+
+// matchSelector reports whether a selector from the src or dst of a rule
+// matches a principal. self is the source of the connection, which is used to
+// evaluate autogroup:self in destinations.
+func (e *policyEvaluator) matchSelector(selector string, p policyPrincipal, self *policyPrincipal) policyMatch {
+	return e.matchSelectorDepth(selector, p, self, 0)
+}
+
+func (e *policyEvaluator) matchSelectorDepth(selector string, p policyPrincipal, self *policyPrincipal, depth int) policyMatch {
+	isUser := p.User != "" && len(p.Tags) == 0
+	isAddress := p.User == "" && len(p.Tags) == 0
+
+	switch {
+	case selector == "*", selector == "autogroup:danger-all":
+		return policyMatchFound
+
+	case selector == "autogroup:member":
+		if isAddress {
+			return e.unknownSelector(selector)
+		}
+		return boolMatch(isUser)
+
+	case selector == "autogroup:tagged":
+		if isAddress {
+			return e.unknownSelector(selector)
+		}
+		return boolMatch(len(p.Tags) > 0)
+
+	case selector == "autogroup:self":
+		if self == nil || isAddress || (self.User == "" && len(self.Tags) == 0) {
+			return e.unknownSelector(selector)
+		}
+		return boolMatch(isUser && len(self.Tags) == 0 && strings.EqualFold(p.User, self.User))
+
+	case strings.HasPrefix(selector, "autogroup:"):
+		return e.unknownSelector(selector)
+
+	case strings.HasPrefix(selector, "group:"):
+		members, ok := e.doc.Groups[selector]
+		if !ok || depth > len(e.doc.Groups) {
+			return e.unknownSelector(selector)
+		}
+		result := policyNoMatch
+		for _, member := range members {
+			result = max(result, e.matchSelectorDepth(member, p, self, depth+1))
+		}
+		return result
+
+	case strings.HasPrefix(selector, "tag:"):
+		if isAddress {
+			return e.unknownSelector(selector)
+		}
+		return boolMatch(slices.Contains(p.Tags, selector))
+
+	case strings.Contains(selector, "@"):
+		if isAddress {
+			return e.unknownSelector(selector)
+		}
+		return boolMatch(isUser && strings.EqualFold(p.User, selector))
+	}
+
+	prefixes, ok := e.prefixes(selector)
+	if !ok {
+		return e.unknownSelector(selector)
+	}
+	if !isAddress {
+		// The addresses of the devices of users and tags aren't known
+		// offline, so whether they are in the selector's ranges isn't either.
+		return e.unknownSelector(selector)
+	}
+	return matchPrefixes(prefixes, p.Prefixes)
+}
+
+// matchPrefixes reports whether all of the ranges of a principal are contained
+// in the ranges of a selector. Ranges that overlap only partially have an
+// unknown result.
+func matchPrefixes(selector, principal []netip.Prefix) policyMatch {
+	result := policyMatchFound
+	for _, p := range principal {
+		m := policyNoMatch
+		for _, s := range selector {
+			switch {
+			case s.Bits() <= p.Bits() && s.Contains(p.Addr()):
+				m = max(m, policyMatchFound)
+			case s.Overlaps(p):
+				m = max(m, policyMatchUnknown)
+			}
+		}
+		result = min(result, m)
+	}
+	return result
+}
+
+// matchAny returns the result of matching a principal against any of a list of
+// selectors.
+func (e *policyEvaluator) matchAny(selectors []string, p policyPrincipal, self *policyPrincipal) policyMatch {
+	result := policyNoMatch
+	for _, s := range selectors {
+		result = max(result, e.matchSelector(s, p, self))
+		if result == policyMatchFound {
+			break
+		}
+	}
+	return result
+}
+
+// canAccess reports whether the acls and grants of the policy file allow src
+// to connect to port on dst over proto. An empty proto is TCP.
+func (e *policyEvaluator) canAccess(src, dst policyPrincipal, port int, proto string) policyMatch {
+	proto = normalizePolicyProto(proto)
+	if proto == "" {
+		proto = "tcp"
+	}
+
+	result := policyNoMatch
+	for _, acl := range e.doc.ACLs {
+		if acl.Action != "accept" || (acl.Proto != "" && normalizePolicyProto(acl.Proto) != proto) {
+			continue
+		}
+
+		result = max(result, e.evalRule(func() policyMatch {
+			dstMatch := policyNoMatch
+			for _, dest := range acl.Dest {
+				host, ports, ok := cutLast(dest, ":")
+				if !ok {
+					dstMatch = max(dstMatch, e.unknownSelector(dest))
+					continue
+				}
+				dstMatch = max(dstMatch, min(e.matchSelector(strings.Trim(host, "[]"), dst, &src), e.matchPorts(ports, port)))
+			}
+			return min(e.matchAny(acl.Source, src, nil), dstMatch)
+		}))
+		if result == policyMatchFound {
+			return result
+		}
+	}
+
+	for _, grant := range e.doc.Grants {
+		result = max(result, e.evalRule(func() policyMatch {
+			ipMatch := policyNoMatch
+			for _, ip := range grant.IP {
+				ipMatch = max(ipMatch, e.matchIPProto(ip, port, proto))
+			}
+			return min(e.matchAny(grant.Source, src, nil), e.matchAny(grant.Dest, dst, &src), ipMatch)
+		}))
+		if result == policyMatchFound {
+			return result
+		}
+	}
+
+	return result
+}
+
+// matchIPProto matches an entry of the ip list of a grant, such as "*", "443",
+// "tcp:443" or "udp:1000-2000", against a port and protocol.
+func (e *policyEvaluator) matchIPProto(ip string, port int, proto string) policyMatch {
+	if ip == "*" {
+		return policyMatchFound
+	}
+	if p, ports, ok := strings.Cut(ip, ":"); ok {
+		if normalizePolicyProto(p) != proto {
+			return policyNoMatch
+		}
+		return e.matchPorts(ports, port)
+	}
+	return e.matchPorts(ip, port)
+}
+
+// matchPorts matches a port against a list of ports and port ranges, such as
+// "*", "22", "80,443" or "1000-2000".
+func (e *policyEvaluator) matchPorts(ports string, port int) policyMatch {
+	for _, r := range strings.Split(ports, ",") {
+		if r == "*" {
+			return policyMatchFound
+		}
+		first, last, isRange := strings.Cut(r, "-")
+		if !isRange {
+			last = first
+		}
+		lo, err1 := strconv.Atoi(first)
+		hi, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil {
+			return e.unknownSelector(ports)
+		}
+		if lo <= port && port <= hi {
+			return policyMatchFound
+		}
+	}
+	return policyNoMatch
+}
+
+// sshAction returns the action of the first ssh rule that allows src to
+// connect to dst as user, or an empty string if no rule allows it.
+func (e *policyEvaluator) sshAction(src, dst policyPrincipal, user string) (string, policyMatch) {
+	for _, rule := range e.doc.SSH {
+		m := e.evalRule(func() policyMatch {
+			return min(
+				e.matchAny(rule.Source, src, nil),
+				e.matchAny(rule.Dest, dst, &src),
+				e.matchSSHUsers(rule.Users, src, user),
+			)
+		})
+		switch m {
+		case policyMatchFound:
+			return rule.Action, policyMatchFound
+		case policyMatchUnknown:
+			// Rules are evaluated in order, so the result of later rules
+			// doesn't matter if this one might apply.
+			return "", policyMatchUnknown
+		}
+	}
+	return "", policyNoMatch
+}
+
+func (e *policyEvaluator) matchSSHUsers(users []string, src policyPrincipal, user string) policyMatch {
+	result := policyNoMatch
+	for _, u := range users {
+		switch {
+		case u == user:
+			return policyMatchFound
+		case u == "autogroup:nonroot":
+			result = max(result, boolMatch(user != "root"))
+		case strings.HasPrefix(u, "localpart:*@"):
+			if src.User == "" {
+				result = max(result, e.unknownSelector(u))
+				continue
+			}
+			local, domain, _ := strings.Cut(src.User, "@")
+			result = max(result, boolMatch(local == user && strings.EqualFold("*@"+domain, strings.TrimPrefix(u, "localpart:"))))
+		case strings.HasPrefix(u, "autogroup:"):
+			result = max(result, e.unknownSelector(u))
+		}
+	}
+	return result
+}
+
+// runTests runs the tests and sshTests sections of the policy file, and
+// returns a description of each assertion that fails. Assertions whose result
+// depends on the state of the tailnet are skipped.
+func (e *policyEvaluator) runTests() []string {
+	var failures []string
+
+	for _, test := range e.doc.Tests {
+		src, ok := e.principal(test.Source)
+		if !ok {
+			continue
+		}
+		check := func(dests []string, want bool) {
+			for _, dest := range dests {
+				dst, port, ok := e.destination(dest)
+				if !ok {
+					continue
+				}
+				switch e.canAccess(src, dst, port, test.Proto) {
+				case policyNoMatch:
+					if want {
+						failures = append(failures, fmt.Sprintf("%s cannot access %s, but is expected to", test.Source, dest))
+					}
+				case policyMatchFound:
+					if !want {
+						failures = append(failures, fmt.Sprintf("%s can access %s, but is expected not to", test.Source, dest))
+					}
+				}
+			}
+		}
+		check(test.Accept, true)
+		check(test.Deny, false)
+	}
+
+	for _, test := range e.sshTests {
+		src, ok := e.principal(test.Source)
+		if !ok {
+			continue
+		}
+		for _, dest := range test.Dest {
+			dst, ok := e.principal(dest)
+			if !ok {
+				continue
+			}
+			check := func(users []string, want string) {
+				for _, user := range users {
+					action, m := e.sshAction(src, dst, user)
+					if m != policyMatchUnknown && action != want {
+						failures = append(failures, fmt.Sprintf("ssh from %s to %s as %s is %s, but is expected to be %s",
+							test.Source, dest, user, sshActionName(action), sshActionName(want)))
+					}
+				}
+			}
+			check(test.Accept, "accept")
+			check(test.Check, "check")
+			check(test.Deny, "")
+		}
+	}
+
+	return failures
+}
+
+func sshActionName(action string) string {
+	switch action {
+	case "accept":
+		return "accepted"
+	case "check":
+		return "checked"
+	}
+	return "denied"
+}
+
+// policyProtoNames are the names of IP protocols for their IANA numbers, which
+// can be used interchangeably in a policy file.
+var policyProtoNames = map[string]string{
+	"1":   "icmp",
+	"2":   "igmp",
+	"6":   "tcp",
+	"17":  "udp",
+	"47":  "gre",
+	"50":  "esp",
+	"51":  "ah",
+	"58":  "ipv6-icmp",
+	"132": "sctp",
+}
+
+func normalizePolicyProto(proto string) string {
+	proto = strings.ToLower(proto)
+	if name, ok := policyProtoNames[proto]; ok {
+		return name
+	}
+	return proto
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,175 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEvalPolicy = `{
+	"groups": {
+		"group:dev": ["alice@example.com", "group:ops"],
+		"group:ops": ["bob@example.com"],
+	},
+	"hosts": {
+		"db":     "100.64.0.10",
+		"office": "192.168.0.0/24",
+	},
+	"ipsets": {
+		"ipset:internal": ["add:10.0.0.0/8", "db"],
+	},
+	"acls": [
+		// Users in group:dev can reach web servers over HTTPS.
+		{"action": "accept", "src": ["group:dev"], "dst": ["tag:web:443,8000-8100"]},
+		{"action": "accept", "proto": "17", "src": ["office"], "dst": ["db:53"]},
+	],
+	"grants": [
+		{"src": ["tag:web"], "dst": ["ipset:internal"], "ip": ["tcp:5432"]},
+		{"src": ["autogroup:member"], "dst": ["autogroup:self"], "ip": ["*"]},
+		{"src": ["autogroup:admin"], "dst": ["tag:prod"], "ip": ["22"]},
+	],
+	"ssh": [
+		{"action": "check", "src": ["group:ops"], "dst": ["tag:web"], "users": ["root"]},
+		{"action": "accept", "src": ["group:dev"], "dst": ["tag:web"], "users": ["autogroup:nonroot"]},
+	],
+}`
+
+func TestPolicyEvaluatorCanAccess(t *testing.T) {
+	e, err := newPolicyEvaluator(testEvalPolicy)
+	require.NoError(t, err)
+
+	tests := []struct {
+		src, dst string
+		proto    string
+		want     policyMatch
+	}{
+		{src: "alice@example.com", dst: "tag:web:443", want: policyMatchFound},
+		{src: "bob@example.com", dst: "tag:web:8080", want: policyMatchFound},
+		{src: "bob@example.com", dst: "tag:web:22", want: policyNoMatch},
+		{src: "carol@example.com", dst: "tag:web:443", want: policyNoMatch},
+		// acls without a proto allow TCP, UDP and ICMP.
+		{src: "alice@example.com", dst: "tag:web:443", proto: "udp", want: policyMatchFound},
+		{src: "192.168.0.15", dst: "db:53", proto: "udp", want: policyMatchFound},
+		{src: "192.168.0.16", dst: "db:53", proto: "tcp", want: policyMatchUnknown},
+		{src: "tag:web", dst: "10.1.2.3:5432", want: policyMatchFound},
+		{src: "tag:web", dst: "db:5432", want: policyMatchFound},
+		{src: "tag:web", dst: "db:5432", proto: "udp", want: policyNoMatch},
+		{src: "alice@example.com", dst: "alice@example.com:22", want: policyMatchFound},
+		// Users can't reach each other's devices through autogroup:self, but
+		// they might be admins, which can't be evaluated offline.
+		{src: "alice@example.com", dst: "bob@example.com:22", want: policyNoMatch},
+		{src: "alice@example.com", dst: "tag:prod:22", want: policyMatchUnknown},
+		// The addresses of tagged devices aren't known offline.
+		{src: "100.64.0.10", dst: "tag:web:443", want: policyMatchUnknown},
+		// Nor are the addresses of users' devices.
+		{src: "alice@example.com", dst: "db:53", proto: "udp", want: policyMatchUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src+"->"+tt.dst, func(t *testing.T) {
+			src, ok := e.principal(tt.src)
+			require.True(t, ok)
+			dst, port, ok := e.destination(tt.dst)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, e.canAccess(src, dst, port, tt.proto))
+		})
+	}
+}
+
+func TestPolicyEvaluatorUnknownSelectors(t *testing.T) {
+	e, err := newPolicyEvaluator(testEvalPolicy)
+	require.NoError(t, err)
+
+	// Only the selectors of rules that might apply are reported.
+	src, _ := e.principal("alice@example.com")
+	dst, port, _ := e.destination("tag:prod:22")
+	assert.Equal(t, policyMatchUnknown, e.canAccess(src, dst, port, ""))
+	assert.Equal(t, []string{"autogroup:admin"}, e.unknownSelectors())
+}
+
+func TestPolicyEvaluatorSSH(t *testing.T) {
+	e, err := newPolicyEvaluator(testEvalPolicy)
+	require.NoError(t, err)
+
+	web := policyPrincipal{Tags: []string{"tag:web"}}
+	tests := []struct {
+		src, user string
+		want      string
+	}{
+		{src: "bob@example.com", user: "root", want: "check"},
+		{src: "bob@example.com", user: "ubuntu", want: "accept"},
+		{src: "alice@example.com", user: "root", want: ""},
+		{src: "carol@example.com", user: "ubuntu", want: ""},
+	}
+
+	for _, tt := range tests {
+		src, _ := e.principal(tt.src)
+		action, m := e.sshAction(src, web, tt.user)
+		assert.NotEqual(t, policyMatchUnknown, m)
+		assert.Equal(t, tt.want, action, "%s as %s", tt.src, tt.user)
+	}
+}
+
+func TestPolicyEvaluatorRunTests(t *testing.T) {
+	e, err := newPolicyEvaluator(`{
+		"acls": [{"action": "accept", "src": ["group:dev"], "dst": ["tag:web:443"]}],
+		"ssh": [{"action": "accept", "src": ["group:dev"], "dst": ["tag:web"], "users": ["ubuntu"]}],
+		"groups": {"group:dev": ["alice@example.com"]},
+		"tests": [
+			{"src": "alice@example.com", "accept": ["tag:web:443"], "deny": ["tag:web:80"]},
+			{"src": "bob@example.com", "accept": ["tag:web:443"]},
+			{"src": "alice@example.com", "deny": ["tag:web:443"]},
+			// Hosts that aren't defined are skipped.
+			{"src": "alice@example.com", "deny": ["unknown-host:443"]},
+		],
+		"sshTests": [
+			{"src": "alice@example.com", "dst": ["tag:web"], "accept": ["ubuntu"], "check": ["ubuntu"], "deny": ["root"]},
+		],
+	}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"bob@example.com cannot access tag:web:443, but is expected to",
+		"alice@example.com can access tag:web:443, but is expected not to",
+		"ssh from alice@example.com to tag:web as ubuntu is accepted, but is expected to be checked",
+	}, e.runTests())
+}
+
+func TestACLTestsValidator(t *testing.T) {
+	validate := func(policy string) *validator.StringResponse {
+		resp := &validator.StringResponse{}
+		aclTestsValidator{}.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("acl"),
+			ConfigValue: types.StringValue(policy),
+		}, resp)
+		return resp
+	}
+
+	assert.False(t, validate(testEvalPolicy).Diagnostics.HasError())
+	assert.False(t, validate(`{"tests": [{"src": "alice@example.com", "accept": ["tag:web:443"]}], "grants": [{"src": ["*"], "dst": ["*"], "ip": ["*"]}]}`).Diagnostics.HasError())
+
+	// Identities can't be matched against address ranges offline, so tests
+	// that rely on it are left to the API.
+	assert.False(t, validate(`{
+		"acls": [
+			{"action": "accept", "src": ["192.168.0.0/24"], "dst": ["10.0.0.0/8:22"]},
+			{"action": "accept", "src": ["group:dev"], "dst": ["100.64.0.0/10:443"]},
+		],
+		"groups": {"group:dev": ["alice@example.com"]},
+		"tests": [
+			{"src": "alice@example.com", "accept": ["10.1.2.3:22", "tag:web:443"]},
+		],
+	}`).Diagnostics.HasError())
+
+	resp := validate(`{"tests": [{"src": "alice@example.com", "accept": ["tag:web:443"]}]}`)
+	require.True(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.Diagnostics[0].Detail(), "alice@example.com cannot access tag:web:443")
+}
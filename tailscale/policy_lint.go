@@ -0,0 +1,405 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// policyLintFinding is a problem found by [lintPolicy].
+type policyLintFinding struct {
+	// Summary is a short description of the kind of problem, such as
+	// "Undefined group".
+	Summary string
+	// Path is the JSON path of the problem in the policy file, such as
+	// `grants[2].src[0]`.
+	Path string
+	// Detail describes the problem.
+	Detail string
+}
+
+// policyLintKinds are the kinds of definitions in a policy file, by the name of
+// the section that defines them and the prefix of their names.
+var policyLintKinds = []struct {
+	kind, section, prefix string
+}{
+	{kind: "group", section: "groups", prefix: "group:"},
+	{kind: "tag", section: "tagOwners", prefix: "tag:"},
+	{kind: "host", section: "hosts"},
+	{kind: "posture", section: "postures", prefix: "posture:"},
+	{kind: "ipset", section: "ipsets", prefix: "ipset:"},
+}
+
+// policyLintField describes how the values of a field of a rule refer to
+// definitions.
+type policyLintField struct {
+	// port is true if values are followed by a port, such as tag:web:443.
+	port bool
+	// hosts is true if values can be the names of hosts.
+	hosts bool
+}
+
+// policyLintFields are the fields of the rules in each section of a policy file
+// that refer to definitions, by lowercase name.
+var policyLintFields = map[string]map[string]policyLintField{
+	"acls": {
+		"src":        {hosts: true},
+		"dst":        {port: true, hosts: true},
+		"users":      {hosts: true},
+		"ports":      {port: true, hosts: true},
+		"srcposture": {},
+	},
+	"grants": {
+		"src":        {hosts: true},
+		"dst":        {hosts: true},
+		"via":        {},
+		"srcposture": {},
+	},
+	"ssh": {
+		"src":        {},
+		"dst":        {},
+		"srcposture": {},
+	},
+	"nodeAttrs": {
+		"target": {hosts: true},
+	},
+	"tests": {
+		"src":    {hosts: true},
+		"user":   {hosts: true},
+		"accept": {port: true, hosts: true},
+		"deny":   {port: true, hosts: true},
+		"allow":  {port: true, hosts: true},
+	},
+	"sshTests": {
+		"src": {},
+		"dst": {hosts: true},
+	},
+}
+
+// policyLinter finds problems in a policy file that the Tailscale API accepts,
+// but that are most likely mistakes.
+type policyLinter struct {
+	// defined and used are the definitions of each kind, by name.
+	defined  map[string]map[string]string
+	used     map[string]map[string]bool
+	findings []policyLintFinding
+}
+
+// lintPolicy checks a HuJSON policy file for references to groups, tags, hosts,
+// postures and ipsets that are not defined, definitions that are not used,
+// duplicate keys, and rules that are shadowed by an earlier rule.
+func lintPolicy(policy string) ([]policyLintFinding, error) {
+	v, err := hujson.Parse([]byte(policy))
+	if err != nil {
+		return nil, err
+	}
+	root, ok := v.Value.(*hujson.Object)
+	if !ok {
+		return nil, fmt.Errorf("policy file is not a JSON object")
+	}
+	doc, _, err := parsePolicyDocument(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &policyLinter{
+		defined: make(map[string]map[string]string),
+		used:    make(map[string]map[string]bool),
+	}
+	l.checkDuplicateKeys(&v, "")
+	l.collectDefinitions(root)
+	l.checkReferences(root)
+	l.checkUnused()
+	l.checkShadowedRules(doc)
+	return l.findings, nil
+}
+
+func (l *policyLinter) add(summary, path, format string, args ...any) {
+	l.findings = append(l.findings, policyLintFinding{
+		Summary: summary,
+		Path:    path,
+		Detail:  fmt.Sprintf(format, args...),
+	})
+}
+
+// policySectionName returns the canonical name of a top-level key of a policy
+// file, which is case-insensitive.
+func policySectionName(name string) string {
+	for _, section := range append(slices.Clone(policyDocumentSections), "sshTests", "defaultSrcPosture") {
+		if strings.EqualFold(section, name) {
+			return section
+		}
+	}
+	return name
+}
+
+func objectMemberName(m hujson.ObjectMember) string {
+	return m.Name.Value.(hujson.Literal).String()
+}
+
+func memberPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return fmt.Sprintf("%s[%s]", parent, strconv.Quote(name))
+}
+
+func fieldPath(parent, name string) string {
+	return parent + "." + name
+}
+
+func elementPath(parent string, i int) string {
+	return fmt.Sprintf("%s[%d]", parent, i)
+}
+
+// checkDuplicateKeys reports keys that appear more than once in an object. Only
+// the last of them has an effect.
+func (l *policyLinter) checkDuplicateKeys(v *hujson.Value, path string) {
+	switch value := v.Value.(type) {
+	case *hujson.Object:
+		seen := make(map[string]bool)
+		for i := range value.Members {
+			name := objectMemberName(value.Members[i])
+			childPath := memberPath(path, name)
+			// Top-level keys are case-insensitive.
+			key := name
+			if path == "" {
+				key = strings.ToLower(name)
+			}
+			if seen[key] {
+				l.add("Duplicate key", childPath, "%q is defined more than once in the same object, and only its last definition is used.", name)
+			}
+			seen[key] = true
+			l.checkDuplicateKeys(&value.Members[i].Value, childPath)
+		}
+	case *hujson.Array:
+		for i := range value.Elements {
+			l.checkDuplicateKeys(&value.Elements[i], elementPath(path, i))
+		}
+	}
+}
+
+func (l *policyLinter) collectDefinitions(root *hujson.Object) {
+	for _, k := range policyLintKinds {
+		l.defined[k.kind] = make(map[string]string)
+		l.used[k.kind] = make(map[string]bool)
+	}
+	for _, m := range root.Members {
+		section := policySectionName(objectMemberName(m))
+		for _, k := range policyLintKinds {
+			obj, ok := m.Value.Value.(*hujson.Object)
+			if k.section != section || !ok {
+				continue
+			}
+			for _, def := range obj.Members {
+				name := objectMemberName(def)
+				l.defined[k.kind][name] = memberPath(objectMemberName(m), name)
+			}
+		}
+	}
+}
+
+// checkReferences checks that the values of the policy file that refer to
+// definitions refer to ones that exist.
+func (l *policyLinter) checkReferences(root *hujson.Object) {
+	for _, m := range root.Members {
+		name := objectMemberName(m)
+		section := policySectionName(name)
+
+		switch section {
+		case "groups", "tagOwners", "ipsets":
+			obj, ok := m.Value.Value.(*hujson.Object)
+			if !ok {
+				continue
+			}
+			for _, def := range obj.Members {
+				field := policyLintField{hosts: section == "ipsets"}
+				l.checkStrings(&def.Value, memberPath(name, objectMemberName(def)), field)
+			}
+
+		case "autoApprovers":
+			obj, ok := m.Value.Value.(*hujson.Object)
+			if !ok {
+				continue
+			}
+			for _, approvers := range obj.Members {
+				path := fieldPath(name, objectMemberName(approvers))
+				if routes, ok := approvers.Value.Value.(*hujson.Object); ok {
+					for _, route := range routes.Members {
+						l.checkStrings(&route.Value, memberPath(path, objectMemberName(route)), policyLintField{})
+					}
+				} else {
+					l.checkStrings(&approvers.Value, path, policyLintField{})
+				}
+			}
+
+		case "defaultSrcPosture":
+			l.checkStrings(&m.Value, name, policyLintField{})
+
+		default:
+			fields, ok := policyLintFields[section]
+			rules, isArray := m.Value.Value.(*hujson.Array)
+			if !ok || !isArray {
+				continue
+			}
+			for i := range rules.Elements {
+				rule, ok := rules.Elements[i].Value.(*hujson.Object)
+				if !ok {
+					continue
+				}
+				for _, f := range rule.Members {
+					fieldName := objectMemberName(f)
+					if field, ok := fields[strings.ToLower(fieldName)]; ok {
+						l.checkStrings(&f.Value, fieldPath(elementPath(name, i), fieldName), field)
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkStrings checks a string, or each string in a list of strings.
+func (l *policyLinter) checkStrings(v *hujson.Value, path string, field policyLintField) {
+	switch value := v.Value.(type) {
+	case hujson.Literal:
+		if value.Kind() == '"' {
+			l.checkReference(value.String(), path, field)
+		}
+	case *hujson.Array:
+		for i := range value.Elements {
+			l.checkStrings(&value.Elements[i], elementPath(path, i), field)
+		}
+	}
+}
+
+func (l *policyLinter) checkReference(value, path string, field policyLintField) {
+	if field.port {
+		value, _, _ = cutLast(value, ":")
+	}
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "add:"), "remove:")
+
+	for _, k := range policyLintKinds {
+		if k.prefix == "" || !strings.HasPrefix(value, k.prefix) {
+			continue
+		}
+		if _, ok := l.defined[k.kind][value]; !ok {
+			l.add("Undefined "+k.kind, path, "%s is not defined in the %s section of the policy file.", value, k.section)
+			return
+		}
+		l.used[k.kind][value] = true
+		return
+	}
+
+	if !field.hosts || !isPolicyHostName(value) {
+		return
+	}
+	if _, ok := l.defined["host"][value]; !ok {
+		l.add("Undefined host", path, "%s is not an IP address, and is not defined in the hosts section of the policy file.", value)
+		return
+	}
+	l.used["host"][value] = true
+}
+
+// isPolicyHostName reports whether a value in a rule can only be the name of a
+// host, rather than a user, an IP address or range, or a selector such as
+// autogroup:member.
+func isPolicyHostName(value string) bool {
+	value = strings.Trim(value, "[]")
+	if value == "" || value == "*" || strings.ContainsAny(value, "@:") {
+		return false
+	}
+	if _, err := netip.ParsePrefix(value); err == nil {
+		return false
+	}
+	if _, err := netip.ParseAddr(value); err == nil {
+		return false
+	}
+	// IP ranges, such as 100.64.0.1-100.64.0.10.
+	if first, _, ok := strings.Cut(value, "-"); ok {
+		if _, err := netip.ParseAddr(first); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *policyLinter) checkUnused() {
+	for _, k := range policyLintKinds {
+		var names []string
+		for name := range l.defined[k.kind] {
+			if !l.used[k.kind][name] {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			l.add("Unused "+k.kind, l.defined[k.kind][name], "%s is defined, but is not used in the policy file.", name)
+		}
+	}
+}
+
+// checkShadowedRules reports rules that can never apply, because an earlier
+// rule already applies to all of their sources and destinations.
+func (l *policyLinter) checkShadowedRules(doc *policyDocument) {
+	for i, acl := range doc.ACLs {
+		for j, earlier := range doc.ACLs[:i] {
+			if acl.Action == earlier.Action && acl.Proto == earlier.Proto &&
+				slices.Equal(acl.SrcPosture, earlier.SrcPosture) &&
+				policySelectorsCovered(acl.Source, earlier.Source, "*") &&
+				policySelectorsCovered(acl.Dest, earlier.Dest, "*:*") {
+				l.add("Shadowed rule", elementPath("acls", i), "This rule is shadowed by acls[%d], which already allows all of its sources and destinations.", j)
+				break
+			}
+		}
+	}
+
+	for i, grant := range doc.Grants {
+		for j, earlier := range doc.Grants[:i] {
+			if len(grant.App) == 0 && len(earlier.App) == 0 &&
+				slices.Equal(grant.Via, earlier.Via) &&
+				slices.Equal(grant.SrcPosture, earlier.SrcPosture) &&
+				policySelectorsCovered(grant.Source, earlier.Source, "*") &&
+				policySelectorsCovered(grant.Dest, earlier.Dest, "*") &&
+				policySelectorsCovered(grant.IP, earlier.IP, "*") {
+				l.add("Shadowed rule", elementPath("grants", i), "This rule is shadowed by grants[%d], which already grants all of its sources, destinations and ports.", j)
+				break
+			}
+		}
+	}
+
+	for i, ssh := range doc.SSH {
+		for j, earlier := range doc.SSH[:i] {
+			if slices.Equal(ssh.SrcPosture, earlier.SrcPosture) &&
+				policySelectorsCovered(ssh.Source, earlier.Source, "") &&
+				policySelectorsCovered(ssh.Dest, earlier.Dest, "") &&
+				policySelectorsCovered(ssh.Users, earlier.Users, "") {
+				l.add("Shadowed rule", elementPath("ssh", i), "This rule is shadowed by ssh[%d], which is evaluated first and applies to all of its sources, destinations and users.", j)
+				break
+			}
+		}
+	}
+}
+
+// policySelectorsCovered reports whether each selector in a is also in b, or b
+// contains the wildcard.
+func policySelectorsCovered(a, b []string, wildcard string) bool {
+	if len(a) == 0 {
+		return false
+	}
+	if wildcard != "" && slices.Contains(b, wildcard) {
+		return true
+	}
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			return false
+		}
+	}
+	return true
+}
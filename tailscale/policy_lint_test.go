@@ -0,0 +1,80 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintPolicy(t *testing.T) {
+	findings, err := lintPolicy(`{
+		"groups": {
+			"group:dev": ["alice@example.com"],
+			"group:old": ["bob@example.com"],
+		},
+		"tagOwners": {
+			"tag:web": ["group:dev"],
+			"tag:db":  ["group:ops"],
+		},
+		"hosts": {
+			"db":      "100.64.0.10",
+			"printer": "100.64.0.20",
+		},
+		"postures": {
+			"posture:latest": ["node:tsReleaseTrack == 'stable'"],
+		},
+		"ipsets": {
+			"ipset:office": ["add:192.168.0.0/24", "add:nas"],
+		},
+		"acls": [
+			{"action": "accept", "src": ["group:dev", "ipset:office"], "dst": ["db:5432", "tag:web:*", "100.64.0.1:22"]},
+			{"action": "accept", "src": ["group:dev"], "dst": ["db:5432"]},
+		],
+		"grants": [
+			{"src": ["autogroup:member"], "dst": ["tag:db"], "ip": ["*"], "srcPosture": ["posture:latest"]},
+			{"src": ["*"], "dst": ["tag:mail"], "ip": ["25"]},
+		],
+		"ssh": [
+			{"action": "check", "src": ["group:dev"], "dst": ["tag:web"], "users": ["root"]},
+			{"action": "accept", "src": ["group:dev"], "dst": ["tag:web"], "users": ["root"]},
+		],
+		"tests": [
+			{"src": "alice@example.com", "accept": ["tag:web:443"], "deny": ["printer2:22"]},
+		],
+		"Tests": [],
+	}`)
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Summary+" at "+f.Path)
+	}
+	assert.Equal(t, []string{
+		"Duplicate key at Tests",
+		"Undefined group at tagOwners[\"tag:db\"][0]",
+		"Undefined host at ipsets[\"ipset:office\"][1]",
+		"Undefined tag at grants[1].dst[0]",
+		"Undefined host at tests[0].deny[0]",
+		"Unused group at groups[\"group:old\"]",
+		"Unused host at hosts[\"printer\"]",
+		"Shadowed rule at acls[1]",
+		"Shadowed rule at ssh[1]",
+	}, got)
+
+	assert.Equal(t, "tag:mail is not defined in the tagOwners section of the policy file.", findings[3].Detail)
+}
+
+func TestLintPolicyUnusedPosture(t *testing.T) {
+	// Everything else in the policy file is defined and used.
+	findings, err := lintPolicy(testPolicyHuJSON)
+	require.NoError(t, err)
+	assert.Equal(t, []policyLintFinding{{
+		Summary: "Unused posture",
+		Path:    `postures["posture:latest"]`,
+		Detail:  "posture:latest is defined, but is not used in the policy file.",
+	}}, findings)
+}
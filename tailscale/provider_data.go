@@ -0,0 +1,159 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+const tailnetAttributeDescription = "The tailnet ID to manage this object in. Defaults to the tailnet configured on the provider. The tailnet must be accessible with the credentials passed to the provider."
+
+// providerData is made available to resources, data sources and ephemeral
+// resources in their Configure methods.
+type providerData struct {
+	// Client is the client for the tailnet configured on the provider.
+	Client *tailscale.Client
+
+	// validateTags is whether the tags of resources are validated against
+	// the policy file during plan.
+	validateTags bool
+
+	mu            sync.Mutex
+	clients       map[string]*tailscale.Client
+	tagPolicies   map[string]*tagPolicy
+	deviceLocks   map[string]*sync.Mutex
+	deviceNodeIDs map[string]string
+}
+
+// ClientForTailnet returns a client for the given tailnet that shares the
+// credentials of the provider's client. If tailnet is empty, the provider's
+// client is returned. Clients are cached, so each tailnet has a single client.
+func (p *providerData) ClientForTailnet(tailnet string) *tailscale.Client {
+	if tailnet == "" || tailnet == p.Client.Tailnet {
+		return p.Client
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[tailnet]; ok {
+		return client
+	}
+
+	// Accessing any of the API resources initializes the provider's client,
+	// which wraps its HTTP client with the OAuth or federated identity
+	// authentication. Reusing that HTTP client means all tailnets share the
+	// same access token rather than each fetching their own.
+	p.Client.Devices()
+
+	client := &tailscale.Client{
+		BaseURL:   p.Client.BaseURL,
+		UserAgent: p.Client.UserAgent,
+		APIKey:    p.Client.APIKey,
+		Tailnet:   tailnet,
+		HTTP:      p.Client.HTTP,
+	}
+	if p.clients == nil {
+		p.clients = make(map[string]*tailscale.Client)
+	}
+	p.clients[tailnet] = client
+	return client
+}
+
+// lockDevice locks the device with the given ID in the client's tailnet, and
+// returns a function that unlocks it. Resources that update a single tag or
+// route of a device hold the lock while they read and write the device's
+// tags or routes, so that concurrent updates in the same run aren't lost.
+// Devices are locked by their node ID, so that resources configured with the
+// legacy ID and the node ID of the same device share a lock. The node ID is
+// looked up once per device and ID.
+func (p *providerData) lockDevice(ctx context.Context, client *tailscale.Client, deviceID string) (func(), error) {
+	nodeID, err := p.deviceNodeID(ctx, client, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	key := client.Tailnet + "/" + nodeID
+
+	p.mu.Lock()
+	if p.deviceLocks == nil {
+		p.deviceLocks = make(map[string]*sync.Mutex)
+	}
+	mu, ok := p.deviceLocks[key]
+	if !ok {
+		mu = &sync.Mutex{}
+		p.deviceLocks[key] = mu
+	}
+	p.mu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock, nil
+}
+
+// deviceNodeID returns the node ID of the device with the given legacy ID or
+// node ID in the client's tailnet, fetching the device if it hasn't been
+// looked up before.
+func (p *providerData) deviceNodeID(ctx context.Context, client *tailscale.Client, deviceID string) (string, error) {
+	p.mu.Lock()
+	nodeID, ok := p.deviceNodeIDs[client.Tailnet+"/"+deviceID]
+	p.mu.Unlock()
+	if ok {
+		return nodeID, nil
+	}
+
+	device, err := client.Devices().Get(ctx, deviceID)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.deviceNodeIDs == nil {
+		p.deviceNodeIDs = make(map[string]string)
+	}
+	for _, id := range []string{deviceID, device.ID, device.NodeID} {
+		p.deviceNodeIDs[client.Tailnet+"/"+id] = device.NodeID
+	}
+	return device.NodeID, nil
+}
+
+// clientForTailnet returns the client to use for an object with the given
+// tailnet attribute, falling back to the provider's client if the attribute
+// is not set or provider data is not yet available.
+func clientForTailnet(data *providerData, tailnet types.String) *tailscale.Client {
+	if data == nil {
+		return nil
+	}
+	if tailnet.IsNull() || tailnet.IsUnknown() {
+		return data.Client
+	}
+	return data.ClientForTailnet(tailnet.ValueString())
+}
+
+// importTailnetAndID splits an import ID of the form `<tailnet>/<id>` and
+// records the tailnet in state. IDs without a tailnet are returned as-is.
+func importTailnetAndID(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) string {
+	tailnet, id, ok := strings.Cut(req.ID, "/")
+	if !ok {
+		return req.ID
+	}
+
+	if tailnet == "" || id == "" {
+		resp.Diagnostics.Append(diag.NewErrorDiagnostic(
+			"Invalid import ID",
+			"Expected an import ID of the form <id> or <tailnet>/<id>, got: "+req.ID,
+		))
+		return id
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tailnet"), tailnet)...)
+	return id
+}
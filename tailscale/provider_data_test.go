@@ -0,0 +1,153 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+
+	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
+)
+
+func TestProviderDataClientForTailnet(t *testing.T) {
+	baseURL, _ := url.Parse("https://api.example.com")
+	client := createTailscaleClient(baseURL, "test", "-", "api_123", "", "", "", "", nil, nil)
+	data := &providerData{Client: &client}
+
+	assert.Same(t, data.Client, clientForTailnet(data, types.StringNull()))
+	assert.Same(t, data.Client, clientForTailnet(data, types.StringUnknown()))
+	assert.Same(t, data.Client, clientForTailnet(data, types.StringValue("")))
+	assert.Same(t, data.Client, clientForTailnet(data, types.StringValue("-")))
+
+	other := clientForTailnet(data, types.StringValue("example.com"))
+	assert.NotSame(t, data.Client, other)
+	assert.Equal(t, "example.com", other.Tailnet)
+	assert.Equal(t, "api_123", other.APIKey)
+	assert.Same(t, data.Client.HTTP, other.HTTP)
+	assert.Same(t, other, clientForTailnet(data, types.StringValue("example.com")))
+
+	assert.Nil(t, clientForTailnet(nil, types.StringValue("example.com")))
+}
+
+func TestProviderDataClientForTailnet_SharesOAuthClient(t *testing.T) {
+	baseURL, _ := url.Parse("https://api.example.com")
+	client := createTailscaleClient(baseURL, "test", "-", "", "client_id", "client_secret", "", "", nil, nil)
+	data := &providerData{Client: &client}
+
+	other := data.ClientForTailnet("example.com")
+	assert.Nil(t, other.Auth)
+	assert.NotNil(t, other.HTTP)
+	assert.Same(t, data.Client.HTTP, other.HTTP)
+}
+
+func TestProviderDataLockDevice(t *testing.T) {
+	server := fakecontrol.NewServer(t)
+	device := server.AddDevice(tailscale.Device{Hostname: "web"})
+	other := server.AddDevice(tailscale.Device{Hostname: "db"})
+
+	baseURL, _ := url.Parse(server.URL())
+	client := createTailscaleClient(baseURL, "test", "-", "api_123", "", "", "", "", nil, nil)
+	data := &providerData{Client: &client}
+	ctx := context.Background()
+
+	lock := func(client *tailscale.Client, deviceID string) func() {
+		t.Helper()
+		unlock, err := data.lockDevice(ctx, client, deviceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return unlock
+	}
+
+	unlock := lock(data.Client, device.NodeID)
+
+	// Other devices, and the same device in other tailnets, aren't locked.
+	lock(data.Client, other.NodeID)()
+	lock(data.ClientForTailnet("example.com"), device.NodeID)()
+
+	// The device is locked by its node ID, even when given its legacy ID.
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := data.lockDevice(ctx, data.Client, device.ID)
+		if err == nil {
+			defer unlock()
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("device was locked while already locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+
+	_, err := data.lockDevice(ctx, data.Client, "n404CNTRL")
+	assert.True(t, tailscale.IsNotFound(err), "expected not found error, got %v", err)
+}
+
+func TestProvider_TailnetOverride(t *testing.T) {
+	const testTailnetOverride = `
+		resource "tailscale_dns_nameservers" "test_nameservers" {
+			tailnet     = "example.com"
+			nameservers = ["8.8.8.8"]
+		}`
+
+	var paths []string
+	resource.Test(t, resource.TestCase{
+		IsUnitTest: true,
+		PreCheck: func() {
+			testServer.HandleRequest = func(method, path string) TestResponse {
+				paths = append(paths, path)
+				return TestResponse{Code: http.StatusOK, Body: map[string][]string{"dns": {"8.8.8.8"}}}
+			}
+		},
+		ProtoV5ProviderFactories: testProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testTailnetOverride,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tailscale_dns_nameservers.test_nameservers", "tailnet", "example.com"),
+					func(s *terraform.State) error {
+						if !slices.Contains(paths, "/api/v2/tailnet/example.com/dns/nameservers") {
+							return fmt.Errorf("expected requests to the example.com tailnet, got %v", paths)
+						}
+						if slices.ContainsFunc(paths, func(p string) bool { return strings.HasPrefix(p, "/api/v2/tailnet/-/") }) {
+							return fmt.Errorf("expected no requests to the default tailnet, got %v", paths)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:  "tailscale_dns_nameservers.test_nameservers",
+				ImportState:   true,
+				ImportStateId: "example.com/nameservers",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if err := assertEqual(1, len(states), "wrong number of imported resources"); err != nil {
+						return err
+					}
+					if err := assertEqual("example.com", states[0].Attributes["tailnet"], "wrong tailnet"); err != nil {
+						return err
+					}
+					return assertEqual("nameservers", states[0].ID, "wrong ID")
+				},
+			},
+		},
+	})
+}
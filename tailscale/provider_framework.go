@@ -8,23 +8,36 @@ package tailscale
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"tailscale.com/client/tailscale/v2"
 	"tailscale.com/wif"
 )
 
-var _ provider.Provider = NewFrameworkProvider()
+var (
+	_ provider.Provider                       = NewFrameworkProvider()
+	_ provider.ProviderWithActions            = &tailscaleProvider{}
+	_ provider.ProviderWithEphemeralResources = &tailscaleProvider{}
+	_ provider.ProviderWithFunctions          = &tailscaleProvider{}
+	_ provider.ProviderWithListResources      = &tailscaleProvider{}
+)
 
 type tailscaleProvider struct {
 	Client tailscale.Client
@@ -93,6 +106,31 @@ func (p *tailscaleProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Optional:    true,
 				Description: "User-Agent header for API requests.",
 			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of times to retry API requests that were rate limited or failed with a transient server error. Only requests that are safe to repeat are retried after a server error. Set to 0 to disable retries. Defaults to 3.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum duration to wait between retries of an API request, such as `30s`. Retries use exponential backoff with jitter, or the delay requested by the API in the `Retry-After` header, up to this limit. Defaults to `30s`.",
+				Validators: []validator.String{
+					retryDeadlineValidator{},
+				},
+			},
+			"validate_tags": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to check during plan that the tags of tailnet keys, devices, OAuth clients, federated identities and services are declared in the `tagOwners` section of the policy file. The policy file is read once per run. Tags are not checked if the provider's credentials can't read the policy file. Resources with tags that are added to the policy file by a `tailscale_acl`, `tailscale_policy` or `tailscale_policy_fragment` resource in the same run must depend on it with `depends_on`: Terraform plans unrelated resources concurrently, so otherwise their tags may be checked against the current policy file. Defaults to `true`.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of API requests the provider will make concurrently. Defaults to no limit.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
 		},
 	}
 }
@@ -108,6 +146,10 @@ type tailscaleProviderModel struct {
 	BaseURL                              types.String `tfsdk:"base_url"`
 	UserAgent                            types.String `tfsdk:"user_agent"`
 	Scopes                               types.List   `tfsdk:"scopes"`
+	MaxRetries                           types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait                         types.String `tfsdk:"retry_max_wait"`
+	MaxConcurrentRequests                types.Int64  `tfsdk:"max_concurrent_requests"`
+	ValidateTags                         types.Bool   `tfsdk:"validate_tags"`
 }
 
 // Configure sets up the Tailscale client based on the provider-level data.
@@ -182,6 +224,23 @@ func (p *tailscaleProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	maxRetries := int64(defaultMaxRetries)
+	if !data.MaxRetries.IsNull() {
+		maxRetries = data.MaxRetries.ValueInt64()
+	}
+
+	retryMaxWait := defaultRetryMaxWait
+	if !data.RetryMaxWait.IsNull() {
+		retryMaxWait, err = time.ParseDuration(data.RetryMaxWait.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not parse retry_max_wait",
+				fmt.Sprintf("While configuring the provider, "+
+					"the retry_max_wait %q could not be parsed: %v", data.RetryMaxWait.ValueString(), err),
+			)
+		}
+	}
+
 	if err := validateProviderCreds(apiKey, oauthClientID, oauthClientSecret, identityToken, audience); err != nil {
 		resp.Diagnostics.AddError("Provider credentials error", err.Error())
 	}
@@ -190,12 +249,21 @@ func (p *tailscaleProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	p.Client = createTailscaleClient(parsedBaseURL, userAgent, tailnet, apiKey, oauthClientID, oauthClientSecret, identityToken, audience, scopes)
+	httpClient := newHTTPClient(int(maxRetries), retryMaxWait, int(data.MaxConcurrentRequests.ValueInt64()))
+	p.Client = createTailscaleClient(parsedBaseURL, userAgent, tailnet, apiKey, oauthClientID, oauthClientSecret, identityToken, audience, scopes, httpClient)
 
-	// Make the Tailscale client available during DataSource and Resource
-	// type Configure methods.
-	resp.ResourceData = &p.Client
-	resp.DataSourceData = &p.Client
+	// Make the Tailscale client available during DataSource, Resource,
+	// EphemeralResource, ListResource and Action type Configure methods. Objects
+	// in other tailnets use clients created on demand with the same credentials.
+	pd := &providerData{
+		Client:       &p.Client,
+		validateTags: data.ValidateTags.IsNull() || data.ValidateTags.ValueBool(),
+	}
+	resp.ResourceData = pd
+	resp.DataSourceData = pd
+	resp.EphemeralResourceData = pd
+	resp.ListResourceData = pd
+	resp.ActionData = pd
 }
 
 // resolveValueFromFile returns the value as-is, or if it starts with "file:",
@@ -224,8 +292,14 @@ func (p *tailscaleProvider) Resources(_ context.Context) []func() resource.Resou
 		NewAWSExternalIDResource,
 		NewContactsResource,
 		NewDeviceAuthorizationResource,
+		NewDeviceIPv4AddressResource,
 		NewDeviceKeyResource,
+		NewDeviceNameResource,
+		NewDevicePostureAttributesResource,
+		NewDeviceResource,
+		NewDeviceSubnetRouteResource,
 		NewDeviceSubnetRoutesResource,
+		NewDeviceTagResource,
 		NewDeviceTagsResource,
 		NewDNSConfigurationResource,
 		NewDNSNameserversResource,
@@ -234,6 +308,8 @@ func (p *tailscaleProvider) Resources(_ context.Context) []func() resource.Resou
 		NewDNSSplitNameserversResource,
 		NewLogstreamConfigurationResource,
 		NewOAuthClientResource,
+		NewPolicyResource,
+		NewPolicyFragmentResource,
 		NewPostureIntegrationResource,
 		NewServiceResource,
 		NewTailnetKeyResource,
@@ -248,6 +324,7 @@ func (p *tailscaleProvider) DataSources(_ context.Context) []func() datasource.D
 	return []func() datasource.DataSource{
 		New4Via6DataSource,
 		NewACLDataSource,
+		NewACLCheckDataSource,
 		NewMultipleUsersDataSource,
 		NewSingleUserDataSource,
 		NewMultipleDevicesDataSource,
@@ -256,6 +333,47 @@ func (p *tailscaleProvider) DataSources(_ context.Context) []func() datasource.D
 	}
 }
 
+// EphemeralResources returns a slice of ephemeral resources.
+func (p *tailscaleProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewTailnetKeyEphemeralResource,
+	}
+}
+
+// ListResources returns a slice of list resources.
+func (p *tailscaleProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewDeviceKeyListResource,
+		NewDeviceTagsListResource,
+		NewFederatedIdentityListResource,
+		NewOAuthClientListResource,
+		NewServiceListResource,
+		NewTailnetKeyListResource,
+	}
+}
+
+// Actions returns a slice of actions.
+func (p *tailscaleProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewDeviceAuthorizeAction,
+		NewDeviceDeleteAction,
+		NewDeviceExpireAction,
+		NewWebhookRotateSecretAction,
+		NewWebhookTestEventAction,
+	}
+}
+
+// Functions returns a slice of functions.
+func (p *tailscaleProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewVia6Function,
+		NewVia6DecodeFunction,
+		NewHuJSONToJSONFunction,
+		NewHuJSONFormatFunction,
+		NewNormalizeTagsFunction,
+	}
+}
+
 // coalesce chooses a string value in order of decreasing priority.
 //
 // It returns the first value which is non-empty -- either configuration data, or
@@ -273,13 +391,17 @@ func coalesce(val types.String, fallbacks ...string) string {
 }
 
 // createTailscaleClient creates a new Tailscale API client based on the credentials
-// provided to the Terraform provider.
-func createTailscaleClient(baseURL *url.URL, userAgent, tailnet, apiKey, oauthClientID, oauthClientSecret, identityToken, audience string, scopes []string) tailscale.Client {
+// provided to the Terraform provider. All requests made by the client are logged
+// to the [apiLogSubsystem].
+func createTailscaleClient(baseURL *url.URL, userAgent, tailnet, apiKey, oauthClientID, oauthClientSecret, identityToken, audience string, scopes []string, httpClient *http.Client) tailscale.Client {
+	httpClient = withAPILogging(httpClient)
+
 	if oauthClientID != "" && oauthClientSecret != "" {
 		return tailscale.Client{
 			BaseURL:   baseURL,
 			UserAgent: userAgent,
 			Tailnet:   tailnet,
+			HTTP:      httpClient,
 			Auth: &tailscale.OAuth{
 				ClientID:     oauthClientID,
 				ClientSecret: oauthClientSecret,
@@ -291,6 +413,7 @@ func createTailscaleClient(baseURL *url.URL, userAgent, tailnet, apiKey, oauthCl
 			BaseURL:   baseURL,
 			UserAgent: userAgent,
 			Tailnet:   tailnet,
+			HTTP:      httpClient,
 			Auth: &tailscale.IdentityFederation{
 				ClientID: oauthClientID,
 				IDTokenFunc: func() (string, error) {
@@ -309,6 +432,7 @@ func createTailscaleClient(baseURL *url.URL, userAgent, tailnet, apiKey, oauthCl
 			UserAgent: userAgent,
 			APIKey:    apiKey,
 			Tailnet:   tailnet,
+			HTTP:      httpClient,
 		}
 	}
 }
@@ -22,6 +22,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 
 	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
 )
 
 // getTestAccClient returns an instance of [tailscale.Client] for use in
@@ -99,6 +101,24 @@ func testProviderFactories(t *testing.T) map[string]func() (tfprotov5.ProviderSe
 	}
 }
 
+// testFakeControlProviderFactories returns provider factories for a provider
+// that talks to a new fake control plane, so that tests can exercise the full
+// lifecycle of resources without access to a real tailnet.
+func testFakeControlProviderFactories(t *testing.T) (map[string]func() (tfprotov5.ProviderServer, error), *fakecontrol.Server) {
+	t.Helper()
+
+	server := fakecontrol.NewServer(t)
+	return map[string]func() (tfprotov5.ProviderServer, error){
+		"tailscale": func() (tfprotov5.ProviderServer, error) {
+			t.Setenv("TAILSCALE_API_KEY", "api_123")
+			t.Setenv("TAILSCALE_BASE_URL", server.URL())
+
+			provider := NewFrameworkProvider()
+			return providerserver.NewProtocol5(provider)(), nil
+		},
+	}, server
+}
+
 func testResourceCreated(name, hcl string) resource.TestStep {
 	return resource.TestStep{
 		ResourceName:       name,
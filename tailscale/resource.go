@@ -7,17 +7,30 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"tailscale.com/client/tailscale/v2"
 )
 
 // ResourceBase is a base struct for all Tailscale resources.
 //
 // All resources should extend this struct, then the authenticated [Client] will
-// be available in their CRUD methods.
+// be available in their CRUD methods. Resources that manage objects in a tailnet
+// other than the provider's should use [ResourceBase.ClientForTailnet] instead.
 type ResourceBase struct {
 	Client *tailscale.Client
+
+	providerData *providerData
 }
 
 // Configure attaches the client to the resource, so it can be used in the
@@ -27,28 +40,127 @@ func (d *ResourceBase) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*tailscale.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
 			fmt.Sprintf(
-				"Expected *tailscale.Client, got: %T. Please report this error at https://github.com/tailscale/tailscale.",
+				"Expected *providerData, got: %T. Please report this error at https://github.com/tailscale/tailscale.",
 				req.ProviderData),
 		)
 		return
 	}
 
-	d.Client = client
+	d.Client = data.Client
+	d.providerData = data
+}
+
+// ClientForTailnet returns the client for the resource's `tailnet` attribute,
+// or the provider's client if it is not set.
+func (d *ResourceBase) ClientForTailnet(tailnet types.String) *tailscale.Client {
+	return clientForTailnet(d.providerData, tailnet)
+}
+
+// IdentityTailnet returns the tailnet to record in the identity of an object
+// with the given `tailnet` attribute.
+func (d *ResourceBase) IdentityTailnet(tailnet types.String) types.String {
+	return identityTailnet(d.providerData, tailnet)
+}
+
+// LockDevice locks the device with the given ID in the resource's tailnet
+// until the returned function is called. See [providerData.lockDevice].
+func (d *ResourceBase) LockDevice(ctx context.Context, tailnet types.String, deviceID string) (func(), error) {
+	if d.providerData == nil {
+		return func() {}, nil
+	}
+	return d.providerData.lockDevice(ctx, d.ClientForTailnet(tailnet), deviceID)
+}
+
+// tailnetResourceAttribute returns the optional `tailnet` attribute that is
+// shared by all resources. Moving an object to another tailnet replaces it.
+func tailnetResourceAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: tailnetAttributeDescription,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
 }
 
-// ResourceImportedByID is a resource that uses the `id` as the import identifier.
-type ResourceImportedByID struct {
+// writeOnlySecretAttributes returns a write-only variant of the sensitive
+// attribute `name`, named `<name>_wo`, along with its `<name>_wo_version`
+// trigger. Write-only values are never stored in state, so Terraform cannot
+// detect when they change; the version must be changed to send a new value.
+func writeOnlySecretAttributes(name, description string) (schema.StringAttribute, schema.Int64Attribute) {
+	woName := name + "_wo"
+	versionName := woName + "_version"
+
+	secret := schema.StringAttribute{
+		Description: fmt.Sprintf("%s This value is write-only and is never stored in state. Requires Terraform 1.11 or later. Conflicts with `%s`.", description, name),
+		Optional:    true,
+		Sensitive:   true,
+		WriteOnly:   true,
+		Validators: []validator.String{
+			stringvalidator.ConflictsWith(path.MatchRoot(name)),
+			stringvalidator.AlsoRequires(path.MatchRoot(versionName)),
+		},
+	}
+	version := schema.Int64Attribute{
+		Description: fmt.Sprintf("A version number for `%s`. Change it whenever the value of `%s` changes so that the new value is sent to Tailscale.", woName, woName),
+		Optional:    true,
+		Validators: []validator.Int64{
+			int64validator.AlsoRequires(path.MatchRoot(woName)),
+		},
+	}
+	return secret, version
+}
+
+// writeOnlyStringValue returns the value of the write-only attribute `name`
+// from config, or fallback if it is not set. Write-only values are always null
+// in the plan, so they can only be read from the config.
+func writeOnlyStringValue(ctx context.Context, config tfsdk.Config, name string, fallback types.String, diags *diag.Diagnostics) types.String {
+	var value types.String
+	diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+	if value.IsNull() || value.IsUnknown() {
+		return fallback
+	}
+	return value
+}
+
+// ResourceIdentifiedByTailnet is a resource that manages a setting of which
+// there is one per tailnet, such as the policy file. Its identity is made up of
+// the tailnet alone.
+type ResourceIdentifiedByTailnet struct {
 	ResourceBase
 }
 
+// IdentitySchema defines an identity made up of the tailnet.
+func (r *ResourceIdentifiedByTailnet) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"tailnet": tailnetIdentityAttribute(),
+		},
+	}
+}
+
 // ImportState is called to import the state of a resource instance.
 //
 // We set the ID, and then allow the Read() method to fully import the data.
-func (r *ResourceImportedByID) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// The ID may be prefixed with a tailnet as `<tailnet>/<id>`. When importing by
+// identity there is no ID, so a random one is generated as for new resources.
+func (r *ResourceIdentifiedByTailnet) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != "" {
+		id := importTailnetAndID(ctx, req, resp)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+		return
+	}
+
+	importIdentityTailnet(ctx, r.providerData, req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), createUUID())...)
+}
+
+// SetIdentity records the identity of the resource in the given tailnet.
+func (r *ResourceIdentifiedByTailnet) SetIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, tailnet types.String) diag.Diagnostics {
+	return identity.Set(ctx, tailnetIdentityModel{Tailnet: r.IdentityTailnet(tailnet)})
 }
@@ -4,9 +4,14 @@
 package tailscale
 
 import (
+	"bytes"
 	"context"
-	"strings"
+	"encoding/json"
+	"errors"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -14,20 +19,32 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/tailscale/hujson"
+
+	"tailscale.com/client/tailscale/v2"
 )
 
 var (
-	_ resource.Resource                = &aclResource{}
-	_ resource.ResourceWithConfigure   = &aclResource{}
-	_ resource.ResourceWithImportState = &aclResource{}
-	_ resource.ResourceWithModifyPlan  = &aclResource{}
+	_ resource.Resource                   = &aclResource{}
+	_ resource.ResourceWithConfigure      = &aclResource{}
+	_ resource.ResourceWithImportState    = &aclResource{}
+	_ resource.ResourceWithIdentity       = &aclResource{}
+	_ resource.ResourceWithModifyPlan     = &aclResource{}
+	_ resource.ResourceWithValidateConfig = &aclResource{}
 )
 
 type aclResourceModel struct {
 	ID                       types.String `tfsdk:"id"`
 	ACL                      types.String `tfsdk:"acl"`
+	ETag                     types.String `tfsdk:"etag"`
+	Lint                     types.String `tfsdk:"lint"`
+	OnDestroy                types.String `tfsdk:"on_destroy"`
+	OnConflict               types.String `tfsdk:"on_conflict"`
 	OverwriteExistingContent types.Bool   `tfsdk:"overwrite_existing_content"`
 	ResetACLOnDestroy        types.Bool   `tfsdk:"reset_acl_on_destroy"`
+	Tailnet                  types.String `tfsdk:"tailnet"`
 }
 
 // NewACLResource returns a new ACL resource.
@@ -36,8 +53,7 @@ func NewACLResource() resource.Resource {
 }
 
 type aclResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -47,7 +63,53 @@ func (r *aclResource) Metadata(_ context.Context, req resource.MetadataRequest,
 
 const resourceACLDescription = `The acl resource allows you to configure a Tailscale policy file. See https://tailscale.com/kb/1395/tailnet-policy-file for more information. Note that this resource will completely overwrite existing policy file contents for a given tailnet.
 
-The policy file is validated against the Tailscale API during planning, so syntax errors and failing tests (the top-level "tests" section) are surfaced before apply.`
+The policy file is validated against the Tailscale API during planning, so syntax errors and failing tests (the top-level "tests" section) are surfaced before apply. The tests and sshTests sections are also evaluated locally, so failing tests are reported even if the Tailscale API cannot be reached. Changes to groups, tag owners, ACL rules, grants and SSH rules are summarised in a warning during planning, as the plan only shows the policy file as a whole.
+
+Updates are only applied if the policy file has not changed since it was last read by Terraform, so that changes made elsewhere, for example in the admin console, are not silently overwritten. This can be disabled with on_conflict.`
+
+const (
+	// aclOnConflictFail fails an update if the policy file has been changed
+	// since it was last read.
+	aclOnConflictFail = "fail"
+	// aclOnConflictOverwrite overwrites the policy file regardless of any
+	// changes made since it was last read.
+	aclOnConflictOverwrite = "overwrite"
+)
+
+const (
+	// aclOnDestroyKeep leaves the policy file as it is when the resource is
+	// destroyed.
+	aclOnDestroyKeep = "keep"
+	// aclOnDestroyReset resets the policy file to the Tailscale default when
+	// the resource is destroyed.
+	aclOnDestroyReset = "reset"
+	// aclOnDestroyRestore restores the policy file as it was before the
+	// resource was created when the resource is destroyed.
+	aclOnDestroyRestore = "restore"
+)
+
+// aclOriginalPolicyKey is the private state key under which the policy file
+// is stored as it was before the resource was created.
+const aclOriginalPolicyKey = "original_policy"
+
+// aclOriginalPolicy is the private state stored in Create, so that the policy
+// file can be restored when the resource is destroyed.
+type aclOriginalPolicy struct {
+	HuJSON string `json:"hujson"`
+
+	// Default is whether the policy file had never been changed from the
+	// tailnet's default.
+	Default bool `json:"default,omitempty"`
+}
+
+const (
+	// aclLintError reports problems found by linting the policy file as
+	// errors.
+	aclLintError = "error"
+	// aclLintWarning reports problems found by linting the policy file as
+	// warnings.
+	aclLintWarning = "warning"
+)
 
 // From https://github.com/hashicorp/terraform-plugin-sdk/blob/34d8a9ebca6bed68fddb983123d6fda72481752c/internal/configs/hcl2shim/values.go#L19
 // TODO: use an exported variable when https://github.com/hashicorp/terraform-plugin-sdk/issues/803 has been addressed.
@@ -57,6 +119,7 @@ func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 	resp.Schema = schema.Schema{
 		Description: resourceACLDescription,
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -71,6 +134,25 @@ func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 				Validators: []validator.String{
 					aclHuJSONValidator{},
+					aclTestsValidator{},
+				},
+			},
+			"etag": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ETag of the policy file when it was last read or written by Terraform. Unless on_conflict is `overwrite`, updates are only applied if the policy file still has this ETag.",
+			},
+			"lint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Whether problems found by linting the policy file are reported as errors or as warnings, either `error` or `warning`. The linter reports references to groups, tags, hosts, postures and ipsets that are not defined, definitions that are not used, duplicate keys, and rules that are shadowed by an earlier rule. Defaults to `warning`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(aclLintError, aclLintWarning),
+				},
+			},
+			"on_conflict": schema.StringAttribute{
+				Optional:    true,
+				Description: "What to do when updating a policy file that has been changed since it was last read by Terraform, for example in the admin console. Either `fail` to report the changes and leave the policy file as it is, or `overwrite` to replace them. Defaults to `fail`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(aclOnConflictFail, aclOnConflictOverwrite),
 				},
 			},
 			"overwrite_existing_content": schema.BoolAttribute{
@@ -78,13 +160,47 @@ func (r *aclResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Description: "If true, will skip requirement to import acl before allowing changes. Be careful, can cause the policy file to be overwritten",
 			},
 			"reset_acl_on_destroy": schema.BoolAttribute{
+				Optional:           true,
+				Description:        "If true, will reset the policy file for the Tailnet to the default when this resource is destroyed",
+				DeprecationMessage: "Use on_destroy = \"reset\" instead.",
+			},
+			"on_destroy": schema.StringAttribute{
 				Optional:    true,
-				Description: "If true, will reset the policy file for the Tailnet to the default when this resource is destroyed",
+				Description: "What to do with the policy file when this resource is destroyed. Either `keep` to leave it as it is, `reset` to reset it to the Tailscale default, or `restore` to restore the policy file as it was before this resource was created, such as a policy file that was overwritten with overwrite_existing_content. A policy file that had never been changed from the default is reset to the default. The policy file is only restored if it hasn't been changed since it was last read by Terraform, unless on_conflict is `overwrite`. Defaults to `reset` if reset_acl_on_destroy is true, or `keep` otherwise.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(aclOnDestroyKeep, aclOnDestroyReset, aclOnDestroyRestore),
+					stringvalidator.ConflictsWith(path.MatchRoot("reset_acl_on_destroy")),
+				},
 			},
 		},
 	}
 }
 
+// ValidateConfig lints the policy file, which the Tailscale API accepts even if
+// it refers to groups or tags that don't exist.
+func (r *aclResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config aclResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.ACL.IsUnknown() || config.ACL.IsNull() {
+		return
+	}
+
+	// Policy files that can't be parsed are reported by aclHuJSONValidator.
+	findings, err := lintPolicy(config.ACL.ValueString())
+	if err != nil {
+		return
+	}
+
+	for _, f := range findings {
+		detail := fmt.Sprintf("%s: %s", f.Path, f.Detail)
+		if config.Lint.ValueString() == aclLintError {
+			resp.Diagnostics.AddAttributeError(path.Root("acl"), f.Summary, detail)
+		} else {
+			resp.Diagnostics.AddAttributeWarning(path.Root("acl"), f.Summary, detail)
+		}
+	}
+}
+
 func (r *aclResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state aclResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -92,13 +208,16 @@ func (r *aclResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
-	acl, err := r.Client.PolicyFile().Raw(ctx)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	acl, err := r.ClientForTailnet(state.Tailnet).PolicyFile().Raw(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch ACL", err.Error())
 		return
 	}
 
 	state.ACL = types.StringValue(acl.HuJSON)
+	state.ETag = types.StringValue(acl.ETag)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -109,15 +228,28 @@ func (r *aclResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	// Setting the `ts-default` ETag will make this operation succeed only if
-	// ACL contents has never been changed from its default value.
-	var etag string
-	if !plan.OverwriteExistingContent.ValueBool() {
-		etag = "ts-default"
+	client := r.ClientForTailnet(plan.Tailnet)
+
+	// Keep the policy file that this resource takes ownership of, so that it
+	// can be restored when the resource is destroyed.
+	original, err := client.PolicyFile().Raw(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to fetch ACL", err.Error())
+		return
 	}
 
-	if err := r.Client.PolicyFile().Set(ctx, plan.ACL.ValueString(), etag); err != nil {
-		if strings.HasSuffix(err.Error(), "(412)") {
+	// Setting the `ts-default` ETag will make this operation succeed only if
+	// ACL contents has never been changed from its default value. This is
+	// also how a default policy file is told apart from a custom one with the
+	// same content, so that it is reset rather than restored as it is.
+	isDefault := true
+	err = client.PolicyFile().Set(ctx, plan.ACL.ValueString(), "ts-default")
+	if isPreconditionFailed(err) && plan.OverwriteExistingContent.ValueBool() {
+		isDefault = false
+		err = client.PolicyFile().Set(ctx, plan.ACL.ValueString(), "")
+	}
+	if err != nil {
+		if isPreconditionFailed(err) {
 			resp.Diagnostics.AddError("Overwrite Protected",
 				"You are trying to overwrite a non-default policy. Please import the ACL first or set overwrite_existing_content = true.")
 			return
@@ -126,8 +258,17 @@ func (r *aclResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	private, err := json.Marshal(aclOriginalPolicy{HuJSON: original.HuJSON, Default: isDefault})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to store original ACL", err.Error())
+		return
+	}
+
 	plan.ID = types.StringValue(createUUID())
+	plan.ETag = r.readETag(ctx, client, plan.ACL.ValueString(), &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, aclOriginalPolicyKey, private)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *aclResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -137,17 +278,117 @@ func (r *aclResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	err := r.Client.PolicyFile().Set(ctx, plan.ACL.ValueString(), "")
-	if err != nil {
+	var state aclResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only update the policy file if it hasn't changed since it was last read,
+	// unless conflicting changes should be overwritten. The ETag is unknown for
+	// resources created by older versions of the provider, or whose policy file
+	// was changed while Terraform wrote it, until they are refreshed.
+	var etag string
+	if plan.OnConflict.ValueString() != aclOnConflictOverwrite {
+		etag = state.ETag.ValueString()
+	}
+
+	client := r.ClientForTailnet(plan.Tailnet)
+	if err := client.PolicyFile().Set(ctx, plan.ACL.ValueString(), etag); err != nil {
+		if isPreconditionFailed(err) {
+			resp.Diagnostics.AddError("Policy file changed out-of-band", r.conflictDetail(ctx, client, state.ACL.ValueString()))
+			return
+		}
 		resp.Diagnostics.AddError("Failed to update ACL", err.Error())
 		return
 	}
 
+	plan.ETag = r.readETag(ctx, client, plan.ACL.ValueString(), &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
-// ModifyPlan validates the planned ACL against the Tailscale API so that
-// syntax errors and failing tests are surfaced at plan time rather than apply.
+// readETag returns the ETag of the policy file written by Terraform, which
+// the API doesn't return when setting it. The policy file is read again, and
+// if it no longer has the written content, it has been changed by someone
+// else in the meantime and its ETag isn't recorded as Terraform's, so the
+// ETag is null until the next refresh.
+func (r *aclResource) readETag(ctx context.Context, client *tailscale.Client, written string, diags *diag.Diagnostics) types.String {
+	acl, err := client.PolicyFile().Raw(ctx)
+	if err != nil {
+		diags.AddError("Failed to fetch ACL", err.Error())
+		return types.StringNull()
+	}
+
+	if !samePolicyContent(acl.HuJSON, written) {
+		diags.AddWarning("Policy file changed out-of-band",
+			"The policy file was changed outside of Terraform while it was being written. Refresh the state to review the changes.")
+		return types.StringNull()
+	}
+	return types.StringValue(acl.ETag)
+}
+
+// samePolicyContent reports whether two HuJSON policy files have the same
+// content, ignoring comments and formatting.
+func samePolicyContent(a, b string) bool {
+	compact := func(policy string) []byte {
+		standardized, err := hujson.Standardize([]byte(policy))
+		if err != nil {
+			return nil
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, standardized); err != nil {
+			return nil
+		}
+		return buf.Bytes()
+	}
+	ca, cb := compact(a), compact(b)
+	return ca != nil && bytes.Equal(ca, cb)
+}
+
+// conflictDetail describes a failed update of a policy file that has been
+// changed since it was last read by Terraform, including the changes that
+// would have been overwritten.
+func (r *aclResource) conflictDetail(ctx context.Context, client *tailscale.Client, previous string) string {
+	detail := "The policy file has been changed outside of Terraform since it was last read, and has not been updated so that these changes are not overwritten. " +
+		"Refresh the state to review the changes and plan again, or set on_conflict = \"overwrite\" to replace them."
+
+	acl, err := client.PolicyFile().Raw(ctx)
+	if err != nil {
+		return fmt.Sprintf("%s\n\nThe current policy file could not be fetched: %s", detail, err)
+	}
+
+	diff, err := policyFileDiff(previous, acl.HuJSON)
+	if err != nil || diff == "" {
+		return detail
+	}
+	return fmt.Sprintf("%s\n\nChanges made outside of Terraform:\n\n%s", detail, diff)
+}
+
+// policyFileDiff returns a unified diff between two HuJSON policy files. Both
+// are formatted first, so that only changes to their contents are shown.
+func policyFileDiff(from, to string) (string, error) {
+	format := func(policy string) string {
+		if formatted, err := hujson.Format([]byte(policy)); err == nil {
+			return string(formatted)
+		}
+		return policy
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(format(from)),
+		B:        difflib.SplitLines(format(to)),
+		FromFile: "last read by Terraform",
+		ToFile:   "current",
+		Context:  3,
+	})
+}
+
+// ModifyPlan summarises the changes to the policy file, and validates the
+// planned ACL against the Tailscale API so that syntax errors and failing tests
+// are surfaced at plan time rather than apply. If the API can't be reached, the
+// policy file's tests have still been run locally by aclTestsValidator, so this
+// is only a warning.
 func (r *aclResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
 	// Nothing to validate when destroying or before the provider is configured.
 	if req.Plan.Raw.IsNull() || r.Client == nil {
@@ -160,11 +401,45 @@ func (r *aclResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReq
 		return
 	}
 
+	var state aclResourceModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	// Tags of other resources are validated against the planned policy file.
+	if !plan.ACL.Equal(state.ACL) {
+		r.PlanPolicyChange(plan.Tailnet, plan.ACL)
+	}
+
 	if plan.ACL.IsUnknown() || plan.ACL.IsNull() {
 		return
 	}
 
-	if err := r.Client.PolicyFile().Validate(ctx, plan.ACL.ValueString()); err != nil {
+	if !req.State.Raw.IsNull() {
+		// The whole policy file is a single string, so summarise what has
+		// changed in it to make the plan easier to review.
+		if changes := policyFileChanges(state.ACL.ValueString(), plan.ACL.ValueString()); len(changes) > 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("acl"),
+				"Policy file changes",
+				fmt.Sprintf("The following changes will be made to the policy file:\n\n%s", formatPolicyDiff(changes)),
+			)
+		}
+	}
+
+	err := r.ClientForTailnet(plan.Tailnet).PolicyFile().Validate(ctx, plan.ACL.ValueString())
+	var apiErr tailscale.APIError
+	switch {
+	case err == nil:
+	case !errors.As(err, &apiErr):
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("acl"),
+			"Policy file not validated",
+			fmt.Sprintf("The policy file could not be validated by the Tailscale API, and its tests were only run locally: %s", err),
+		)
+	default:
 		resp.Diagnostics.AddAttributeError(
 			path.Root("acl"),
 			"Invalid ACL",
@@ -176,15 +451,66 @@ func (r *aclResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanReq
 func (r *aclResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state aclResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-
-	// Each tailnet always has an associated ACL file, so deleting a resource will
-	// only remove it from Terraform state, leaving ACL contents intact.
-	if !state.ResetACLOnDestroy.ValueBool() {
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Setting the ACL to an empty string resets its value to the default.
-	if err := r.Client.PolicyFile().Set(ctx, "", ""); err != nil {
-		resp.Diagnostics.AddError("Failed to reset ACL", err.Error())
+	onDestroy := state.OnDestroy.ValueString()
+	if state.OnDestroy.IsNull() {
+		onDestroy = aclOnDestroyKeep
+		if state.ResetACLOnDestroy.ValueBool() {
+			onDestroy = aclOnDestroyReset
+		}
+	}
+
+	client := r.ClientForTailnet(state.Tailnet)
+	switch onDestroy {
+	case aclOnDestroyKeep:
+		// Each tailnet always has an associated ACL file, so deleting a resource will
+		// only remove it from Terraform state, leaving ACL contents intact.
+		return
+
+	case aclOnDestroyReset:
+		// Setting the ACL to an empty string resets its value to the default.
+		if err := client.PolicyFile().Set(ctx, "", ""); err != nil {
+			resp.Diagnostics.AddError("Failed to reset ACL", err.Error())
+		}
+
+	case aclOnDestroyRestore:
+		raw, diags := req.Private.GetKey(ctx, aclOriginalPolicyKey)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if raw == nil {
+			resp.Diagnostics.AddWarning("Policy file not restored",
+				"The policy file as it was before this resource was created is not known, for example because the resource was imported or created by an older version of the provider, so it has been left as it is.")
+			return
+		}
+
+		var original aclOriginalPolicy
+		if err := json.Unmarshal(raw, &original); err != nil {
+			resp.Diagnostics.AddError("Failed to read original ACL", err.Error())
+			return
+		}
+
+		// A default policy file is reset, rather than set to the same content
+		// as a custom policy file, so that it keeps following the default.
+		policy := original.HuJSON
+		if original.Default {
+			policy = ""
+		}
+
+		var etag string
+		if state.OnConflict.ValueString() != aclOnConflictOverwrite {
+			etag = state.ETag.ValueString()
+		}
+		if err := client.PolicyFile().Set(ctx, policy, etag); err != nil {
+			if isPreconditionFailed(err) {
+				resp.Diagnostics.AddError("Policy file changed out-of-band", r.conflictDetail(ctx, client, state.ACL.ValueString()))
+				return
+			}
+			resp.Diagnostics.AddError("Failed to restore ACL", err.Error())
+		}
 	}
 }
@@ -7,18 +7,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 
 	"github.com/tailscale/hujson"
 
 	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
 )
 
 const testACL = `
@@ -172,6 +178,196 @@ func TestProvider_TailscaleACLDiffs(t *testing.T) {
 	})
 }
 
+func TestProvider_TailscaleACLLifecycle(t *testing.T) {
+	const resourceName = "tailscale_acl.test_acl"
+
+	const testACLCreate = `
+		resource "tailscale_acl" "test_acl" {
+			on_destroy = "reset"
+			acl = jsonencode({
+				tagOwners = { "tag:web" = ["autogroup:admin"] }
+			})
+		}`
+
+	const testACLUpdate = `
+		resource "tailscale_acl" "test_acl" {
+			on_destroy = "reset"
+			acl = jsonencode({
+				tagOwners = { "tag:web" = ["autogroup:admin"], "tag:db" = ["autogroup:admin"] }
+			})
+		}`
+
+	factories, server := testFakeControlProviderFactories(t)
+	checkTagOwners := func(expected ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			policy, _ := server.Policy()
+			acl := &tailscale.ACL{}
+			if err := json.Unmarshal([]byte(policy), acl); err != nil {
+				return err
+			}
+			if diff := cmp.Diff(expected, slices.Sorted(maps.Keys(acl.TagOwners))); diff != "" {
+				return fmt.Errorf("unexpected tag owners (-want, +got): %s", diff)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			if policy, _ := server.Policy(); policy != fakecontrol.DefaultPolicy {
+				return fmt.Errorf("policy file was not reset: %s", policy)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testACLCreate,
+				Check: resource.ComposeTestCheckFunc(
+					checkTagOwners("tag:web"),
+					resource.TestCheckResourceAttrSet(resourceName, "etag"),
+				),
+			},
+			{
+				// Changes made outside of Terraform are detected and reverted.
+				PreConfig: func() {
+					server.SetPolicy(`{"tagOwners": {"tag:other": ["autogroup:admin"]}}`)
+				},
+				Config: testACLUpdate,
+				Check:  checkTagOwners("tag:db", "tag:web"),
+			},
+			{
+				// Imported ACLs have a new random ID, so they are matched by
+				// their contents.
+				ResourceName:                         resourceName,
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "acl",
+				ImportStateVerifyIgnore:              []string{"id", "on_destroy", "overwrite_existing_content"},
+			},
+		},
+	})
+}
+
+func TestACLConflictDetail(t *testing.T) {
+	server := fakecontrol.NewServer(t)
+	u, err := url.Parse(server.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+
+	const previous = `{"tagOwners": {"tag:web": ["autogroup:admin"]}}`
+	server.SetPolicy(previous)
+	_, etag := server.Policy()
+
+	// The policy file is changed in the admin console after Terraform read it.
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"], "tag:db": ["autogroup:admin"]}}`)
+
+	err = client.PolicyFile().Set(context.Background(), `{}`, etag)
+	if !isPreconditionFailed(err) {
+		t.Fatalf("expected the update to fail with a precondition error, got %v", err)
+	}
+
+	r := &aclResource{}
+	detail := r.conflictDetail(context.Background(), &client, previous)
+	for _, want := range []string{
+		`on_conflict = "overwrite"`,
+		`--- last read by Terraform`,
+		`+++ current`,
+		`-{"tagOwners": {"tag:web": ["autogroup:admin"]}}`,
+		"+\t\"tagOwners\": {\"tag:web\": [\"autogroup:admin\"], \"tag:db\": [\"autogroup:admin\"]}",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("conflict detail does not contain %q:\n%s", want, detail)
+		}
+	}
+}
+
+func TestProvider_TailscaleACLRestoreOnDestroy(t *testing.T) {
+	const original = `{
+	// Written by hand.
+	"tagOwners": {"tag:other": ["autogroup:admin"]},
+}`
+
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(original)
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			if policy, _ := server.Policy(); policy != original {
+				return fmt.Errorf("policy file was not restored: %s", policy)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_acl" "test_acl" {
+						overwrite_existing_content = true
+						on_destroy                 = "restore"
+						acl = jsonencode({
+							tagOwners = { "tag:web" = ["autogroup:admin"] }
+						})
+					}`,
+			},
+		},
+	})
+}
+
+func TestProvider_TailscaleACLRestoreDefaultOnDestroy(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			if policy, _ := server.Policy(); policy != fakecontrol.DefaultPolicy {
+				return fmt.Errorf("policy file was not restored: %s", policy)
+			}
+
+			// The default policy file is reset rather than written again, so
+			// it still matches the default ETag.
+			baseURL, _ := url.Parse(server.URL())
+			client := &tailscale.Client{BaseURL: baseURL, APIKey: "tskey-api-test", Tailnet: "-"}
+			if err := client.PolicyFile().Set(context.Background(), fakecontrol.DefaultPolicy, "ts-default"); err != nil {
+				return fmt.Errorf("policy file was not reset to the default: %w", err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_acl" "test_acl" {
+						on_destroy = "restore"
+						acl = jsonencode({
+							tagOwners = { "tag:web" = ["autogroup:admin"] }
+						})
+					}`,
+			},
+		},
+	})
+}
+
+func TestProvider_TailscaleACLOverwriteProtected(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"tagOwners": {"tag:other": ["autogroup:admin"]}}`)
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testACL,
+				ExpectError: regexp.MustCompile("Overwrite Protected"),
+			},
+		},
+	})
+}
+
 func TestAccACL(t *testing.T) {
 	const resourceName = "tailscale_acl.test_acl"
 
@@ -398,6 +594,28 @@ func TestProvider_TailscaleACL_InvalidConfig(t *testing.T) {
 				`,
 			ExpectError: regexp.MustCompile(`Attribute acl is invalid HuJSON, got: hujson: line 1, column 1`),
 		},
+		{
+			Name: "lint-error",
+			Config: `
+					resource "tailscale_acl" "example" {
+						lint = "error"
+						acl  = jsonencode({
+							grants = [{ src = ["group:dev"], dst = ["*"], ip = ["*"] }]
+						})
+					}
+				`,
+			ExpectError: regexp.MustCompile(`grants\[0\]\.src\[0\]: group:dev is not defined`),
+		},
+		{
+			Name: "invalid-lint",
+			Config: `
+					resource "tailscale_acl" "example" {
+						lint = "fatal"
+						acl  = "{}"
+					}
+				`,
+			ExpectError: regexp.MustCompile(`Attribute lint value must be one of`),
+		},
 	}
 
 	runExpectedErrorTests(t, testCases)
@@ -424,3 +642,30 @@ func TestProvider_TailscaleACL_ValidationFailsAtPlan(t *testing.T) {
 		},
 	})
 }
+
+func TestACLReadETag(t *testing.T) {
+	server := fakecontrol.NewServer(t)
+	baseURL, err := url.Parse(server.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &tailscale.Client{BaseURL: baseURL, APIKey: "tskey-api-test", Tailnet: "-"}
+
+	const written = `{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`
+	server.SetPolicy(written)
+	_, etag := server.Policy()
+
+	var r aclResource
+	var diags diag.Diagnostics
+	if got := r.readETag(context.Background(), client, "{\n  // Allow all.\n  \"acls\": [{\"action\": \"accept\", \"src\": [\"*\"], \"dst\": [\"*:*\"]}],\n}", &diags); strings.Trim(got.ValueString(), `"`) != etag || diags.HasError() {
+		t.Errorf("readETag() = %v, %v, want %q", got, diags, etag)
+	}
+
+	// A policy file changed by someone else after it was written doesn't have
+	// Terraform's ETag.
+	server.SetPolicy(`{"acls": []}`)
+	diags = nil
+	if got := r.readETag(context.Background(), client, written, &diags); !got.IsNull() || diags.WarningsCount() != 1 {
+		t.Errorf("readETag() = %v, %v, want null ETag and a warning", got, diags)
+	}
+}
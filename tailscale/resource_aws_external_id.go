@@ -14,6 +14,7 @@ import (
 var (
 	_ resource.Resource              = &awsExternalIDResource{}
 	_ resource.ResourceWithConfigure = &awsExternalIDResource{}
+	_ resource.ResourceWithIdentity  = &awsExternalIDResource{}
 )
 
 // NewAWSExternalIDResource returns a new AWS External ID resource.
@@ -35,6 +36,7 @@ func (r *awsExternalIDResource) Schema(_ context.Context, _ resource.SchemaReque
 	resp.Schema = schema.Schema{
 		Description: "The aws_external_id resource allows you to mint an AWS External ID that Tailscale can use to assume an AWS IAM role that you create for the purposes of allowing Tailscale to stream logs to your S3 bucket. See the logstream_configuration resource for more details.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -50,20 +52,33 @@ func (r *awsExternalIDResource) Schema(_ context.Context, _ resource.SchemaReque
 	}
 }
 
+// IdentitySchema defines the identity of the resource. The resource cannot be
+// imported, as there is no way to read an existing external ID.
+func (r *awsExternalIDResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("id", "The External ID.")
+}
+
 type awsExternalIDResourceData struct {
 	ID                    types.String `tfsdk:"id"`
 	ExternalID            types.String `tfsdk:"external_id"`
 	TailscaleAWSAccountID types.String `tfsdk:"tailscale_aws_account_id"`
+	Tailnet               types.String `tfsdk:"tailnet"`
 }
 
 // Create creates a new AWS external ID.
 func (r *awsExternalIDResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan awsExternalIDResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// We pass "reusable: false" on purpose. Otherwise, two tailscale_aws_external_id resources
 	// could end up with the same resource ID (because we use the actual external ID).
 	//
 	// Also, "reusable: true" is an optimization intended for the admin console UI's usage
 	// pattern, and it's not really necessary for Terraform use cases.
-	aid, err := r.Client.Logging().CreateOrGetAwsExternalId(ctx, false)
+	aid, err := r.ClientForTailnet(plan.Tailnet).Logging().CreateOrGetAwsExternalId(ctx, false)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating AWS External ID",
@@ -76,14 +91,30 @@ func (r *awsExternalIDResource) Create(ctx context.Context, req resource.CreateR
 		ID:                    types.StringValue(aid.ExternalID),
 		ExternalID:            types.StringValue(aid.ExternalID),
 		TailscaleAWSAccountID: types.StringValue(aid.TailscaleAWSAccountID),
+		Tailnet:               plan.Tailnet,
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(data.Tailnet),
+		ID:      data.ID,
+	})...)
 }
 
 // There are no GET or DELETE endpoints in the API; this is a create-only resource.
-// These methods are no-ops.
+// These methods are no-ops, except that Read records the identity of resources
+// created before identities were supported.
 func (r *awsExternalIDResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state awsExternalIDResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		ID:      state.ID,
+	})...)
 }
 func (r *awsExternalIDResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 }
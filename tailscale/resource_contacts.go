@@ -22,6 +22,7 @@ var (
 	_ resource.Resource                = &contactsResource{}
 	_ resource.ResourceWithConfigure   = &contactsResource{}
 	_ resource.ResourceWithImportState = &contactsResource{}
+	_ resource.ResourceWithIdentity    = &contactsResource{}
 )
 
 const resourceContactsDescription = `The contacts resource allows you to configure contact details for your Tailscale network. See https://tailscale.com/kb/1224/contact-preferences for more information.
@@ -35,8 +36,7 @@ func NewContactsResource() resource.Resource {
 }
 
 type contactsResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -49,6 +49,7 @@ func (r *contactsResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 	resp.Schema = schema.Schema{
 		Description: resourceContactsDescription,
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -108,6 +109,7 @@ type contactsResourceData struct {
 	ContactAccount  types.Set    `tfsdk:"account"`
 	ContactSupport  types.Set    `tfsdk:"support"`
 	ContactSecurity types.Set    `tfsdk:"security"`
+	Tailnet         types.String `tfsdk:"tailnet"`
 }
 
 type contactModel struct {
@@ -134,6 +136,7 @@ func (r *contactsResource) Create(ctx context.Context, req resource.CreateReques
 
 	plan.ID = types.StringValue(createUUID())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *contactsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -144,7 +147,9 @@ func (r *contactsResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	contacts, err := r.Client.Contacts().Get(ctx)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	contacts, err := r.ClientForTailnet(state.Tailnet).Contacts().Get(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Error fetching contacts", err.Error())
 		return
@@ -196,6 +201,7 @@ func (r *contactsResource) Update(ctx context.Context, req resource.UpdateReques
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *contactsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -233,11 +239,11 @@ func (r *contactsResource) updateContact(ctx context.Context, data *contactsReso
 	}
 
 	contactEmail := models[0].Email.ValueString()
-	if err := r.Client.Contacts().Update(ctx, contactType, tailscale.UpdateContactRequest{Email: &contactEmail}); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).Contacts().Update(ctx, contactType, tailscale.UpdateContactRequest{Email: &contactEmail}); err != nil {
 		diags.AddError("Failed to update contacts", err.Error())
 	}
 
-	if err := r.Client.Contacts().Update(ctx, contactType, tailscale.UpdateContactRequest{Email: &contactEmail}); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).Contacts().Update(ctx, contactType, tailscale.UpdateContactRequest{Email: &contactEmail}); err != nil {
 		diags.AddError("Failed to update contacts", err.Error())
 		return
 	}
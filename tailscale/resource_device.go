@@ -0,0 +1,599 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+const resourceDeviceDescription = `The device resource manages the settings of a single device in one place: its authorization, key expiry, tags, enabled subnet routes, name and custom posture attributes. See https://tailscale.com/kb/1372/manage-devices for more information.
+
+Devices join a tailnet by logging in, so this resource adopts an existing device by its ID or hostname rather than creating one. Settings that are not set in the configuration are left unchanged. Destroying the resource leaves the device in the tailnet, unless ` + "`delete_on_destroy`" + ` is set.
+
+This resource replaces ` + "`tailscale_device_authorization`" + `, ` + "`tailscale_device_key`" + `, ` + "`tailscale_device_tags`" + ` and ` + "`tailscale_device_subnet_routes`" + `, and should not be used together with them for the same device.`
+
+var (
+	_ resource.Resource                = &deviceResource{}
+	_ resource.ResourceWithConfigure   = &deviceResource{}
+	_ resource.ResourceWithImportState = &deviceResource{}
+	_ resource.ResourceWithIdentity    = &deviceResource{}
+	_ resource.ResourceWithModifyPlan  = &deviceResource{}
+)
+
+type deviceResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	DeviceID          types.String `tfsdk:"device_id"`
+	Hostname          types.String `tfsdk:"hostname"`
+	Name              types.String `tfsdk:"name"`
+	Authorized        types.Bool   `tfsdk:"authorized"`
+	KeyExpiryDisabled types.Bool   `tfsdk:"key_expiry_disabled"`
+	Tags              types.Set    `tfsdk:"tags"`
+	Routes            types.Set    `tfsdk:"routes"`
+	PostureAttributes types.Map    `tfsdk:"posture_attributes"`
+	Addresses         types.List   `tfsdk:"addresses"`
+	DeleteOnDestroy   types.Bool   `tfsdk:"delete_on_destroy"`
+	WaitFor           types.String `tfsdk:"wait_for"`
+	Tailnet           types.String `tfsdk:"tailnet"`
+}
+
+// NewDeviceResource returns a new device resource.
+func NewDeviceResource() resource.Resource {
+	return &deviceResource{}
+}
+
+type deviceResource struct {
+	ResourceBase
+}
+
+func (r *deviceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device"
+}
+
+func (r *deviceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: resourceDeviceDescription,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The node ID of the device.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"device_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the device to adopt. Prefer the node ID; the legacy ID also works. Exactly one of `device_id` and `hostname` must be set.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("device_id"), path.MatchRoot("hostname")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The hostname of the device to adopt. It is an error if more than one device has this hostname. The device is not adopted again if its hostname later changes.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The machine name of the device, which is the first label of its MagicDNS name. Defaults to the current machine name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"authorized": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the device is authorized to join the tailnet. Defaults to the current authorization.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_expiry_disabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the device's key never expires. Defaults to the current setting.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags": schema.SetAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The tags applied to the device. Defaults to the current tags.",
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"routes": schema.SetAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The subnet routes that are enabled for the device. Routes must also be advertised by the device to be used. Defaults to the currently enabled routes.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(cidrValidator{}),
+				},
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"posture_attributes": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Custom posture attributes of the device, by key. Keys must start with `custom:`. Values that are numbers or `true` or `false` are set as numbers and booleans. Only the attributes in this map are managed; attributes removed from the map are deleted from the device.",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^custom:`), "must start with custom:")),
+				},
+			},
+			"addresses": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The Tailscale IP addresses of the device.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"delete_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to delete the device from the tailnet when the resource is destroyed. Defaults to `false`, which leaves the device and its settings unchanged.",
+			},
+			"wait_for": schema.StringAttribute{
+				Optional:    true,
+				Description: "If specified, the provider will wait up to this duration, such as `5m`, for the device to register with the tailnet before adopting it. Retries are made every second, so this value should be greater than 1s.",
+				Validators: []validator.String{
+					retryDeadlineValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (r *deviceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The ID of the device.")
+}
+
+// ImportState imports the resource by device ID or by identity.
+func (r *deviceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, r.providerData, "device_id", req, resp)
+}
+
+// ModifyPlan validates the planned tags against the policy file, and warns
+// that destroying the resource leaves the device in the tailnet.
+func (r *deviceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		var deleteOnDestroy types.Bool
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("delete_on_destroy"), &deleteOnDestroy)...)
+		if !deleteOnDestroy.ValueBool() {
+			resp.Diagnostics.AddWarning(
+				"Resource Destruction Considerations",
+				"Applying this resource destruction will only remove the resource from the Terraform state and "+
+					"will not modify the device. Set delete_on_destroy to delete the device from the tailnet.",
+			)
+		}
+		return
+	}
+
+	r.ValidateTags(ctx, req, resp, path.Root("tags"))
+}
+
+func (r *deviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	device := r.adopt(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.update(ctx, device, &plan, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  r.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (r *deviceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  r.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
+	if !r.read(ctx, &state, &resp.Diagnostics) {
+		// If the device is not found, remove from the state so we can adopt it again.
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *deviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state deviceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.ID.ValueString()
+	device, err := r.ClientForTailnet(plan.Tailnet).Devices().GetWithAllFields(ctx, deviceID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to fetch device",
+			"Failed to fetch device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	r.update(ctx, device, &plan, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  r.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (r *deviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state deviceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.DeleteOnDestroy.ValueBool() {
+		return
+	}
+
+	deviceID := state.ID.ValueString()
+	err := r.ClientForTailnet(state.Tailnet).Devices().Delete(ctx, deviceID)
+	if err != nil && !tailscale.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Failed to delete device",
+			"Failed to delete device with ID "+deviceID+": "+err.Error(),
+		)
+	}
+}
+
+// adopt finds the device identified by the plan's device_id or hostname,
+// waiting for it to register for up to wait_for.
+func (r *deviceResource) adopt(ctx context.Context, plan *deviceResourceModel, diags *diag.Diagnostics) *tailscale.Device {
+	var deadline time.Duration
+	if !plan.WaitFor.IsNull() {
+		parsed, err := time.ParseDuration(plan.WaitFor.ValueString())
+		if err != nil {
+			diags.AddError("Failed to parse wait_for", err.Error())
+			return nil
+		}
+		deadline = parsed
+	}
+
+	devices := r.ClientForTailnet(plan.Tailnet).Devices()
+	var found []tailscale.Device
+	poll := func(ctx context.Context) error {
+		if !plan.DeviceID.IsNull() && !plan.DeviceID.IsUnknown() {
+			device, err := devices.GetWithAllFields(ctx, plan.DeviceID.ValueString())
+			if err != nil {
+				return err
+			}
+			found = []tailscale.Device{*device}
+			return nil
+		}
+
+		var err error
+		found, err = devices.List(ctx, tailscale.WithFilter("hostname", []string{plan.Hostname.ValueString()}), tailscale.WithFields(tailscale.IncludeFieldsAll))
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return fmt.Errorf("could not find device with hostname=%q", plan.Hostname.ValueString())
+		}
+		return nil
+	}
+
+	if err := retryWithDeadline(ctx, poll, deadline, 1*time.Second); err != nil {
+		diags.AddError("Failed to fetch device", err.Error())
+		return nil
+	}
+	if len(found) > 1 {
+		var ids []string
+		for _, device := range found {
+			ids = append(ids, device.NodeID)
+		}
+		diags.AddAttributeError(
+			path.Root("hostname"),
+			"Multiple devices found",
+			fmt.Sprintf("%d devices have the hostname %q: %s. Use device_id to choose one of them.", len(found), plan.Hostname.ValueString(), strings.Join(ids, ", ")),
+		)
+		return nil
+	}
+
+	plan.ID = types.StringValue(found[0].NodeID)
+	return &found[0]
+}
+
+// update applies the settings in plan that differ from the device. Posture
+// attributes that are in state but no longer in plan are deleted.
+func (r *deviceResource) update(ctx context.Context, device *tailscale.Device, plan, state *deviceResourceModel, diags *diag.Diagnostics) {
+	devices := r.ClientForTailnet(plan.Tailnet).Devices()
+	deviceID := device.NodeID
+	fail := func(what string, err error) {
+		diags.AddError(
+			"Failed to update device "+what,
+			"Failed to update "+what+" for device with ID "+deviceID+": "+err.Error(),
+		)
+	}
+
+	if !plan.Authorized.IsNull() && !plan.Authorized.IsUnknown() && plan.Authorized.ValueBool() != device.Authorized {
+		if err := devices.SetAuthorized(ctx, deviceID, plan.Authorized.ValueBool()); err != nil {
+			fail("authorization", err)
+			return
+		}
+	}
+
+	if !plan.KeyExpiryDisabled.IsNull() && !plan.KeyExpiryDisabled.IsUnknown() && plan.KeyExpiryDisabled.ValueBool() != device.KeyExpiryDisabled {
+		key := tailscale.DeviceKey{KeyExpiryDisabled: plan.KeyExpiryDisabled.ValueBool()}
+		if err := devices.SetKey(ctx, deviceID, key); err != nil {
+			fail("key", err)
+			return
+		}
+	}
+
+	if !plan.Name.IsNull() && !plan.Name.IsUnknown() && plan.Name.ValueString() != deviceMachineName(device) {
+		if err := devices.SetName(ctx, deviceID, plan.Name.ValueString()); err != nil {
+			fail("name", err)
+			return
+		}
+	}
+
+	if !plan.Tags.IsNull() && !plan.Tags.IsUnknown() {
+		var tags []string
+		diags.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
+		if !sameStrings(tags, device.Tags) {
+			if err := devices.SetTags(ctx, deviceID, tags); err != nil {
+				fail("tags", err)
+				return
+			}
+		}
+	}
+
+	if !plan.Routes.IsNull() && !plan.Routes.IsUnknown() {
+		routes := []string{}
+		diags.Append(plan.Routes.ElementsAs(ctx, &routes, false)...)
+		if !sameStrings(routes, device.EnabledRoutes) {
+			if err := devices.SetSubnetRoutes(ctx, deviceID, routes); err != nil {
+				fail("subnet routes", err)
+				return
+			}
+		}
+	}
+
+	var planned, managed map[string]string
+	diags.Append(plan.PostureAttributes.ElementsAs(ctx, &planned, false)...)
+	if state != nil {
+		diags.Append(state.PostureAttributes.ElementsAs(ctx, &managed, false)...)
+	}
+	if diags.HasError() || (len(planned) == 0 && len(managed) == 0) {
+		return
+	}
+
+	current, err := devices.GetPostureAttributes(ctx, deviceID)
+	if err != nil {
+		diags.AddError(
+			"Failed to fetch device posture attributes",
+			"Failed to fetch posture attributes for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+	for _, key := range slices.Sorted(maps.Keys(planned)) {
+		value, ok := current.Attributes[key]
+		if ok && samePostureAttributeValue(value, planned[key]) {
+			continue
+		}
+		req := tailscale.DevicePostureAttributeRequest{Value: parsePostureAttributeValue(planned[key])}
+		if err := devices.SetPostureAttribute(ctx, deviceID, key, req); err != nil {
+			fail("posture attribute "+key, err)
+			return
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(managed)) {
+		if _, ok := planned[key]; ok {
+			continue
+		}
+		if err := devices.DeletePostureAttribute(ctx, deviceID, key); err != nil && !tailscale.IsNotFound(err) {
+			fail("posture attribute "+key, err)
+			return
+		}
+	}
+}
+
+// read refreshes the model from the device. It returns false if the device no
+// longer exists.
+func (r *deviceResource) read(ctx context.Context, m *deviceResourceModel, diags *diag.Diagnostics) bool {
+	devices := r.ClientForTailnet(m.Tailnet).Devices()
+	deviceID := m.ID.ValueString()
+
+	device, err := devices.GetWithAllFields(ctx, deviceID)
+	if err != nil {
+		if tailscale.IsNotFound(err) {
+			return false
+		}
+		diags.AddError(
+			"Failed to fetch device",
+			"Failed to fetch device with ID "+deviceID+": "+err.Error(),
+		)
+		return true
+	}
+
+	// The device is identified by whichever ID or hostname it was adopted by,
+	// so that the device isn't replaced if, for example, its hostname changes.
+	m.ID = types.StringValue(device.NodeID)
+	if m.DeviceID.IsNull() || m.DeviceID.IsUnknown() || (m.DeviceID.ValueString() != device.ID && m.DeviceID.ValueString() != device.NodeID) {
+		m.DeviceID = types.StringValue(device.NodeID)
+	}
+	if m.Hostname.IsNull() || m.Hostname.IsUnknown() {
+		m.Hostname = types.StringValue(device.Hostname)
+	}
+
+	m.Name = types.StringValue(deviceMachineName(device))
+	m.Authorized = types.BoolValue(device.Authorized)
+	m.KeyExpiryDisabled = types.BoolValue(device.KeyExpiryDisabled)
+	m.Tags = SetOfStringValue(ctx, nonNilStrings(device.Tags), diags)
+	m.Routes = SetOfStringValue(ctx, nonNilStrings(device.EnabledRoutes), diags)
+	m.Addresses = ListOfStringValue(ctx, nonNilStrings(device.Addresses), diags)
+	if m.DeleteOnDestroy.IsNull() {
+		m.DeleteOnDestroy = types.BoolValue(false)
+	}
+
+	// Only the posture attributes that are managed by this resource are read.
+	if m.PostureAttributes.IsNull() || m.PostureAttributes.IsUnknown() {
+		return true
+	}
+	posture, err := devices.GetPostureAttributes(ctx, device.NodeID)
+	if err != nil {
+		diags.AddError(
+			"Failed to fetch device posture attributes",
+			"Failed to fetch posture attributes for device with ID "+deviceID+": "+err.Error(),
+		)
+		return true
+	}
+	var previous map[string]string
+	diags.Append(m.PostureAttributes.ElementsAs(ctx, &previous, false)...)
+	attributes := make(map[string]string)
+	for key := range previous {
+		value, ok := posture.Attributes[key]
+		switch {
+		case !ok:
+		case samePostureAttributeValue(value, previous[key]):
+			// Keep the configured form of values such as 1.0.
+			attributes[key] = previous[key]
+		default:
+			attributes[key] = formatPostureAttributeValue(value)
+		}
+	}
+	m.PostureAttributes, _ = types.MapValueFrom(ctx, types.StringType, attributes)
+	return true
+}
+
+// deviceMachineName returns the machine name of a device, which is the first
+// label of its MagicDNS name.
+func deviceMachineName(device *tailscale.Device) string {
+	name, _, _ := strings.Cut(device.Name, ".")
+	return name
+}
+
+// parsePostureAttributeValue returns the value of a posture attribute set in
+// the configuration, which is a number or boolean if it looks like one.
+func parsePostureAttributeValue(s string) any {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil && json.Valid([]byte(s)) {
+		return n
+	}
+	return s
+}
+
+// formatPostureAttributeValue formats the value of a posture attribute as
+// returned by the API in the form used by the configuration.
+func formatPostureAttributeValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// samePostureAttributeValue reports whether a posture attribute value
+// returned by the API is the same as a value set in the configuration.
+func samePostureAttributeValue(v any, s string) bool {
+	return v == parsePostureAttributeValue(s)
+}
+
+// sameStrings reports whether a and b contain the same strings, in any order.
+func sameStrings(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+// nonNilStrings returns s, or an empty slice if s is nil, so that it is
+// converted to an empty rather than null value.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
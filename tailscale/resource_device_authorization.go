@@ -18,6 +18,7 @@ var (
 	_ resource.Resource                = &deviceAuthorizationResource{}
 	_ resource.ResourceWithConfigure   = &deviceAuthorizationResource{}
 	_ resource.ResourceWithImportState = &deviceAuthorizationResource{}
+	_ resource.ResourceWithIdentity    = &deviceAuthorizationResource{}
 	_ resource.ResourceWithModifyPlan  = &deviceAuthorizationResource{}
 )
 
@@ -25,6 +26,7 @@ type deviceAuthorizationResourceModel struct {
 	ID         types.String `tfsdk:"id"`
 	DeviceID   types.String `tfsdk:"device_id"`
 	Authorized types.Bool   `tfsdk:"authorized"`
+	Tailnet    types.String `tfsdk:"tailnet"`
 }
 
 // NewDeviceAuthorizationResource returns a new device authorization resource.
@@ -34,7 +36,6 @@ func NewDeviceAuthorizationResource() resource.Resource {
 
 type deviceAuthorizationResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 func (d deviceAuthorizationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,6 +46,7 @@ func (d deviceAuthorizationResource) Schema(_ context.Context, _ resource.Schema
 	resp.Schema = schema.Schema{
 		Description: "The device_authorization resource is used to approve new devices before they can join the tailnet. See https://tailscale.com/kb/1099/device-authorization/ for more details.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -65,6 +67,15 @@ func (d deviceAuthorizationResource) Schema(_ context.Context, _ resource.Schema
 	}
 }
 
+func (d deviceAuthorizationResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to set as authorized.")
+}
+
+// ImportState imports the resource by device ID or by identity.
+func (d deviceAuthorizationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, d.providerData, "device_id", req, resp)
+}
+
 func (d deviceAuthorizationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state deviceAuthorizationResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -73,9 +84,14 @@ func (d deviceAuthorizationResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
 	deviceID := state.ID.ValueString()
 
-	device, err := d.Client.Devices().Get(ctx, deviceID)
+	device, err := d.ClientForTailnet(state.Tailnet).Devices().Get(ctx, deviceID)
 	if err != nil {
 		// If the device is not found, remove from the state so we can create it again.
 		if tailscale.IsNotFound(err) {
@@ -114,7 +130,7 @@ func (d deviceAuthorizationResource) Create(ctx context.Context, req resource.Cr
 	deviceID := plan.DeviceID.ValueString()
 	authorized := plan.Authorized.ValueBool()
 
-	if err := d.Client.Devices().SetAuthorized(ctx, deviceID, authorized); err != nil {
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetAuthorized(ctx, deviceID, authorized); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device authorization",
 			"Failed to update authorization for device with ID "+deviceID+": "+err.Error(),
@@ -125,6 +141,10 @@ func (d deviceAuthorizationResource) Create(ctx context.Context, req resource.Cr
 	plan.ID = types.StringValue(deviceID)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
 }
 
 func (d deviceAuthorizationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -139,7 +159,7 @@ func (d deviceAuthorizationResource) Update(ctx context.Context, req resource.Up
 
 	authorized := plan.Authorized.ValueBool()
 
-	if err := d.Client.Devices().SetAuthorized(ctx, deviceID, authorized); err != nil {
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetAuthorized(ctx, deviceID, authorized); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device authorization",
 			"Failed to update authorization for device with ID "+deviceID+": "+err.Error(),
@@ -149,6 +169,10 @@ func (d deviceAuthorizationResource) Update(ctx context.Context, req resource.Up
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
 }
 
 func (d deviceAuthorizationResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
@@ -0,0 +1,192 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+var (
+	_ resource.Resource                = &deviceIPv4AddressResource{}
+	_ resource.ResourceWithConfigure   = &deviceIPv4AddressResource{}
+	_ resource.ResourceWithImportState = &deviceIPv4AddressResource{}
+	_ resource.ResourceWithIdentity    = &deviceIPv4AddressResource{}
+)
+
+type deviceIPv4AddressResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	DeviceID    types.String `tfsdk:"device_id"`
+	IPv4Address types.String `tfsdk:"ipv4_address"`
+	Tailnet     types.String `tfsdk:"tailnet"`
+}
+
+// NewDeviceIPv4AddressResource returns a new device IPv4 address resource.
+func NewDeviceIPv4AddressResource() resource.Resource {
+	return &deviceIPv4AddressResource{}
+}
+
+type deviceIPv4AddressResource struct {
+	ResourceBase
+}
+
+func (d deviceIPv4AddressResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_ipv4_address"
+}
+
+func (d deviceIPv4AddressResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The device_ipv4_address resource allows you to set the Tailscale IPv4 address of a device. Destroying the resource leaves the device's address unchanged. See https://tailscale.com/kb/1304/ip-pool for more information.",
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The device to set the IPv4 address of",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipv4_address": schema.StringAttribute{
+				Required:    true,
+				Description: "The IPv4 address of the device, which must be in the CGNAT range `100.64.0.0/10` and not in use by another device. The device's IPv6 address is assigned by Tailscale and can't be changed.",
+				Validators: []validator.String{
+					tailscaleIPv4Validator{},
+				},
+			},
+		},
+	}
+}
+
+func (d deviceIPv4AddressResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to set the IPv4 address of.")
+}
+
+// ImportState imports the resource by device ID or by identity.
+func (d deviceIPv4AddressResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, d.providerData, "device_id", req, resp)
+}
+
+func (d deviceIPv4AddressResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceIPv4AddressResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetIPv4Address(ctx, deviceID, plan.IPv4Address.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to set device IPv4 address",
+			"Failed to set IPv4 address for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (d deviceIPv4AddressResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceIPv4AddressResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
+	deviceID := state.ID.ValueString()
+
+	device, err := d.ClientForTailnet(state.Tailnet).Devices().Get(ctx, deviceID)
+	if err != nil {
+		// If the device is not found, remove from the state so we can create it again.
+		if tailscale.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Failed to fetch device IPv4 address",
+			"Failed to fetch IPv4 address for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	// If the device lookup succeeds and the state ID is not the same as the legacy ID, we can assume the ID is the node ID.
+	canonicalDeviceID := device.ID
+	if device.ID != deviceID {
+		canonicalDeviceID = device.NodeID
+	}
+	state.DeviceID = types.StringValue(canonicalDeviceID)
+
+	// Keep the address as configured if it's the same address, written
+	// differently.
+	addr := deviceIPv4Address(device)
+	switch configured, err := netip.ParseAddr(state.IPv4Address.ValueString()); {
+	case !addr.IsValid():
+		state.IPv4Address = types.StringNull()
+	case err != nil || configured != addr:
+		state.IPv4Address = types.StringValue(addr.String())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d deviceIPv4AddressResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan deviceIPv4AddressResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetIPv4Address(ctx, deviceID, plan.IPv4Address.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to set device IPv4 address",
+			"Failed to set IPv4 address for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (d deviceIPv4AddressResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// The API can't return a device to an automatically assigned address, so
+	// the device keeps its address, which is what other tailnets refer to it by.
+}
+
+// deviceIPv4Address returns the Tailscale IPv4 address of a device, or the
+// zero address if it doesn't have one.
+func deviceIPv4Address(device *tailscale.Device) netip.Addr {
+	for _, a := range device.Addresses {
+		if addr, err := netip.ParseAddr(a); err == nil && addr.Is4() {
+			return addr
+		}
+	}
+	return netip.Addr{}
+}
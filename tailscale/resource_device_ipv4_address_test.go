@@ -0,0 +1,84 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"tailscale.com/client/tailscale/v2"
+)
+
+func TestProvider_TailscaleDeviceIPv4AddressLifecycle(t *testing.T) {
+	const resourceName = "tailscale_device_ipv4_address.db"
+
+	factories, server := testFakeControlProviderFactories(t)
+	device := server.AddDevice(tailscale.Device{Hostname: "db"})
+	other := server.AddDevice(tailscale.Device{Hostname: "web"})
+
+	checkAddress := func(expected string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, _ := server.Device(device.NodeID)
+			if d.Addresses[0] != expected {
+				return fmt.Errorf("expected device address %s, got %v", expected, d.Addresses)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		// The address is left unchanged when the resource is destroyed.
+		CheckDestroy: checkAddress("100.100.1.2"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_device_ipv4_address" "db" {
+						device_id    = "` + device.NodeID + `"
+						ipv4_address = "192.168.0.1"
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`100\.64\.0\.0/10`),
+			},
+			{
+				Config: `
+					resource "tailscale_device_ipv4_address" "db" {
+						device_id    = "` + device.NodeID + `"
+						ipv4_address = "100.100.1.1"
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", device.NodeID),
+					checkAddress("100.100.1.1"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device_ipv4_address" "db" {
+						device_id    = "` + device.NodeID + `"
+						ipv4_address = "100.100.1.2"
+					}`,
+				Check: checkAddress("100.100.1.2"),
+			},
+			{
+				// Addresses in use by another device are rejected by the API.
+				Config: `
+					resource "tailscale_device_ipv4_address" "db" {
+						device_id    = "` + device.NodeID + `"
+						ipv4_address = "` + other.Addresses[0] + `"
+					}`,
+				ExpectError: regexp.MustCompile(`already in use`),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     device.NodeID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
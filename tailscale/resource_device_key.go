@@ -19,12 +19,14 @@ var (
 	_ resource.Resource                = &deviceKeyResource{}
 	_ resource.ResourceWithConfigure   = &deviceKeyResource{}
 	_ resource.ResourceWithImportState = &deviceKeyResource{}
+	_ resource.ResourceWithIdentity    = &deviceKeyResource{}
 )
 
 type deviceKeyResourceModel struct {
 	ID                types.String `tfsdk:"id"`
 	DeviceID          types.String `tfsdk:"device_id"`
 	KeyExpiryDisabled types.Bool   `tfsdk:"key_expiry_disabled"`
+	Tailnet           types.String `tfsdk:"tailnet"`
 }
 
 // NewDeviceKeyResource returns a new device key resource.
@@ -34,7 +36,6 @@ func NewDeviceKeyResource() resource.Resource {
 
 type deviceKeyResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 func (d deviceKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,6 +46,7 @@ func (d deviceKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 	resp.Schema = schema.Schema{
 		Description: "The device_key resource allows you to update the properties of a device's key",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -80,7 +82,7 @@ func (d deviceKeyResource) Create(ctx context.Context, req resource.CreateReques
 		KeyExpiryDisabled: keyExpiryDisabled,
 	}
 
-	if err := d.Client.Devices().SetKey(ctx, deviceID, key); err != nil {
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetKey(ctx, deviceID, key); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device key",
 			"Failed to update key for device with ID "+deviceID+": "+err.Error(),
@@ -91,6 +93,10 @@ func (d deviceKeyResource) Create(ctx context.Context, req resource.CreateReques
 	plan.ID = types.StringValue(deviceID)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
 }
 
 func (d deviceKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -104,7 +110,7 @@ func (d deviceKeyResource) Delete(ctx context.Context, req resource.DeleteReques
 	deviceID := state.DeviceID.ValueString()
 	key := tailscale.DeviceKey{}
 
-	if err := d.Client.Devices().SetKey(ctx, deviceID, key); err != nil {
+	if err := d.ClientForTailnet(state.Tailnet).Devices().SetKey(ctx, deviceID, key); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device key",
 			"Failed to update key for device with ID "+deviceID+": "+err.Error(),
@@ -113,6 +119,15 @@ func (d deviceKeyResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 }
 
+func (d deviceKeyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to update the key properties of.")
+}
+
+// ImportState imports the resource by device ID or by identity.
+func (d deviceKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, d.providerData, "device_id", req, resp)
+}
+
 func (d deviceKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state deviceKeyResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -121,9 +136,14 @@ func (d deviceKeyResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
 	deviceID := state.ID.ValueString()
 
-	device, err := d.Client.Devices().Get(ctx, deviceID)
+	device, err := d.ClientForTailnet(state.Tailnet).Devices().Get(ctx, deviceID)
 	if err != nil {
 		// If the device is not found, remove from the state so we can create it again.
 		if tailscale.IsNotFound(err) {
@@ -166,7 +186,7 @@ func (d deviceKeyResource) Update(ctx context.Context, req resource.UpdateReques
 		KeyExpiryDisabled: keyExpiryDisabled,
 	}
 
-	if err := d.Client.Devices().SetKey(ctx, deviceID, key); err != nil {
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetKey(ctx, deviceID, key); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device key",
 			"Failed to update key for device with ID "+deviceID+": "+err.Error(),
@@ -177,4 +197,8 @@ func (d deviceKeyResource) Update(ctx context.Context, req resource.UpdateReques
 	plan.ID = types.StringValue(deviceID)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
 }
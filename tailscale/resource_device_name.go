@@ -0,0 +1,186 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+var (
+	_ resource.Resource                = &deviceNameResource{}
+	_ resource.ResourceWithConfigure   = &deviceNameResource{}
+	_ resource.ResourceWithImportState = &deviceNameResource{}
+	_ resource.ResourceWithIdentity    = &deviceNameResource{}
+)
+
+type deviceNameResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	DeviceID types.String `tfsdk:"device_id"`
+	Name     types.String `tfsdk:"name"`
+	Tailnet  types.String `tfsdk:"tailnet"`
+}
+
+// NewDeviceNameResource returns a new device name resource.
+func NewDeviceNameResource() resource.Resource {
+	return &deviceNameResource{}
+}
+
+type deviceNameResource struct {
+	ResourceBase
+}
+
+func (d deviceNameResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_name"
+}
+
+func (d deviceNameResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "The device_name resource allows you to set the machine name of a device, which is the first label of its MagicDNS name. Destroying the resource resets the machine name to one generated from the device's hostname. See https://tailscale.com/kb/1098/machine-names for more information.",
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The device to set the machine name of",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The machine name of the device, such as `db-1` for the MagicDNS name `db-1.example.ts.net`. Machine names must be unique within the tailnet.",
+				Validators: []validator.String{
+					dnsLabelValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (d deviceNameResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to set the machine name of.")
+}
+
+// ImportState imports the resource by device ID or by identity.
+func (d deviceNameResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, d.providerData, "device_id", req, resp)
+}
+
+func (d deviceNameResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceNameResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetName(ctx, deviceID, plan.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to set device name",
+			"Failed to set name for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (d deviceNameResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceNameResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
+	deviceID := state.ID.ValueString()
+
+	device, err := d.ClientForTailnet(state.Tailnet).Devices().Get(ctx, deviceID)
+	if err != nil {
+		// If the device is not found, remove from the state so we can create it again.
+		if tailscale.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Failed to fetch device name",
+			"Failed to fetch name for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	// If the device lookup succeeds and the state ID is not the same as the legacy ID, we can assume the ID is the node ID.
+	canonicalDeviceID := device.ID
+	if device.ID != deviceID {
+		canonicalDeviceID = device.NodeID
+	}
+
+	state.DeviceID = types.StringValue(canonicalDeviceID)
+	state.Name = types.StringValue(deviceMachineName(device))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d deviceNameResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan deviceNameResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetName(ctx, deviceID, plan.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to set device name",
+			"Failed to set name for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (d deviceNameResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state deviceNameResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An empty name resets the machine name to one generated from the hostname.
+	deviceID := state.DeviceID.ValueString()
+	err := d.ClientForTailnet(state.Tailnet).Devices().SetName(ctx, deviceID, "")
+	if err != nil && !tailscale.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Failed to reset device name",
+			"Failed to reset name for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
+)
+
+func TestProvider_TailscaleDeviceNameLifecycle(t *testing.T) {
+	const resourceName = "tailscale_device_name.db"
+
+	factories, server := testFakeControlProviderFactories(t)
+	device := server.AddDevice(tailscale.Device{Hostname: "ip-10-0-0-1"})
+
+	checkName := func(expected string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, _ := server.Device(device.NodeID)
+			if d.Name != expected+"."+fakecontrol.DNSSuffix {
+				return fmt.Errorf("expected device name %s, got %s", expected, d.Name)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy:             checkName("ip-10-0-0-1"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_device_name" "db" {
+						device_id = "` + device.NodeID + `"
+						name      = "db-1"
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", device.NodeID),
+					checkName("db-1"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device_name" "db" {
+						device_id = "` + device.NodeID + `"
+						name      = "db-2"
+					}`,
+				Check: checkName("db-2"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     device.NodeID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,372 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+const resourceDevicePostureAttributesDescription = `The device_posture_attributes resource manages custom posture attributes of a device, which can be referred to in the postures section of the policy file. See https://tailscale.com/kb/1288/device-posture for more information.
+
+Only the attributes configured in this resource are managed: other attributes of the device, such as those set by posture integrations, are left unchanged, and destroying the resource only deletes its own attributes. Once an attribute expires it is no longer returned by Tailscale, so Terraform will plan to set it again.`
+
+var (
+	_ resource.Resource                   = &devicePostureAttributesResource{}
+	_ resource.ResourceWithConfigure      = &devicePostureAttributesResource{}
+	_ resource.ResourceWithImportState    = &devicePostureAttributesResource{}
+	_ resource.ResourceWithIdentity       = &devicePostureAttributesResource{}
+	_ resource.ResourceWithValidateConfig = &devicePostureAttributesResource{}
+)
+
+type devicePostureAttributesResourceModel struct {
+	ID         types.String                  `tfsdk:"id"`
+	DeviceID   types.String                  `tfsdk:"device_id"`
+	Attributes []devicePostureAttributeModel `tfsdk:"attribute"`
+	Tailnet    types.String                  `tfsdk:"tailnet"`
+}
+
+type devicePostureAttributeModel struct {
+	Key     types.String `tfsdk:"key"`
+	Value   types.String `tfsdk:"value"`
+	Expiry  types.String `tfsdk:"expiry"`
+	Comment types.String `tfsdk:"comment"`
+}
+
+// NewDevicePostureAttributesResource returns a new device posture attributes
+// resource.
+func NewDevicePostureAttributesResource() resource.Resource {
+	return &devicePostureAttributesResource{}
+}
+
+type devicePostureAttributesResource struct {
+	ResourceBase
+}
+
+func (d devicePostureAttributesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_posture_attributes"
+}
+
+func (d devicePostureAttributesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: resourceDevicePostureAttributesDescription,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The device to set posture attributes for",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"attribute": schema.SetNestedBlock{
+				Description: "A custom posture attribute of the device.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Required:    true,
+							Description: "The name of the attribute, which must start with `custom:`, such as `custom:tier`.",
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^custom:`), "must start with custom:"),
+							},
+						},
+						"value": schema.StringAttribute{
+							Required:    true,
+							Description: "The value of the attribute. Values that are numbers or `true` or `false` are set as numbers and booleans.",
+						},
+						"expiry": schema.StringAttribute{
+							Optional:    true,
+							Description: "The time at which the attribute expires and is removed from the device, as an RFC3339 timestamp. Defaults to never.",
+							Validators: []validator.String{
+								rfc3339Validator{},
+							},
+						},
+						"comment": schema.StringAttribute{
+							Optional:    true,
+							Description: "A comment describing why the attribute was set, which is recorded in the tailnet's configuration audit log.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d devicePostureAttributesResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to set posture attributes for.")
+}
+
+// devicePostureAttributesImportKey is the private state key that marks a
+// resource as being imported, until it is first read.
+const devicePostureAttributesImportKey = "importing"
+
+// ImportState imports the resource by device ID or by identity. All custom
+// posture attributes of the device are imported.
+func (d devicePostureAttributesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, d.providerData, "device_id", req, resp)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, devicePostureAttributesImportKey, []byte("true"))...)
+}
+
+// ValidateConfig checks that each attribute is only configured once.
+func (d devicePostureAttributesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config devicePostureAttributesResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, attr := range config.Attributes {
+		if attr.Key.IsUnknown() || attr.Key.IsNull() {
+			continue
+		}
+		key := attr.Key.ValueString()
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("attribute"),
+				"Duplicate posture attribute",
+				fmt.Sprintf("The posture attribute %q is configured more than once.", key),
+			)
+		}
+		seen[key] = true
+	}
+}
+
+func (d devicePostureAttributesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan devicePostureAttributesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.setAttributes(ctx, &plan, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(plan.DeviceID.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (d devicePostureAttributesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state devicePostureAttributesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
+	deviceID := state.ID.ValueString()
+	devices := d.ClientForTailnet(state.Tailnet).Devices()
+
+	device, err := devices.Get(ctx, deviceID)
+	if err != nil {
+		// If the device is not found, remove from the state so we can create it again.
+		if tailscale.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Failed to fetch device posture attributes",
+			"Failed to fetch posture attributes for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	posture, err := devices.GetPostureAttributes(ctx, deviceID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to fetch device posture attributes",
+			"Failed to fetch posture attributes for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	// If the device lookup succeeds and the state ID is not the same as the legacy ID, we can assume the ID is the node ID.
+	canonicalDeviceID := device.ID
+	if device.ID != deviceID {
+		canonicalDeviceID = device.NodeID
+	}
+	state.DeviceID = types.StringValue(canonicalDeviceID)
+
+	// Only the attributes in state are managed by this resource, except when
+	// importing, when all custom attributes are. Attributes of the device that
+	// aren't in state otherwise belong to someone else, even if none are.
+	importing, diags := req.Private.GetKey(ctx, devicePostureAttributesImportKey)
+	resp.Diagnostics.Append(diags...)
+	managed := state.Attributes
+	if importing != nil {
+		for key := range posture.Attributes {
+			if strings.HasPrefix(key, "custom:") {
+				managed = append(managed, devicePostureAttributeModel{Key: types.StringValue(key)})
+			}
+		}
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, devicePostureAttributesImportKey, nil)...)
+	}
+
+	var attributes []devicePostureAttributeModel
+	for _, attr := range managed {
+		key := attr.Key.ValueString()
+		value, ok := posture.Attributes[key]
+		if !ok {
+			continue
+		}
+
+		if !samePostureAttributeValue(value, attr.Value.ValueString()) {
+			attr.Value = types.StringValue(formatPostureAttributeValue(value))
+		}
+
+		expiry, hasExpiry := posture.Expiries[key]
+		switch {
+		case !hasExpiry || expiry.IsZero():
+			attr.Expiry = types.StringNull()
+		case !samePostureAttributeExpiry(expiry, attr.Expiry):
+			attr.Expiry = types.StringValue(expiry.Format(time.RFC3339))
+		}
+
+		// Comments are not returned by the API, so are kept as configured.
+		attributes = append(attributes, attr)
+	}
+	state.Attributes = attributes
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d devicePostureAttributesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state devicePostureAttributesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.setAttributes(ctx, &plan, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+func (d devicePostureAttributesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state devicePostureAttributesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.DeviceID.ValueString()
+	devices := d.ClientForTailnet(state.Tailnet).Devices()
+	for _, attr := range state.Attributes {
+		key := attr.Key.ValueString()
+		if err := devices.DeletePostureAttribute(ctx, deviceID, key); err != nil && !tailscale.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Failed to delete device posture attribute",
+				"Failed to delete posture attribute "+key+" for device with ID "+deviceID+": "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+// setAttributes sets the planned attributes that differ from state, and
+// deletes the attributes that are in state but no longer planned.
+func (d devicePostureAttributesResource) setAttributes(ctx context.Context, plan, state *devicePostureAttributesResourceModel, diags *diag.Diagnostics) {
+	deviceID := plan.DeviceID.ValueString()
+	devices := d.ClientForTailnet(plan.Tailnet).Devices()
+
+	current := make(map[string]devicePostureAttributeModel)
+	if state != nil {
+		for _, attr := range state.Attributes {
+			current[attr.Key.ValueString()] = attr
+		}
+	}
+
+	planned := make(map[string]bool)
+	for _, attr := range plan.Attributes {
+		key := attr.Key.ValueString()
+		planned[key] = true
+		if old, ok := current[key]; ok && old == attr {
+			continue
+		}
+
+		req := tailscale.DevicePostureAttributeRequest{
+			Value:   parsePostureAttributeValue(attr.Value.ValueString()),
+			Comment: attr.Comment.ValueString(),
+		}
+		if !attr.Expiry.IsNull() {
+			expiry, err := time.Parse(time.RFC3339, attr.Expiry.ValueString())
+			if err != nil {
+				diags.AddAttributeError(path.Root("attribute"), "Invalid expiry", err.Error())
+				return
+			}
+			req.Expiry = tailscale.Time{Time: expiry}
+		}
+
+		if err := devices.SetPostureAttribute(ctx, deviceID, key, req); err != nil {
+			diags.AddError(
+				"Failed to set device posture attribute",
+				"Failed to set posture attribute "+key+" for device with ID "+deviceID+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	for key := range current {
+		if planned[key] {
+			continue
+		}
+		if err := devices.DeletePostureAttribute(ctx, deviceID, key); err != nil && !tailscale.IsNotFound(err) {
+			diags.AddError(
+				"Failed to delete device posture attribute",
+				"Failed to delete posture attribute "+key+" for device with ID "+deviceID+": "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+// samePostureAttributeExpiry reports whether the expiry of a posture attribute
+// returned by the API is the same time as a configured RFC3339 timestamp.
+func samePostureAttributeExpiry(expiry tailscale.Time, configured types.String) bool {
+	t, err := time.Parse(time.RFC3339, configured.ValueString())
+	return err == nil && t.Equal(expiry.Time)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"tailscale.com/client/tailscale/v2"
+)
+
+func TestProvider_TailscaleDevicePostureAttributesLifecycle(t *testing.T) {
+	const resourceName = "tailscale_device_posture_attributes.test"
+
+	factories, server := testFakeControlProviderFactories(t)
+	device := server.AddDevice(tailscale.Device{Hostname: "web"})
+
+	u, _ := url.Parse(server.URL())
+	client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+
+	checkPosture := func(key string, expected any) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			attrs, err := client.Devices().GetPostureAttributes(context.Background(), device.NodeID)
+			if err != nil {
+				return err
+			}
+			if actual, ok := attrs.Attributes[key]; ok != (expected != nil) || actual != expected {
+				return fmt.Errorf("bad posture attribute %s: %v", key, actual)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		PreCheck: func() {
+			// Attributes not managed by the resource are left unchanged.
+			err := client.Devices().SetPostureAttribute(context.Background(), device.NodeID, "custom:other", tailscale.DevicePostureAttributeRequest{Value: "x"})
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			checkPosture("custom:tier", nil),
+			checkPosture("custom:other", "x"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_device_posture_attributes" "test" {
+						device_id = "` + device.NodeID + `"
+
+						attribute {
+							key     = "custom:tier"
+							value   = "gold"
+							comment = "test"
+						}
+
+						attribute {
+							key    = "custom:score"
+							value  = "5"
+							expiry = "2099-01-01T00:00:00Z"
+						}
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", device.NodeID),
+					resource.TestCheckResourceAttr(resourceName, "attribute.#", "2"),
+					checkPosture("custom:tier", "gold"),
+					checkPosture("custom:score", float64(5)),
+				),
+			},
+			{
+				// Attributes removed from the configuration are deleted.
+				Config: `
+					resource "tailscale_device_posture_attributes" "test" {
+						device_id = "` + device.NodeID + `"
+
+						attribute {
+							key   = "custom:tier"
+							value = "silver"
+						}
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "attribute.#", "1"),
+					checkPosture("custom:tier", "silver"),
+					checkPosture("custom:score", nil),
+					checkPosture("custom:other", "x"),
+				),
+			},
+			{
+				// Managed attributes that disappear from the device are
+				// removed from state, without adopting other attributes.
+				PreConfig: func() {
+					if err := client.Devices().DeletePostureAttribute(context.Background(), device.NodeID, "custom:tier"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check:              resource.TestCheckResourceAttr(resourceName, "attribute.#", "0"),
+			},
+			{
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check:              resource.TestCheckResourceAttr(resourceName, "attribute.#", "0"),
+			},
+			{
+				Config: `
+					resource "tailscale_device_posture_attributes" "test" {
+						device_id = "` + device.NodeID + `"
+
+						attribute {
+							key   = "custom:tier"
+							value = "silver"
+						}
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "attribute.#", "1"),
+					checkPosture("custom:tier", "silver"),
+					checkPosture("custom:other", "x"),
+				),
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: device.NodeID,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					// All custom attributes are imported.
+					if n := states[0].Attributes["attribute.#"]; n != "2" {
+						return fmt.Errorf("expected 2 imported attributes, got %s", n)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestProvider_TailscaleDevicePostureAttributesDuplicateKey(t *testing.T) {
+	factories, _ := testFakeControlProviderFactories(t)
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_device_posture_attributes" "test" {
+						device_id = "nodeid"
+
+						attribute {
+							key   = "custom:tier"
+							value = "gold"
+						}
+
+						attribute {
+							key   = "custom:tier"
+							value = "silver"
+						}
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Duplicate posture attribute`),
+			},
+		},
+	})
+}
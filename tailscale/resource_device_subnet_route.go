@@ -0,0 +1,226 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+const resourceDeviceSubnetRouteDescription = `The device_subnet_route resource enables a single subnet route for a Tailscale device, leaving its other enabled routes unchanged. See https://tailscale.com/kb/1019/subnets for more information.
+
+Unlike tailscale_device_subnet_routes, which sets all of a device's enabled routes, several device_subnet_route resources can each enable a route of the same device, such as from different modules. They must not be used together with tailscale_device_subnet_routes for the same device. Destroying the resource disables only its route.
+
+Routes must be both advertised and enabled for a device to act as a subnet router. Routes must be advertised directly from the device: advertised routes cannot be managed through Terraform.`
+
+var (
+	_ resource.Resource                = &deviceSubnetRouteResource{}
+	_ resource.ResourceWithConfigure   = &deviceSubnetRouteResource{}
+	_ resource.ResourceWithImportState = &deviceSubnetRouteResource{}
+	_ resource.ResourceWithIdentity    = &deviceSubnetRouteResource{}
+)
+
+type deviceSubnetRouteResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	DeviceID types.String `tfsdk:"device_id"`
+	Route    types.String `tfsdk:"route"`
+	Tailnet  types.String `tfsdk:"tailnet"`
+}
+
+// NewDeviceSubnetRouteResource returns a new device subnet route resource.
+func NewDeviceSubnetRouteResource() resource.Resource {
+	return &deviceSubnetRouteResource{}
+}
+
+type deviceSubnetRouteResource struct {
+	ResourceBase
+}
+
+func (d deviceSubnetRouteResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_subnet_route"
+}
+
+func (d deviceSubnetRouteResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: resourceDeviceSubnetRouteDescription,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The device ID and route, separated by a colon.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The device to enable the subnet route for",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"route": schema.StringAttribute{
+				Required:    true,
+				Description: "The subnet route to enable, such as `10.0.0.0/24`",
+				Validators: []validator.String{
+					cidrValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (d deviceSubnetRouteResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = deviceMemberIdentitySchema("route", "The subnet route enabled for the device.")
+}
+
+// ImportState imports the resource by an ID of the form
+// `<device_id>:<route>`, or by identity.
+func (d deviceSubnetRouteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importDeviceMember(ctx, d.providerData, "route", req, resp)
+}
+
+func (d deviceSubnetRouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceSubnetRouteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.DeviceID.ValueString()
+
+	deviceRoutes, err := d.ClientForTailnet(state.Tailnet).Devices().SubnetRoutes(ctx, deviceID)
+	if err != nil {
+		// If the device is not found, remove from the state so we can create it again.
+		if tailscale.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Failed to fetch device subnet routes",
+			"Failed to fetch subnet routes for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	// If the route has been disabled, remove from the state so we can enable
+	// it again.
+	if !containsRoute(deviceRoutes.Enabled, state.Route.ValueString()) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceSubnetRouteIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.DeviceID,
+		Route:    state.Route,
+	})...)
+}
+
+func (d deviceSubnetRouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceSubnetRouteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	route := plan.Route.ValueString()
+	err := d.updateRoutes(ctx, plan.Tailnet, deviceID, func(routes []string) ([]string, bool) {
+		if containsRoute(routes, route) {
+			return routes, false
+		}
+		return append(routes, route), true
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to enable device subnet route",
+			"Failed to enable subnet route "+route+" for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID + ":" + route)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceSubnetRouteIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.DeviceID,
+		Route:    plan.Route,
+	})...)
+}
+
+// Update only records the plan, as changing the device or route replaces the
+// resource.
+func (d deviceSubnetRouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan deviceSubnetRouteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (d deviceSubnetRouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state deviceSubnetRouteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.DeviceID.ValueString()
+	route := state.Route.ValueString()
+	err := d.updateRoutes(ctx, state.Tailnet, deviceID, func(routes []string) ([]string, bool) {
+		if !containsRoute(routes, route) {
+			return routes, false
+		}
+		return slices.DeleteFunc(routes, func(r string) bool { return normalizeRoute(r) == normalizeRoute(route) }), true
+	})
+	if err != nil && !tailscale.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Failed to disable device subnet route",
+			"Failed to disable subnet route "+route+" for device with ID "+deviceID+": "+err.Error(),
+		)
+	}
+}
+
+// updateRoutes sets the enabled routes of the device to the result of calling
+// update with its currently enabled routes, holding the device's lock so that
+// concurrent updates of its routes aren't lost. The routes are only set if
+// update reports a change.
+func (d deviceSubnetRouteResource) updateRoutes(ctx context.Context, tailnet types.String, deviceID string, update func([]string) ([]string, bool)) error {
+	unlock, err := d.LockDevice(ctx, tailnet, deviceID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	devices := d.ClientForTailnet(tailnet).Devices()
+	deviceRoutes, err := devices.SubnetRoutes(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	routes, changed := update(slices.Clone(deviceRoutes.Enabled))
+	if !changed {
+		return nil
+	}
+	if routes == nil {
+		routes = []string{}
+	}
+	return devices.SetSubnetRoutes(ctx, deviceID, routes)
+}
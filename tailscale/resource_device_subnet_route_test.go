@@ -0,0 +1,66 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"tailscale.com/client/tailscale/v2"
+)
+
+func TestProvider_TailscaleDeviceSubnetRouteLifecycle(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+	device := server.AddDevice(tailscale.Device{Hostname: "router"})
+
+	checkRoutes := func(expected ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, _ := server.Device(device.NodeID)
+			routes := slices.Sorted(slices.Values(d.EnabledRoutes))
+			if !slices.Equal(routes, expected) {
+				return fmt.Errorf("expected enabled routes %v, got %v", expected, routes)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy:             checkRoutes(),
+		Steps: []resource.TestStep{
+			{
+				// Routes enabled concurrently for the same device are all applied.
+				Config: `
+					resource "tailscale_device_subnet_route" "test" {
+						for_each  = toset(["10.0.0.0/24", "10.1.0.0/24", "10.2.0.0/24", "10.3.0.0/24"])
+						device_id = "` + device.NodeID + `"
+						route     = each.key
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`tailscale_device_subnet_route.test["10.0.0.0/24"]`, "id", device.NodeID+":10.0.0.0/24"),
+					checkRoutes("10.0.0.0/24", "10.1.0.0/24", "10.2.0.0/24", "10.3.0.0/24"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device_subnet_route" "test" {
+						for_each  = toset(["10.1.0.0/24", "10.3.0.0/24"])
+						device_id = "` + device.NodeID + `"
+						route     = each.key
+					}`,
+				Check: checkRoutes("10.1.0.0/24", "10.3.0.0/24"),
+			},
+			{
+				ResourceName:      `tailscale_device_subnet_route.test["10.1.0.0/24"]`,
+				ImportState:       true,
+				ImportStateId:     device.NodeID + ":10.1.0.0/24",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -5,12 +5,22 @@ package tailscale
 
 import (
 	"context"
-
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"tailscale.com/client/tailscale/v2"
 )
@@ -19,19 +29,32 @@ const resourceDeviceSubnetRoutesDescription = `The device_subnet_routes resource
 
 Routes must be both advertised and enabled for a device to act as a subnet router or exit node. Routes must be advertised directly from the device: advertised routes cannot be managed through Terraform. If a device is advertising routes, they are not exposed to traffic until they are enabled. Conversely, if routes are enabled before they are advertised, they are not available for routing until the device in question is advertising them.
 
+Planned routes that the device does not advertise are reported as a warning during plan. Set ` + "`wait_for_advertised`" + ` to instead wait for the device to advertise them before enabling them, such as when the device is created in the same run.
+
 Note: all routes enabled for the device through the admin console or autoApprovers in the ACL must be explicitly added to the routes attribute of this resource to avoid configuration drift.
 `
 
+// exitNodeRoutes are the routes that a device advertises to act as an exit
+// node, which are managed together by the exit_node attribute.
+var exitNodeRoutes = []string{"0.0.0.0/0", "::/0"}
+
 var (
-	_ resource.Resource                = &deviceSubnetRoutesResource{}
-	_ resource.ResourceWithConfigure   = &deviceSubnetRoutesResource{}
-	_ resource.ResourceWithImportState = &deviceSubnetRoutesResource{}
+	_ resource.Resource                   = &deviceSubnetRoutesResource{}
+	_ resource.ResourceWithConfigure      = &deviceSubnetRoutesResource{}
+	_ resource.ResourceWithImportState    = &deviceSubnetRoutesResource{}
+	_ resource.ResourceWithIdentity       = &deviceSubnetRoutesResource{}
+	_ resource.ResourceWithModifyPlan     = &deviceSubnetRoutesResource{}
+	_ resource.ResourceWithValidateConfig = &deviceSubnetRoutesResource{}
 )
 
 type deviceSubnetRoutesModel struct {
-	ID       types.String `tfsdk:"id"`
-	DeviceID types.String `tfsdk:"device_id"`
-	Routes   types.Set    `tfsdk:"routes"`
+	ID                types.String `tfsdk:"id"`
+	DeviceID          types.String `tfsdk:"device_id"`
+	Routes            types.Set    `tfsdk:"routes"`
+	ExitNode          types.Bool   `tfsdk:"exit_node"`
+	AdvertisedRoutes  types.Set    `tfsdk:"advertised_routes"`
+	WaitForAdvertised types.String `tfsdk:"wait_for_advertised"`
+	Tailnet           types.String `tfsdk:"tailnet"`
 }
 
 func NewDeviceSubnetRoutesResource() resource.Resource {
@@ -48,8 +71,15 @@ func (d deviceSubnetRoutesResource) ImportState(ctx context.Context, req resourc
 	//
 	// TODO(mpminardi): investigate changing the ID in state to be the device_id instead
 	// in an eventual major version bump.
+	var deviceID types.String
+	if req.ID != "" {
+		deviceID = types.StringValue(importTailnetAndID(ctx, req, resp))
+	} else {
+		resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root("device_id"), &deviceID)...)
+		importIdentityTailnet(ctx, d.providerData, req, resp)
+	}
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), createUUID())...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("device_id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("device_id"), deviceID)...)
 }
 
 func (d deviceSubnetRoutesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -60,6 +90,7 @@ func (d deviceSubnetRoutesResource) Schema(_ context.Context, _ resource.SchemaR
 	resp.Schema = schema.Schema{
 		Description: resourceDeviceSubnetRoutesDescription,
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -74,14 +105,39 @@ func (d deviceSubnetRoutesResource) Schema(_ context.Context, _ resource.SchemaR
 				},
 			},
 			"routes": schema.SetAttribute{
-				Required:    true,
-				Description: "The subnet routes that are enabled to be routed by a device",
+				Optional:    true,
+				Computed:    true,
+				Description: "The subnet routes that are enabled to be routed by a device. Defaults to no routes.",
+				ElementType: types.StringType,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"exit_node": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether the device is enabled as an exit node, which enables the `0.0.0.0/0` and `::/0` routes together. When set, those routes must not be in `routes`, and if only one of them is enabled it is reported in `routes` as drift. When not set, exit node routes are managed through `routes`.",
+			},
+			"advertised_routes": schema.SetAttribute{
+				Computed:    true,
+				Description: "The subnet routes that the device advertises",
 				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"wait_for_advertised": schema.StringAttribute{
+				Optional:    true,
+				Description: "If specified, the provider will wait up to this duration, such as `5m`, for the device to advertise all enabled routes before enabling them, and fail if it does not. Retries are made every second, so this value should be greater than 1s.",
+				Validators: []validator.String{
+					retryDeadlineValidator{},
+				},
 			},
 		},
 	}
 }
 
+func (d deviceSubnetRoutesResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to set subnet routes for.")
+}
+
 func (d deviceSubnetRoutesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state deviceSubnetRoutesModel
 	diags := req.State.Get(ctx, &state)
@@ -90,9 +146,14 @@ func (d deviceSubnetRoutesResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.DeviceID,
+	})...)
+
 	deviceID := state.DeviceID.ValueString()
 
-	deviceRoutes, err := d.Client.Devices().SubnetRoutes(ctx, deviceID)
+	deviceRoutes, err := d.ClientForTailnet(state.Tailnet).Devices().SubnetRoutes(ctx, deviceID)
 
 	if err != nil {
 		// If the device is not found, remove from the state so we can create it again.
@@ -111,8 +172,25 @@ func (d deviceSubnetRoutesResource) Read(ctx context.Context, req resource.ReadR
 	if deviceRoutes.Enabled == nil {
 		deviceRoutes.Enabled = []string{}
 	}
+	if deviceRoutes.Advertised == nil {
+		deviceRoutes.Advertised = []string{}
+	}
+
+	// When exit_node is set, the exit node routes are reported by it rather
+	// than in routes if both are enabled. A lone exit node route stays in
+	// routes, so that it shows up as drift.
+	enabled := deviceRoutes.Enabled
+	if !state.ExitNode.IsNull() {
+		withoutExitNode := slices.DeleteFunc(slices.Clone(enabled), isExitNodeRoute)
+		isExitNode := len(enabled)-len(withoutExitNode) == len(exitNodeRoutes)
+		if isExitNode {
+			enabled = withoutExitNode
+		}
+		state.ExitNode = types.BoolValue(isExitNode)
+	}
 
-	state.Routes = SetOfStringValue(ctx, deviceRoutes.Enabled, &resp.Diagnostics)
+	state.Routes = SetOfStringValue(ctx, enabled, &resp.Diagnostics)
+	state.AdvertisedRoutes = SetOfStringValue(ctx, deviceRoutes.Advertised, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -130,16 +208,19 @@ func (d deviceSubnetRoutesResource) Create(ctx context.Context, req resource.Cre
 	}
 
 	deviceID := plan.DeviceID.ValueString()
-	routes := plan.Routes
+	devices := d.ClientForTailnet(plan.Tailnet).Devices()
 
-	subnetRoutes := make([]string, len(routes.Elements()))
-	diags = routes.ElementsAs(ctx, &subnetRoutes, false)
-	resp.Diagnostics.Append(diags...)
+	subnetRoutes := plannedSubnetRoutes(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.waitForAdvertised(ctx, &plan, subnetRoutes, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := d.Client.Devices().SetSubnetRoutes(ctx, deviceID, subnetRoutes); err != nil {
+	if err := devices.SetSubnetRoutes(ctx, deviceID, subnetRoutes); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device subnet routes",
 			"Failed to update subnet routes for device with ID "+deviceID+": "+err.Error(),
@@ -147,9 +228,18 @@ func (d deviceSubnetRoutesResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
+	d.readAdvertisedRoutes(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	plan.ID = types.StringValue(createUUID())
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.DeviceID,
+	})...)
 }
 
 func (d deviceSubnetRoutesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -161,16 +251,18 @@ func (d deviceSubnetRoutesResource) Update(ctx context.Context, req resource.Upd
 	}
 
 	deviceID := plan.DeviceID.ValueString()
-	routes := plan.Routes
 
-	subnetRoutes := make([]string, len(routes.Elements()))
-	diags = routes.ElementsAs(ctx, &subnetRoutes, false)
-	resp.Diagnostics.Append(diags...)
+	subnetRoutes := plannedSubnetRoutes(ctx, &plan, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := d.Client.Devices().SetSubnetRoutes(ctx, deviceID, subnetRoutes); err != nil {
+	d.waitForAdvertised(ctx, &plan, subnetRoutes, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetSubnetRoutes(ctx, deviceID, subnetRoutes); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device subnet routes",
 			"Failed to update subnet routes for device with ID "+deviceID+": "+err.Error(),
@@ -178,8 +270,17 @@ func (d deviceSubnetRoutesResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
+	d.readAdvertisedRoutes(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.DeviceID,
+	})...)
 }
 
 func (d deviceSubnetRoutesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -192,7 +293,7 @@ func (d deviceSubnetRoutesResource) Delete(ctx context.Context, req resource.Del
 
 	deviceID := state.DeviceID.ValueString()
 
-	if err := d.Client.Devices().SetSubnetRoutes(ctx, deviceID, []string{}); err != nil {
+	if err := d.ClientForTailnet(state.Tailnet).Devices().SetSubnetRoutes(ctx, deviceID, []string{}); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to delete device subnet routes",
 			"Failed to delete subnet routes for device with ID "+deviceID+": "+err.Error(),
@@ -200,3 +301,182 @@ func (d deviceSubnetRoutesResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 }
+
+// ValidateConfig checks that exit node routes are not in routes when they are
+// managed by exit_node.
+func (d deviceSubnetRoutesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config deviceSubnetRoutesModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.ExitNode.IsNull() || config.Routes.IsUnknown() {
+		return
+	}
+
+	for _, v := range config.Routes.Elements() {
+		route, ok := v.(types.String)
+		if ok && !route.IsUnknown() && isExitNodeRoute(route.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("routes"),
+				"Conflicting exit node route",
+				fmt.Sprintf("The route %s is managed by exit_node, so must not be in routes.", route.ValueString()),
+			)
+		}
+	}
+}
+
+// ModifyPlan marks advertised_routes as unknown when the routes are updated,
+// as they are read again afterwards, and warns when newly planned routes are
+// not advertised by the device, as enabling them has no effect until the
+// device advertises them.
+func (d deviceSubnetRoutesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when destroying or before the provider is configured.
+	if req.Plan.Raw.IsNull() || d.providerData == nil {
+		return
+	}
+
+	var plan deviceSubnetRoutesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var existing []string
+	if !req.State.Raw.IsNull() {
+		var state deviceSubnetRoutesModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !plan.DeviceID.Equal(state.DeviceID) || !plan.Routes.Equal(state.Routes) ||
+			!plan.ExitNode.Equal(state.ExitNode) || !plan.WaitForAdvertised.Equal(state.WaitForAdvertised) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("advertised_routes"), types.SetUnknown(types.StringType))...)
+		}
+
+		existing = plannedSubnetRoutes(ctx, &state, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() || !plan.WaitForAdvertised.IsNull() ||
+		plan.DeviceID.IsUnknown() || plan.Routes.IsUnknown() || plan.ExitNode.IsUnknown() || plan.Tailnet.IsUnknown() {
+		return
+	}
+
+	// Only routes that aren't already enabled are checked.
+	var routes []string
+	for _, route := range plannedSubnetRoutes(ctx, &plan, &resp.Diagnostics) {
+		if !containsRoute(existing, route) {
+			routes = append(routes, route)
+		}
+	}
+	if resp.Diagnostics.HasError() || len(routes) == 0 {
+		return
+	}
+
+	// The device may not have joined the tailnet yet, in which case it can't
+	// be checked.
+	deviceRoutes, err := d.ClientForTailnet(plan.Tailnet).Devices().SubnetRoutes(ctx, plan.DeviceID.ValueString())
+	if err != nil {
+		return
+	}
+	if missing := unadvertisedRoutes(routes, deviceRoutes.Advertised); len(missing) > 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("routes"),
+			"Subnet routes not advertised",
+			fmt.Sprintf("The device with ID %s does not advertise the routes %s. They will be enabled, but will not be routed until the device advertises them. "+
+				"Set wait_for_advertised to wait for the device to advertise them.", plan.DeviceID.ValueString(), strings.Join(missing, ", ")),
+		)
+	}
+}
+
+// waitForAdvertised waits for up to wait_for_advertised for the device to
+// advertise all of routes, if it is set.
+func (d deviceSubnetRoutesResource) waitForAdvertised(ctx context.Context, plan *deviceSubnetRoutesModel, routes []string, diags *diag.Diagnostics) {
+	if plan.WaitForAdvertised.IsNull() {
+		return
+	}
+	deadline, err := time.ParseDuration(plan.WaitForAdvertised.ValueString())
+	if err != nil {
+		diags.AddError("Failed to parse wait_for_advertised", err.Error())
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	devices := d.ClientForTailnet(plan.Tailnet).Devices()
+	poll := func(ctx context.Context) error {
+		deviceRoutes, err := devices.SubnetRoutes(ctx, deviceID)
+		if err != nil {
+			return err
+		}
+		if missing := unadvertisedRoutes(routes, deviceRoutes.Advertised); len(missing) > 0 {
+			return fmt.Errorf("device with ID %s does not advertise the routes %s", deviceID, strings.Join(missing, ", "))
+		}
+		return nil
+	}
+
+	if err := retryWithDeadline(ctx, poll, deadline, 1*time.Second); err != nil {
+		diags.AddError("Subnet routes not advertised", err.Error())
+	}
+}
+
+// readAdvertisedRoutes sets the advertised routes of plan to those currently
+// advertised by the device.
+func (d deviceSubnetRoutesResource) readAdvertisedRoutes(ctx context.Context, plan *deviceSubnetRoutesModel, diags *diag.Diagnostics) {
+	deviceID := plan.DeviceID.ValueString()
+	deviceRoutes, err := d.ClientForTailnet(plan.Tailnet).Devices().SubnetRoutes(ctx, deviceID)
+	if err != nil {
+		diags.AddError(
+			"Failed to fetch device subnet routes",
+			"Failed to fetch subnet routes for device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+	if deviceRoutes.Advertised == nil {
+		deviceRoutes.Advertised = []string{}
+	}
+	plan.AdvertisedRoutes = SetOfStringValue(ctx, deviceRoutes.Advertised, diags)
+}
+
+// plannedSubnetRoutes returns the routes to enable for the device, including
+// the exit node routes if exit_node is true.
+func plannedSubnetRoutes(ctx context.Context, m *deviceSubnetRoutesModel, diags *diag.Diagnostics) []string {
+	routes := make([]string, 0, len(m.Routes.Elements()))
+	diags.Append(m.Routes.ElementsAs(ctx, &routes, false)...)
+	if m.ExitNode.ValueBool() {
+		routes = append(routes, exitNodeRoutes...)
+	}
+	return routes
+}
+
+// isExitNodeRoute reports whether route is one of the exit node routes.
+func isExitNodeRoute(route string) bool {
+	prefix, err := netip.ParsePrefix(route)
+	return err == nil && prefix.Bits() == 0
+}
+
+// containsRoute reports whether routes contains route, comparing them as
+// prefixes so that differently written routes are equal.
+func containsRoute(routes []string, route string) bool {
+	return slices.ContainsFunc(routes, func(r string) bool {
+		return normalizeRoute(r) == normalizeRoute(route)
+	})
+}
+
+// unadvertisedRoutes returns the routes that are not in advertised.
+func unadvertisedRoutes(routes, advertised []string) []string {
+	var missing []string
+	for _, route := range routes {
+		if !containsRoute(advertised, route) {
+			missing = append(missing, route)
+		}
+	}
+	return missing
+}
+
+// normalizeRoute returns route with its host bits masked, or route unchanged
+// if it is not a valid prefix.
+func normalizeRoute(route string) string {
+	prefix, err := netip.ParsePrefix(route)
+	if err != nil {
+		return route
+	}
+	return prefix.Masked().String()
+}
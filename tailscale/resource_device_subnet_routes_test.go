@@ -6,17 +6,176 @@ package tailscale
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 
 	"tailscale.com/client/tailscale/v2"
 )
 
+func TestProvider_TailscaleDeviceSubnetRoutesIdentity(t *testing.T) {
+	const resourceName = "tailscale_device_subnet_routes.test_subnet_routes"
+
+	const testDeviceSubnetRoutes = `
+		resource "tailscale_device_subnet_routes" "test_subnet_routes" {
+			device_id = "n123CNTRL"
+			routes    = ["10.0.1.0/24"]
+		}`
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest: true,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_12_0),
+		},
+		PreCheck: func() {
+			testServer.ResponseCode = http.StatusOK
+			testServer.ResponseBody = tailscale.DeviceRoutes{
+				Advertised: []string{"10.0.1.0/24"},
+				Enabled:    []string{"10.0.1.0/24"},
+			}
+		},
+		ProtoV5ProviderFactories: testProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testDeviceSubnetRoutes,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity(resourceName, map[string]knownvalue.Check{
+						"tailnet":   knownvalue.StringExact("-"),
+						"device_id": knownvalue.StringExact("n123CNTRL"),
+					}),
+				},
+			},
+			{
+				ResourceName:    resourceName,
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+		},
+	})
+}
+
+func TestProvider_TailscaleDeviceSubnetRoutesAdvertised(t *testing.T) {
+	const resourceName = "tailscale_device_subnet_routes.test"
+
+	factories, server := testFakeControlProviderFactories(t)
+	device := server.AddDevice(tailscale.Device{
+		Hostname:         "router",
+		AdvertisedRoutes: []string{"10.0.0.0/24", "0.0.0.0/0", "::/0"},
+	})
+
+	u, _ := url.Parse(server.URL())
+	client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+
+	checkEnabled := func(expected ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, _ := server.Device(device.NodeID)
+			if !slices.Equal(d.EnabledRoutes, expected) {
+				return fmt.Errorf("expected enabled routes %v, got %v", expected, d.EnabledRoutes)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_device_subnet_routes" "test" {
+						device_id = "` + device.NodeID + `"
+						routes    = ["10.0.0.0/24"]
+						exit_node = true
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "routes.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "advertised_routes.#", "3"),
+					checkEnabled("10.0.0.0/24", "0.0.0.0/0", "::/0"),
+				),
+			},
+			{
+				// A lone exit node route disabled out-of-band shows up as drift.
+				PreConfig: func() {
+					if err := client.Devices().SetSubnetRoutes(context.Background(), device.NodeID, []string{"10.0.0.0/24", "0.0.0.0/0"}); err != nil {
+						t.Fatal(err)
+					}
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "exit_node", "false"),
+					resource.TestCheckResourceAttr(resourceName, "routes.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "routes.*", "0.0.0.0/0"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device_subnet_routes" "test" {
+						device_id = "` + device.NodeID + `"
+						routes    = ["10.0.0.0/24"]
+						exit_node = true
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "exit_node", "true"),
+					resource.TestCheckResourceAttr(resourceName, "routes.#", "1"),
+					checkEnabled("10.0.0.0/24", "0.0.0.0/0", "::/0"),
+				),
+			},
+			{
+				// Routes are enabled once the device advertises them.
+				PreConfig: func() {
+					time.AfterFunc(2*time.Second, func() {
+						server.AdvertiseRoutes(device.NodeID, []string{"10.0.0.0/24", "10.1.0.0/24", "0.0.0.0/0", "::/0"})
+					})
+				},
+				Config: `
+					resource "tailscale_device_subnet_routes" "test" {
+						device_id           = "` + device.NodeID + `"
+						routes              = ["10.0.0.0/24", "10.1.0.0/24"]
+						exit_node           = false
+						wait_for_advertised = "30s"
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "exit_node", "false"),
+					resource.TestCheckResourceAttr(resourceName, "advertised_routes.#", "4"),
+					checkEnabled("10.0.0.0/24", "10.1.0.0/24"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device_subnet_routes" "test" {
+						device_id           = "` + device.NodeID + `"
+						routes              = ["10.2.0.0/24"]
+						wait_for_advertised = "2s"
+					}`,
+				ExpectError: regexp.MustCompile(`does not advertise`),
+			},
+			{
+				Config: `
+					resource "tailscale_device_subnet_routes" "test" {
+						device_id = "` + device.NodeID + `"
+						routes    = ["0.0.0.0/0"]
+						exit_node = false
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Conflicting exit node route`),
+			},
+		},
+	})
+}
+
 func TestAccTailscaleDeviceSubnetRoutes(t *testing.T) {
 	const resourceName = "tailscale_device_subnet_routes.test_subnet_routes"
 
@@ -0,0 +1,236 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+)
+
+const resourceDeviceTagDescription = `The device_tag resource applies a single tag to a Tailscale device, leaving its other tags unchanged. See https://tailscale.com/kb/1068/acl-tags/ for more details.
+
+Unlike tailscale_device_tags, which sets all of a device's tags, several device_tag resources can each add a tag to the same device, such as from different modules. They must not be used together with tailscale_device_tags for the same device. Destroying the resource removes only its tag from the device.`
+
+var (
+	_ resource.Resource                = &deviceTagResource{}
+	_ resource.ResourceWithConfigure   = &deviceTagResource{}
+	_ resource.ResourceWithImportState = &deviceTagResource{}
+	_ resource.ResourceWithIdentity    = &deviceTagResource{}
+	_ resource.ResourceWithModifyPlan  = &deviceTagResource{}
+)
+
+type deviceTagResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	DeviceID types.String `tfsdk:"device_id"`
+	Tag      types.String `tfsdk:"tag"`
+	Tailnet  types.String `tfsdk:"tailnet"`
+}
+
+// NewDeviceTagResource returns a new device tag resource.
+func NewDeviceTagResource() resource.Resource {
+	return &deviceTagResource{}
+}
+
+type deviceTagResource struct {
+	ResourceBase
+}
+
+func (d deviceTagResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_tag"
+}
+
+func (d deviceTagResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: resourceDeviceTagDescription,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The device ID and tag, separated by a colon.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The device to apply the tag to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tag": schema.StringAttribute{
+				Required:    true,
+				Description: "The tag to apply to the device, such as `tag:web`",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^tag:`), "must start with tag:"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (d deviceTagResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = deviceMemberIdentitySchema("tag", "The tag applied to the device.")
+}
+
+// ImportState imports the resource by an ID of the form
+// `<device_id>:<tag>`, or by identity.
+func (d deviceTagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importDeviceMember(ctx, d.providerData, "tag", req, resp)
+}
+
+// ModifyPlan validates the planned tag against the policy file.
+func (d deviceTagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	d.ValidateTag(ctx, req, resp, path.Root("tag"))
+}
+
+func (d deviceTagResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceTagResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.DeviceID.ValueString()
+
+	device, err := d.ClientForTailnet(state.Tailnet).Devices().Get(ctx, deviceID)
+	if err != nil {
+		// If the device is not found, remove from the state so we can create it again.
+		if tailscale.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Failed to fetch device tags",
+			"Failed to fetch device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	// If the tag has been removed from the device, remove from the state so
+	// we can add it again.
+	if !slices.Contains(device.Tags, state.Tag.ValueString()) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// If the device lookup succeeds and the state ID is not the same as the legacy ID, we can assume the ID is the node ID.
+	canonicalDeviceID := device.ID
+	if device.ID != deviceID {
+		canonicalDeviceID = device.NodeID
+	}
+	state.DeviceID = types.StringValue(canonicalDeviceID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceTagIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.DeviceID,
+		Tag:      state.Tag,
+	})...)
+}
+
+func (d deviceTagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceTagResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	tag := plan.Tag.ValueString()
+	err := d.updateTags(ctx, plan.Tailnet, deviceID, func(tags []string) ([]string, bool) {
+		if slices.Contains(tags, tag) {
+			return tags, false
+		}
+		return append(tags, tag), true
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to add device tag",
+			"Failed to add tag "+tag+" to device with ID "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID + ":" + tag)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceTagIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.DeviceID,
+		Tag:      plan.Tag,
+	})...)
+}
+
+// Update only records the plan, as changing the device or tag replaces the
+// resource.
+func (d deviceTagResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan deviceTagResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (d deviceTagResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state deviceTagResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.DeviceID.ValueString()
+	tag := state.Tag.ValueString()
+	err := d.updateTags(ctx, state.Tailnet, deviceID, func(tags []string) ([]string, bool) {
+		if !slices.Contains(tags, tag) {
+			return tags, false
+		}
+		return slices.DeleteFunc(tags, func(t string) bool { return t == tag }), true
+	})
+	if err != nil && !tailscale.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Failed to remove device tag",
+			"Failed to remove tag "+tag+" from device with ID "+deviceID+": "+err.Error(),
+		)
+	}
+}
+
+// updateTags sets the tags of the device to the result of calling update with
+// its current tags, holding the device's lock so that concurrent updates of
+// its tags aren't lost. The tags are only set if update reports a change.
+func (d deviceTagResource) updateTags(ctx context.Context, tailnet types.String, deviceID string, update func([]string) ([]string, bool)) error {
+	unlock, err := d.LockDevice(ctx, tailnet, deviceID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	devices := d.ClientForTailnet(tailnet).Devices()
+	device, err := devices.Get(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	tags, changed := update(slices.Clone(device.Tags))
+	if !changed {
+		return nil
+	}
+	return devices.SetTags(ctx, deviceID, tags)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"tailscale.com/client/tailscale/v2"
+)
+
+func TestProvider_TailscaleDeviceTagLifecycle(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"tagOwners": {
+		"tag:a": ["autogroup:admin"],
+		"tag:b": ["autogroup:admin"],
+		"tag:c": ["autogroup:admin"],
+		"tag:d": ["autogroup:admin"],
+		"tag:other": ["autogroup:admin"],
+	}}`)
+	device := server.AddDevice(tailscale.Device{Hostname: "web", Tags: []string{"tag:other"}})
+
+	checkTags := func(expected ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, _ := server.Device(device.NodeID)
+			tags := slices.Sorted(slices.Values(d.Tags))
+			if !slices.Equal(tags, expected) {
+				return fmt.Errorf("expected tags %v, got %v", expected, tags)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		// Tags that aren't managed by the resources are left unchanged.
+		CheckDestroy: checkTags("tag:other"),
+		Steps: []resource.TestStep{
+			{
+				// Tags added concurrently to the same device are all applied.
+				Config: `
+					resource "tailscale_device_tag" "test" {
+						for_each  = toset(["tag:a", "tag:b", "tag:c", "tag:d"])
+						device_id = "` + device.NodeID + `"
+						tag       = each.key
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(`tailscale_device_tag.test["tag:a"]`, "id", device.NodeID+":tag:a"),
+					checkTags("tag:a", "tag:b", "tag:c", "tag:d", "tag:other"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device_tag" "test" {
+						for_each  = toset(["tag:a", "tag:c"])
+						device_id = "` + device.NodeID + `"
+						tag       = each.key
+					}`,
+				Check: checkTags("tag:a", "tag:c", "tag:other"),
+			},
+			{
+				ResourceName:      `tailscale_device_tag.test["tag:c"]`,
+				ImportState:       true,
+				ImportStateId:     device.NodeID + ":tag:c",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -6,6 +6,7 @@ package tailscale
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -18,12 +19,15 @@ var (
 	_ resource.Resource                = &deviceTagsResource{}
 	_ resource.ResourceWithConfigure   = &deviceTagsResource{}
 	_ resource.ResourceWithImportState = &deviceTagsResource{}
+	_ resource.ResourceWithIdentity    = &deviceTagsResource{}
+	_ resource.ResourceWithModifyPlan  = &deviceTagsResource{}
 )
 
 type deviceTagsResourceModel struct {
 	ID       types.String `tfsdk:"id"`
 	DeviceID types.String `tfsdk:"device_id"`
 	Tags     types.Set    `tfsdk:"tags"`
+	Tailnet  types.String `tfsdk:"tailnet"`
 }
 
 // NewDeviceTagsResource returns a new device tags resource.
@@ -33,7 +37,6 @@ func NewDeviceTagsResource() resource.Resource {
 
 type deviceTagsResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 func (d deviceTagsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,6 +47,7 @@ func (d deviceTagsResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "The device_tags resource is used to apply tags to Tailscale devices. See https://tailscale.com/kb/1068/acl-tags/ for more details.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -63,6 +67,15 @@ func (d deviceTagsResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	}
 }
 
+func (d deviceTagsResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("device_id", "The device to set tags for.")
+}
+
+// ImportState imports the resource by device ID or by identity.
+func (d deviceTagsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, d.providerData, "device_id", req, resp)
+}
+
 func (d deviceTagsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state deviceTagsResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -71,9 +84,14 @@ func (d deviceTagsResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(state.Tailnet),
+		DeviceID: state.ID,
+	})...)
+
 	deviceID := state.ID.ValueString()
 
-	device, err := d.Client.Devices().Get(ctx, deviceID)
+	device, err := d.ClientForTailnet(state.Tailnet).Devices().Get(ctx, deviceID)
 	if err != nil {
 		// If the device is not found, remove from the state so we can create it again.
 		if tailscale.IsNotFound(err) {
@@ -124,7 +142,7 @@ func (d deviceTagsResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	if err := d.Client.Devices().SetTags(ctx, deviceID, tags); err != nil {
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetTags(ctx, deviceID, tags); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device tags",
 			"Failed to update tags for device with ID "+deviceID+": "+err.Error(),
@@ -135,6 +153,10 @@ func (d deviceTagsResource) Create(ctx context.Context, req resource.CreateReque
 	plan.ID = types.StringValue(deviceID)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
 }
 
 func (d deviceTagsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -153,7 +175,7 @@ func (d deviceTagsResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	if err := d.Client.Devices().SetTags(ctx, deviceID, tags); err != nil {
+	if err := d.ClientForTailnet(plan.Tailnet).Devices().SetTags(ctx, deviceID, tags); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to update device tags",
 			"Failed to update tags for device with ID "+deviceID+": "+err.Error(),
@@ -164,6 +186,15 @@ func (d deviceTagsResource) Update(ctx context.Context, req resource.UpdateReque
 	plan.ID = types.StringValue(deviceID)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, deviceIdentityModel{
+		Tailnet:  d.IdentityTailnet(plan.Tailnet),
+		DeviceID: plan.ID,
+	})...)
+}
+
+// ModifyPlan validates the planned tags against the policy file.
+func (d deviceTagsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	d.ValidateTags(ctx, req, resp, path.Root("tags"))
 }
 
 func (d deviceTagsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -186,7 +217,7 @@ func (d deviceTagsResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	deviceID := state.DeviceID.ValueString()
 
-	if err := d.Client.Devices().SetTags(ctx, deviceID, []string{}); err != nil {
+	if err := d.ClientForTailnet(state.Tailnet).Devices().SetTags(ctx, deviceID, []string{}); err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to delete device tags",
 			"Failed to delete tags for device with ID "+deviceID+": "+err.Error(),
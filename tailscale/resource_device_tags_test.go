@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -42,6 +43,54 @@ func TestProvider_TailscaleDeviceTags(t *testing.T) {
 	})
 }
 
+func TestProvider_TailscaleDeviceTagsLifecycle(t *testing.T) {
+	const resourceName = "tailscale_device_tags.test_tags"
+
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"], "tag:prod": ["autogroup:admin"]}}`)
+	device := server.AddDevice(tailscale.Device{Hostname: "web"})
+
+	config := func(tags string) string {
+		return fmt.Sprintf(`
+			resource "tailscale_device_tags" "test_tags" {
+				device_id = %q
+				tags      = %s
+			}`, device.NodeID, tags)
+	}
+	checkTags := func(expected ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, _ := server.Device(device.NodeID)
+			slices.Sort(d.Tags)
+			if !slices.Equal(d.Tags, expected) {
+				return fmt.Errorf("bad device.tags: %v", d.Tags)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy:             checkTags(),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`["tag:web"]`),
+				Check:  checkTags("tag:web"),
+			},
+			{
+				Config: config(`["tag:prod", "tag:web"]`),
+				Check:  checkTags("tag:prod", "tag:web"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     device.NodeID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccTailscaleDeviceTags(t *testing.T) {
 	const resourceName = "tailscale_device_tags.test_tags"
 
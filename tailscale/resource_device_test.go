@@ -0,0 +1,165 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
+)
+
+func TestProvider_TailscaleDeviceLifecycle(t *testing.T) {
+	const resourceName = "tailscale_device.web"
+
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"]}}`)
+	device := server.AddDevice(tailscale.Device{Hostname: "web"})
+
+	u, _ := url.Parse(server.URL())
+	client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+
+	checkDevice := func(check func(d tailscale.Device) error) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			d, ok := server.Device(device.NodeID)
+			if !ok {
+				return fmt.Errorf("device %s not found", device.NodeID)
+			}
+			return check(d)
+		}
+	}
+	checkPosture := func(key string, expected any) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			attrs, err := client.Devices().GetPostureAttributes(context.Background(), device.NodeID)
+			if err != nil {
+				return err
+			}
+			if actual, ok := attrs.Attributes[key]; ok != (expected != nil) || actual != expected {
+				return fmt.Errorf("bad posture attribute %s: %v", key, actual)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.Device(device.NodeID); ok {
+				return fmt.Errorf("device %s was not deleted", device.NodeID)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				// Settings that aren't configured are left unchanged.
+				Config: `
+					resource "tailscale_device" "web" {
+						hostname          = "web"
+						delete_on_destroy = true
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", device.NodeID),
+					resource.TestCheckResourceAttr(resourceName, "device_id", device.NodeID),
+					resource.TestCheckResourceAttr(resourceName, "name", "web"),
+					resource.TestCheckResourceAttr(resourceName, "authorized", "false"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "addresses.#", "2"),
+				),
+			},
+			{
+				Config: `
+					resource "tailscale_device" "web" {
+						hostname            = "web"
+						name                = "web-1"
+						authorized          = true
+						key_expiry_disabled = true
+						tags                = ["tag:web"]
+						routes              = ["10.0.0.0/8"]
+						delete_on_destroy   = true
+
+						posture_attributes = {
+							"custom:tier"  = "gold"
+							"custom:score" = "5"
+						}
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					checkDevice(func(d tailscale.Device) error {
+						if !d.Authorized || !d.KeyExpiryDisabled || !slices.Equal(d.Tags, []string{"tag:web"}) ||
+							!slices.Equal(d.EnabledRoutes, []string{"10.0.0.0/8"}) || d.Name != "web-1."+fakecontrol.DNSSuffix {
+							return fmt.Errorf("device was not updated: %+v", d)
+						}
+						return nil
+					}),
+					checkPosture("custom:tier", "gold"),
+					checkPosture("custom:score", float64(5)),
+				),
+			},
+			{
+				// Posture attributes removed from the map are deleted.
+				Config: `
+					resource "tailscale_device" "web" {
+						hostname          = "web"
+						delete_on_destroy = true
+
+						posture_attributes = {
+							"custom:tier" = "silver"
+						}
+					}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "tags.0", "tag:web"),
+					checkPosture("custom:tier", "silver"),
+					checkPosture("custom:score", nil),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           device.NodeID,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"hostname", "delete_on_destroy", "posture_attributes"},
+			},
+		},
+	})
+}
+
+func TestProvider_TailscaleDeviceWaitFor(t *testing.T) {
+	factories, _ := testFakeControlProviderFactories(t)
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_device" "missing" {
+						hostname = "missing"
+						wait_for = "2s"
+					}`,
+				ExpectError: regexp.MustCompile(`could not find device`),
+			},
+		},
+	})
+}
+
+func TestPostureAttributeValue(t *testing.T) {
+	assert.Equal(t, true, parsePostureAttributeValue("true"))
+	assert.Equal(t, 1.5, parsePostureAttributeValue("1.5"))
+	assert.Equal(t, "gold", parsePostureAttributeValue("gold"))
+	assert.Equal(t, "NaN", parsePostureAttributeValue("NaN"))
+
+	assert.Equal(t, "5", formatPostureAttributeValue(float64(5)))
+	assert.Equal(t, "false", formatPostureAttributeValue(false))
+	assert.True(t, samePostureAttributeValue(float64(1), "1.0"))
+	assert.False(t, samePostureAttributeValue("1", "1.0"))
+}
@@ -26,6 +26,7 @@ var (
 	_ resource.Resource                = &dnsConfigurationResource{}
 	_ resource.ResourceWithConfigure   = &dnsConfigurationResource{}
 	_ resource.ResourceWithImportState = &dnsConfigurationResource{}
+	_ resource.ResourceWithIdentity    = &dnsConfigurationResource{}
 )
 
 // NewDNSConfigurationResource returns a new DNS configuration resource.
@@ -34,8 +35,7 @@ func NewDNSConfigurationResource() resource.Resource {
 }
 
 type dnsConfigurationResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -48,6 +48,7 @@ func (r *dnsConfigurationResource) Schema(_ context.Context, _ resource.SchemaRe
 	resp.Schema = schema.Schema{
 		Description: "The dns_configuration resource allows you to manage the complete DNS configuration for your Tailscale network. See https://tailscale.com/kb/1054/dns for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -139,6 +140,7 @@ type dnsConfigurationResourceData struct {
 	SearchPaths      types.List        `tfsdk:"search_paths"`
 	Nameservers      []nameserverModel `tfsdk:"nameservers"`
 	SplitDNS         []splitDNSModel   `tfsdk:"split_dns"`
+	Tailnet          types.String      `tfsdk:"tailnet"`
 }
 
 type nameserverModel struct {
@@ -159,7 +161,9 @@ func (r *dnsConfigurationResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	remote, err := r.Client.DNS().Configuration(ctx)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	remote, err := r.ClientForTailnet(state.Tailnet).DNS().Configuration(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch DNS configuration", err.Error())
 		return
@@ -247,6 +251,7 @@ func (r *dnsConfigurationResource) Create(ctx context.Context, req resource.Crea
 
 	plan.ID = types.StringValue(createUUID())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -263,10 +268,17 @@ func (r *dnsConfigurationResource) Update(ctx context.Context, req resource.Upda
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	if err := r.Client.DNS().SetConfiguration(ctx, tailscale.DNSConfiguration{}); err != nil {
+	var state dnsConfigurationResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.ClientForTailnet(state.Tailnet).DNS().SetConfiguration(ctx, tailscale.DNSConfiguration{}); err != nil {
 		resp.Diagnostics.AddError("Failed to delete DNS configuration", err.Error())
 	}
 }
@@ -307,7 +319,7 @@ func (r *dnsConfigurationResource) updateDNSConfiguration(ctx context.Context, d
 		configuration.SearchPaths = append(configuration.SearchPaths, path)
 	}
 
-	if err := r.Client.DNS().SetConfiguration(ctx, configuration); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).DNS().SetConfiguration(ctx, configuration); err != nil {
 		diags.AddError("Failed to set DNS configuration", err.Error())
 	}
 }
@@ -20,6 +20,7 @@ var (
 	_ resource.Resource                = &dnsNameserversResource{}
 	_ resource.ResourceWithConfigure   = &dnsNameserversResource{}
 	_ resource.ResourceWithImportState = &dnsNameserversResource{}
+	_ resource.ResourceWithIdentity    = &dnsNameserversResource{}
 )
 
 // NewDNSNameserversResource returns a new DNS preferences resources.
@@ -28,8 +29,7 @@ func NewDNSNameserversResource() resource.Resource {
 }
 
 type dnsNameserversResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -42,6 +42,7 @@ func (r *dnsNameserversResource) Schema(_ context.Context, _ resource.SchemaRequ
 	resp.Schema = schema.Schema{
 		Description: "The dns_nameservers resource allows you to configure DNS nameservers for your Tailscale network. See https://tailscale.com/kb/1054/dns for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -63,6 +64,7 @@ func (r *dnsNameserversResource) Schema(_ context.Context, _ resource.SchemaRequ
 type dnsNameserversResourceData struct {
 	ID          types.String `tfsdk:"id"`
 	Nameservers types.List   `tfsdk:"nameservers"`
+	Tailnet     types.String `tfsdk:"tailnet"`
 }
 
 func (r *dnsNameserversResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -73,7 +75,9 @@ func (r *dnsNameserversResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	servers, err := r.Client.DNS().Nameservers(ctx)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	servers, err := r.ClientForTailnet(state.Tailnet).DNS().Nameservers(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error fetching DNS name servers",
@@ -104,6 +108,7 @@ func (r *dnsNameserversResource) Create(ctx context.Context, req resource.Create
 
 	plan.ID = types.StringValue(createUUID())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsNameserversResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -120,10 +125,17 @@ func (r *dnsNameserversResource) Update(ctx context.Context, req resource.Update
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsNameserversResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	if err := r.Client.DNS().SetNameservers(ctx, []string{}); err != nil {
+	var state dnsNameserversResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.ClientForTailnet(state.Tailnet).DNS().SetNameservers(ctx, []string{}); err != nil {
 		resp.Diagnostics.AddError("Failed to delete DNS nameservers", err.Error())
 	}
 }
@@ -140,7 +152,7 @@ func (r *dnsNameserversResource) updateDNSNameservers(ctx context.Context, data
 		}
 	}
 
-	if err := r.Client.DNS().SetNameservers(ctx, nameservers); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).DNS().SetNameservers(ctx, nameservers); err != nil {
 		diags.AddError("Failed to update DNS nameservers", err.Error())
 		return
 	}
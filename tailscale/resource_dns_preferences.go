@@ -20,6 +20,7 @@ var (
 	_ resource.Resource                = &dnsPreferencesResource{}
 	_ resource.ResourceWithConfigure   = &dnsPreferencesResource{}
 	_ resource.ResourceWithImportState = &dnsPreferencesResource{}
+	_ resource.ResourceWithIdentity    = &dnsPreferencesResource{}
 )
 
 // NewDNSPreferencesResource returns a new DNS preferences resources.
@@ -28,8 +29,7 @@ func NewDNSPreferencesResource() resource.Resource {
 }
 
 type dnsPreferencesResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -42,6 +42,7 @@ func (r *dnsPreferencesResource) Schema(_ context.Context, _ resource.SchemaRequ
 	resp.Schema = schema.Schema{
 		Description: "The dns_preferences resource allows you to configure DNS preferences for your Tailscale network. See https://tailscale.com/kb/1054/dns for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -59,6 +60,7 @@ func (r *dnsPreferencesResource) Schema(_ context.Context, _ resource.SchemaRequ
 type dnsPreferencesResourceData struct {
 	ID       types.String `tfsdk:"id"`
 	MagicDNS types.Bool   `tfsdk:"magic_dns"`
+	Tailnet  types.String `tfsdk:"tailnet"`
 }
 
 func (r *dnsPreferencesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -69,7 +71,9 @@ func (r *dnsPreferencesResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	preferences, err := r.Client.DNS().Preferences(ctx)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	preferences, err := r.ClientForTailnet(state.Tailnet).DNS().Preferences(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error fetching DNS preferences",
@@ -100,6 +104,7 @@ func (r *dnsPreferencesResource) Create(ctx context.Context, req resource.Create
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsPreferencesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -120,10 +125,17 @@ func (r *dnsPreferencesResource) Update(ctx context.Context, req resource.Update
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsPreferencesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	if err := r.Client.DNS().SetPreferences(ctx, tailscale.DNSPreferences{}); err != nil {
+	var state dnsPreferencesResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.ClientForTailnet(state.Tailnet).DNS().SetPreferences(ctx, tailscale.DNSPreferences{}); err != nil {
 		resp.Diagnostics.AddError("Failed to set DNS preferences", "Failed to set DNS preferences: "+err.Error())
 	}
 }
@@ -135,7 +147,7 @@ func (r *dnsPreferencesResource) updateDNSPreferences(ctx context.Context, data
 		MagicDNS: data.MagicDNS.ValueBool(),
 	}
 
-	if err := r.Client.DNS().SetPreferences(ctx, prefs); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).DNS().SetPreferences(ctx, prefs); err != nil {
 		diags.AddError("Failed to set DNS preferences", "Failed to set DNS preferences: "+err.Error())
 	}
 }
@@ -18,6 +18,7 @@ var (
 	_ resource.Resource                = &dnsSearchPathsResource{}
 	_ resource.ResourceWithConfigure   = &dnsSearchPathsResource{}
 	_ resource.ResourceWithImportState = &dnsSearchPathsResource{}
+	_ resource.ResourceWithIdentity    = &dnsSearchPathsResource{}
 )
 
 // NewDNSPreferencesResource returns a new DNS search paths resources.
@@ -26,8 +27,7 @@ func NewDNSSearchPathsResource() resource.Resource {
 }
 
 type dnsSearchPathsResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -40,6 +40,7 @@ func (r *dnsSearchPathsResource) Schema(_ context.Context, _ resource.SchemaRequ
 	resp.Schema = schema.Schema{
 		Description: "The dns_search_paths resource allows you to configure DNS search paths for your Tailscale network. See https://tailscale.com/kb/1054/dns for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -58,6 +59,7 @@ func (r *dnsSearchPathsResource) Schema(_ context.Context, _ resource.SchemaRequ
 type dnsSearchPathsResourceData struct {
 	ID          types.String `tfsdk:"id"`
 	SearchPaths types.List   `tfsdk:"search_paths"`
+	Tailnet     types.String `tfsdk:"tailnet"`
 }
 
 func (r *dnsSearchPathsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -68,7 +70,9 @@ func (r *dnsSearchPathsResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	paths, err := r.Client.DNS().SearchPaths(ctx)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	paths, err := r.ClientForTailnet(state.Tailnet).DNS().SearchPaths(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error fetching DNS search paths",
@@ -106,6 +110,7 @@ func (r *dnsSearchPathsResource) Create(ctx context.Context, req resource.Create
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsSearchPathsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -126,10 +131,17 @@ func (r *dnsSearchPathsResource) Update(ctx context.Context, req resource.Update
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (r *dnsSearchPathsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	if err := r.Client.DNS().SetSearchPaths(ctx, []string{}); err != nil {
+	var state dnsSearchPathsResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.ClientForTailnet(state.Tailnet).DNS().SetSearchPaths(ctx, []string{}); err != nil {
 		resp.Diagnostics.AddError("Failed to delete DNS search paths", err.Error())
 	}
 }
@@ -144,7 +156,7 @@ func (r *dnsSearchPathsResource) updateDNSSearchPaths(ctx context.Context, data
 		return
 	}
 
-	if err := r.Client.DNS().SetSearchPaths(ctx, searchPaths); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).DNS().SetSearchPaths(ctx, searchPaths); err != nil {
 		diags.AddError("Failed to set DNS search paths", "Failed to set DNS search paths: "+err.Error())
 	}
 }
@@ -21,6 +21,7 @@ var (
 	_ resource.Resource                = &dnsSplitNameserversResource{}
 	_ resource.ResourceWithConfigure   = &dnsSplitNameserversResource{}
 	_ resource.ResourceWithImportState = &dnsSplitNameserversResource{}
+	_ resource.ResourceWithIdentity    = &dnsSplitNameserversResource{}
 )
 
 // NewDNSSplitNameserversResource returns a new DNS preferences resources.
@@ -42,6 +43,7 @@ func (r *dnsSplitNameserversResource) Schema(_ context.Context, _ resource.Schem
 	resp.Schema = schema.Schema{
 		Description: "The dns_split_nameservers resource allows you to configure split DNS nameservers for your Tailscale network. See https://tailscale.com/kb/1054/dns for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -64,10 +66,17 @@ func (r *dnsSplitNameserversResource) Schema(_ context.Context, _ resource.Schem
 	}
 }
 
+// IdentitySchema defines the identity of the resource, which is also used to
+// import it.
+func (r *dnsSplitNameserversResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("domain", "The domain to configure split DNS for.")
+}
+
 type dnsSplitNameserversResourceData struct {
 	ID          types.String `tfsdk:"id"`
 	Domain      types.String `tfsdk:"domain"`
 	Nameservers types.Set    `tfsdk:"nameservers"`
+	Tailnet     types.String `tfsdk:"tailnet"`
 }
 
 func (r *dnsSplitNameserversResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -78,7 +87,12 @@ func (r *dnsSplitNameserversResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	splitDNS, err := r.Client.DNS().SplitDNS(ctx)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, splitNameserversIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		Domain:  state.ID,
+	})...)
+
+	splitDNS, err := r.ClientForTailnet(state.Tailnet).DNS().SplitDNS(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error fetching split DNS config",
@@ -115,6 +129,10 @@ func (r *dnsSplitNameserversResource) Create(ctx context.Context, req resource.C
 
 	plan.ID = plan.Domain
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, splitNameserversIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		Domain:  plan.ID,
+	})...)
 }
 
 func (r *dnsSplitNameserversResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -131,6 +149,10 @@ func (r *dnsSplitNameserversResource) Update(ctx context.Context, req resource.U
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, splitNameserversIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		Domain:  plan.ID,
+	})...)
 }
 
 // updateSplitDNSConfig calls the Tailscale API to update the split DNS config based
@@ -148,7 +170,7 @@ func (r *dnsSplitNameserversResource) updateSplitDNSConfig(ctx context.Context,
 		domain: nameservers,
 	}
 
-	if _, err := r.Client.DNS().UpdateSplitDNS(ctx, updateReq); err != nil {
+	if _, err := r.ClientForTailnet(data.Tailnet).DNS().UpdateSplitDNS(ctx, updateReq); err != nil {
 		diags.AddError("Failed to update DNS split nameservers", err.Error())
 		return
 	}
@@ -164,13 +186,17 @@ func (r *dnsSplitNameserversResource) Delete(ctx context.Context, req resource.D
 	domain := state.Domain.ValueString()
 	updateReq := tailscale.SplitDNSRequest{domain: {}}
 
-	if _, err := r.Client.DNS().UpdateSplitDNS(ctx, updateReq); err != nil {
+	if _, err := r.ClientForTailnet(state.Tailnet).DNS().UpdateSplitDNS(ctx, updateReq); err != nil {
 		resp.Diagnostics.AddError("Failed to delete DNS split nameservers", err.Error())
 		return
 	}
 }
 
+// ImportState imports the resource by domain or by identity.
 func (r *dnsSplitNameserversResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), req.ID)...)
+	importStateWithIdentity(ctx, r.providerData, "domain", req, resp)
+
+	var domain types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
 }
@@ -22,6 +22,51 @@ const testSplitNameservers = `
 		nameservers = ["1.2.3.4", "4.5.6.7"]
 	}`
 
+func TestProvider_TailscaleSplitDNSNameserversLifecycle(t *testing.T) {
+	const resourceName = "tailscale_dns_split_nameservers.test_nameservers"
+
+	const testSplitNameserversUpdate = `
+		resource "tailscale_dns_split_nameservers" "test_nameservers" {
+			domain = "example.com"
+			nameservers = ["8.8.9.9"]
+		}`
+
+	factories, server := testFakeControlProviderFactories(t)
+	checkNameservers := func(expected ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			var actual []string
+			for _, r := range server.DNSConfiguration().SplitDNS["example.com"] {
+				actual = append(actual, r.Address)
+			}
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				return fmt.Errorf("unexpected nameservers (-want, +got): %s", diff)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy:             checkNameservers(),
+		Steps: []resource.TestStep{
+			{
+				Config: testSplitNameservers,
+				Check:  checkNameservers("1.2.3.4", "4.5.6.7"),
+			},
+			{
+				Config: testSplitNameserversUpdate,
+				Check:  checkNameservers("8.8.9.9"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestProvider_TailscaleSplitDNSNameservers(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		IsUnitTest: true,
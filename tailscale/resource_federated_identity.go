@@ -29,6 +29,8 @@ var (
 	_ resource.Resource                = &federatedIdentityResource{}
 	_ resource.ResourceWithConfigure   = &federatedIdentityResource{}
 	_ resource.ResourceWithImportState = &federatedIdentityResource{}
+	_ resource.ResourceWithIdentity    = &federatedIdentityResource{}
+	_ resource.ResourceWithModifyPlan  = &federatedIdentityResource{}
 )
 
 // NewFederatedIdentityResource returns a new federated identity resource.
@@ -50,6 +52,7 @@ func (r *federatedIdentityResource) Schema(_ context.Context, _ resource.SchemaR
 	resp.Schema = schema.Schema{
 		Description: "The federated_identity resource allows you to create federated identities to programmatically interact with the Tailscale API using workload identity federation.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Description: "The client ID, also known as the key id. Used with an OIDC identity token to generate access tokens.",
 				Computed:    true,
@@ -144,6 +147,12 @@ type federatedIdentityResourceModel struct {
 	CreatedAt        types.String `tfsdk:"created_at"`
 	UpdatedAt        types.String `tfsdk:"updated_at"`
 	UserID           types.String `tfsdk:"user_id"`
+	Tailnet          types.String `tfsdk:"tailnet"`
+}
+
+// IdentitySchema defines the attributes that identify a federated identity.
+func (r *federatedIdentityResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("id", "The client ID.")
 }
 
 // Create creates a new federated identity.
@@ -169,7 +178,7 @@ func (r *federatedIdentityResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	key, err := r.Client.Keys().CreateFederatedIdentity(ctx, createReq)
+	key, err := r.ClientForTailnet(data.Tailnet).Keys().CreateFederatedIdentity(ctx, createReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create federated identity", err.Error())
 		return
@@ -180,6 +189,10 @@ func (r *federatedIdentityResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(data.Tailnet),
+		ID:      data.ID,
+	})...)
 }
 
 // Read fetches the current state of the federated identity.
@@ -190,7 +203,12 @@ func (r *federatedIdentityResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	key, err := r.Client.Keys().Get(ctx, data.ID.ValueString())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(data.Tailnet),
+		ID:      data.ID,
+	})...)
+
+	key, err := r.ClientForTailnet(data.Tailnet).Keys().Get(ctx, data.ID.ValueString())
 	if err != nil {
 		if tailscale.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
@@ -230,7 +248,7 @@ func (r *federatedIdentityResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
-	key, err := r.Client.Keys().SetFederatedIdentity(ctx, data.ID.ValueString(), updateReq)
+	key, err := r.ClientForTailnet(data.Tailnet).Keys().SetFederatedIdentity(ctx, data.ID.ValueString(), updateReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update federated identity", err.Error())
 		return
@@ -241,9 +259,18 @@ func (r *federatedIdentityResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(data.Tailnet),
+		ID:      data.ID,
+	})...)
 }
 
 // Delete deletes a federated identity.
+// ModifyPlan validates the planned tags against the policy file.
+func (r *federatedIdentityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.ValidateTags(ctx, req, resp, path.Root("tags"))
+}
+
 func (r *federatedIdentityResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data federatedIdentityResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -251,15 +278,15 @@ func (r *federatedIdentityResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	err := r.Client.Keys().Delete(ctx, data.ID.ValueString())
+	err := r.ClientForTailnet(data.Tailnet).Keys().Delete(ctx, data.ID.ValueString())
 	if err != nil && !tailscale.IsNotFound(err) {
 		resp.Diagnostics.AddError("Failed to delete federated identity", err.Error())
 	}
 }
 
-// ImportState implements state passthrough for import.
+// ImportState imports the resource by client ID or by identity.
 func (r *federatedIdentityResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	importStateWithIdentity(ctx, r.providerData, "id", req, resp)
 }
 
 // populateFromKey updates the model with data from the API key response.
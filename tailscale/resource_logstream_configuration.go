@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"tailscale.com/client/tailscale/v2"
@@ -26,6 +27,7 @@ var (
 	_ resource.Resource                = &logstreamConfigurationResource{}
 	_ resource.ResourceWithConfigure   = &logstreamConfigurationResource{}
 	_ resource.ResourceWithImportState = &logstreamConfigurationResource{}
+	_ resource.ResourceWithIdentity    = &logstreamConfigurationResource{}
 )
 
 // NewLogstreamConfigurationResource returns a new logtsream configuration resource.
@@ -35,7 +37,6 @@ func NewLogstreamConfigurationResource() resource.Resource {
 
 type logstreamConfigurationResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -45,9 +46,19 @@ func (r *logstreamConfigurationResource) Metadata(_ context.Context, req resourc
 
 // Schema defines a schema describing what fields can be defined in the resource.
 func (r *logstreamConfigurationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	const (
+		tokenDescription             = "The token/password with which log streams to this endpoint should be authenticated, required unless destination_type is 's3'."
+		s3SecretAccessKeyDescription = "The S3 secret access key. Required if destination_type is 's3' and s3_authentication_type is 'accesskey'."
+		gcsCredentialsDescription    = "The encoded string of JSON that is used to authenticate for workload identity in GCS."
+	)
+	tokenWO, tokenWOVersion := writeOnlySecretAttributes("token", tokenDescription)
+	s3SecretAccessKeyWO, s3SecretAccessKeyWOVersion := writeOnlySecretAttributes("s3_secret_access_key", s3SecretAccessKeyDescription)
+	gcsCredentialsWO, gcsCredentialsWOVersion := writeOnlySecretAttributes("gcs_credentials", gcsCredentialsDescription)
+
 	resp.Schema = schema.Schema{
 		Description: "The logstream_configuration resource allows you to configure streaming configuration or network flow logs to a supported security information and event management (SIEM) system. See https://tailscale.com/kb/1255/log-streaming for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -96,13 +107,15 @@ func (r *logstreamConfigurationResource) Schema(_ context.Context, _ resource.Sc
 				Default:     stringdefault.StaticString("user"),
 			},
 			"token": schema.StringAttribute{
-				Description: "The token/password with which log streams to this endpoint should be authenticated, required unless destination_type is 's3'.",
+				Description: tokenDescription,
 				Optional:    true,
 				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"token_wo":         tokenWO,
+			"token_wo_version": tokenWOVersion,
 			"upload_period_minutes": schema.Int32Attribute{
 				Description: "An optional number of minutes to wait in between uploading new logs. If the quantity of logs does not fit within a single upload, multiple uploads will be made.",
 				Computed:    true,
@@ -157,13 +170,15 @@ func (r *logstreamConfigurationResource) Schema(_ context.Context, _ resource.Sc
 				Default:     stringdefault.StaticString(""),
 			},
 			"s3_secret_access_key": schema.StringAttribute{
-				Description: "The S3 secret access key. Required if destination_type is 's3' and s3_authentication_type is 'accesskey'.",
+				Description: s3SecretAccessKeyDescription,
 				Optional:    true,
 				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"s3_secret_access_key_wo":         s3SecretAccessKeyWO,
+			"s3_secret_access_key_wo_version": s3SecretAccessKeyWOVersion,
 			"s3_role_arn": schema.StringAttribute{
 				Description: "ARN of the AWS IAM role that Tailscale should assume when using role-based authentication. Required if destination_type is 's3' and s3_authentication_type is 'rolearn'.",
 				Computed:    true,
@@ -177,7 +192,7 @@ func (r *logstreamConfigurationResource) Schema(_ context.Context, _ resource.Sc
 				Default:     stringdefault.StaticString(""),
 			},
 			"gcs_credentials": schema.StringAttribute{
-				Description: "The encoded string of JSON that is used to authenticate for workload identity in GCS",
+				Description: gcsCredentialsDescription,
 				Computed:    true,
 				Optional:    true,
 				Default:     stringdefault.StaticString(""),
@@ -185,6 +200,8 @@ func (r *logstreamConfigurationResource) Schema(_ context.Context, _ resource.Sc
 					jsonSemanticDiffModifier{},
 				},
 			},
+			"gcs_credentials_wo":         gcsCredentialsWO,
+			"gcs_credentials_wo_version": gcsCredentialsWOVersion,
 			"gcs_bucket": schema.StringAttribute{
 				Description: "The name of the GCS bucket",
 				Computed:    true,
@@ -208,27 +225,45 @@ func (r *logstreamConfigurationResource) Schema(_ context.Context, _ resource.Sc
 	}
 }
 
+// IdentitySchema defines the identity of the resource, which is also used to
+// import it.
+func (r *logstreamConfigurationResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("log_type", "The type of logs that are streamed, either `configuration` or `network`.")
+}
+
+// ImportState imports the resource by log type or by identity.
+func (r *logstreamConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, r.providerData, "log_type", req, resp)
+}
+
 type logstreamConfigurationResourceModel struct {
-	ID                   types.String `tfsdk:"id"`
-	LogType              types.String `tfsdk:"log_type"`
-	DestinationType      types.String `tfsdk:"destination_type"`
-	URL                  types.String `tfsdk:"url"`
-	User                 types.String `tfsdk:"user"`
-	Token                types.String `tfsdk:"token"`
-	UploadPeriodMinutes  types.Int32  `tfsdk:"upload_period_minutes"`
-	CompressionFormat    types.String `tfsdk:"compression_format"`
-	S3Bucket             types.String `tfsdk:"s3_bucket"`
-	S3Region             types.String `tfsdk:"s3_region"`
-	S3KeyPrefix          types.String `tfsdk:"s3_key_prefix"`
-	S3AuthenticationType types.String `tfsdk:"s3_authentication_type"`
-	S3AccessKeyID        types.String `tfsdk:"s3_access_key_id"`
-	S3SecretAccessKey    types.String `tfsdk:"s3_secret_access_key"`
-	S3RoleARN            types.String `tfsdk:"s3_role_arn"`
-	S3ExternalID         types.String `tfsdk:"s3_external_id"`
-	GCSCredentials       types.String `tfsdk:"gcs_credentials"`
-	GCSBucket            types.String `tfsdk:"gcs_bucket"`
-	GCSScopes            types.Set    `tfsdk:"gcs_scopes"`
-	GCSKeyPrefix         types.String `tfsdk:"gcs_key_prefix"`
+	ID                         types.String `tfsdk:"id"`
+	LogType                    types.String `tfsdk:"log_type"`
+	DestinationType            types.String `tfsdk:"destination_type"`
+	URL                        types.String `tfsdk:"url"`
+	User                       types.String `tfsdk:"user"`
+	Token                      types.String `tfsdk:"token"`
+	TokenWO                    types.String `tfsdk:"token_wo"`
+	TokenWOVersion             types.Int64  `tfsdk:"token_wo_version"`
+	UploadPeriodMinutes        types.Int32  `tfsdk:"upload_period_minutes"`
+	CompressionFormat          types.String `tfsdk:"compression_format"`
+	S3Bucket                   types.String `tfsdk:"s3_bucket"`
+	S3Region                   types.String `tfsdk:"s3_region"`
+	S3KeyPrefix                types.String `tfsdk:"s3_key_prefix"`
+	S3AuthenticationType       types.String `tfsdk:"s3_authentication_type"`
+	S3AccessKeyID              types.String `tfsdk:"s3_access_key_id"`
+	S3SecretAccessKey          types.String `tfsdk:"s3_secret_access_key"`
+	S3SecretAccessKeyWO        types.String `tfsdk:"s3_secret_access_key_wo"`
+	S3SecretAccessKeyWOVersion types.Int64  `tfsdk:"s3_secret_access_key_wo_version"`
+	S3RoleARN                  types.String `tfsdk:"s3_role_arn"`
+	S3ExternalID               types.String `tfsdk:"s3_external_id"`
+	GCSCredentials             types.String `tfsdk:"gcs_credentials"`
+	GCSCredentialsWO           types.String `tfsdk:"gcs_credentials_wo"`
+	GCSCredentialsWOVersion    types.Int64  `tfsdk:"gcs_credentials_wo_version"`
+	GCSBucket                  types.String `tfsdk:"gcs_bucket"`
+	GCSScopes                  types.Set    `tfsdk:"gcs_scopes"`
+	GCSKeyPrefix               types.String `tfsdk:"gcs_key_prefix"`
+	Tailnet                    types.String `tfsdk:"tailnet"`
 }
 
 func (d *logstreamConfigurationResourceModel) asRequest(ctx context.Context, diags *diag.Diagnostics) (tailscale.LogType, tailscale.SetLogstreamConfigurationRequest) {
@@ -284,19 +319,27 @@ func (d *logstreamConfigurationResourceModel) updateFields(ctx context.Context,
 		config.GCSScopes = []string{}
 	}
 	d.GCSScopes = SetOfStringValue(ctx, config.GCSScopes, diags)
-	d.GCSCredentials = types.StringValue(config.GCSCredentials)
+	// Credentials set with gcs_credentials_wo must not be written to state.
+	if d.GCSCredentialsWOVersion.IsNull() {
+		d.GCSCredentials = types.StringValue(config.GCSCredentials)
+	}
 	d.GCSKeyPrefix = types.StringValue(config.GCSKeyPrefix)
 	d.GCSBucket = types.StringValue(config.GCSBucket)
 }
 
 // updateLogstreamConfiguration calls the Tailscale API to set logstream configuration.
-func (r *logstreamConfigurationResource) updateLogstreamConfiguration(ctx context.Context, data *logstreamConfigurationResourceModel, diags *diag.Diagnostics) {
+// Secrets given with write-only attributes are read from config and sent in
+// place of their stored counterparts.
+func (r *logstreamConfigurationResource) updateLogstreamConfiguration(ctx context.Context, data *logstreamConfigurationResourceModel, config tfsdk.Config, diags *diag.Diagnostics) {
 	logType, request := data.asRequest(ctx, diags)
+	request.Token = writeOnlyStringValue(ctx, config, "token_wo", data.Token, diags).ValueString()
+	request.S3SecretAccessKey = writeOnlyStringValue(ctx, config, "s3_secret_access_key_wo", data.S3SecretAccessKey, diags).ValueString()
+	request.GCSCredentials = writeOnlyStringValue(ctx, config, "gcs_credentials_wo", data.GCSCredentials, diags).ValueString()
 	if diags.HasError() {
 		return
 	}
 
-	if err := r.Client.Logging().SetLogstreamConfiguration(ctx, logType, request); err != nil {
+	if err := r.ClientForTailnet(data.Tailnet).Logging().SetLogstreamConfiguration(ctx, logType, request); err != nil {
 		diags.AddError("Failed to set logstream configuration", err.Error())
 	}
 }
@@ -308,13 +351,17 @@ func (r *logstreamConfigurationResource) Create(ctx context.Context, req resourc
 		return
 	}
 
-	r.updateLogstreamConfiguration(ctx, &plan, &resp.Diagnostics)
+	r.updateLogstreamConfiguration(ctx, &plan, req.Config, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.ID = plan.LogType
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, logstreamIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		LogType: plan.ID,
+	})...)
 }
 
 func (r *logstreamConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -324,7 +371,12 @@ func (r *logstreamConfigurationResource) Read(ctx context.Context, req resource.
 		return
 	}
 
-	config, err := r.Client.Logging().LogstreamConfiguration(ctx, tailscale.LogType(state.ID.ValueString()))
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, logstreamIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		LogType: state.ID,
+	})...)
+
+	config, err := r.ClientForTailnet(state.Tailnet).Logging().LogstreamConfiguration(ctx, tailscale.LogType(state.ID.ValueString()))
 	if err != nil {
 		if tailscale.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
@@ -345,8 +397,12 @@ func (r *logstreamConfigurationResource) Update(ctx context.Context, req resourc
 		return
 	}
 
-	r.updateLogstreamConfiguration(ctx, &plan, &resp.Diagnostics)
+	r.updateLogstreamConfiguration(ctx, &plan, req.Config, &resp.Diagnostics)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, logstreamIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		LogType: plan.ID,
+	})...)
 }
 
 func (r *logstreamConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -357,7 +413,7 @@ func (r *logstreamConfigurationResource) Delete(ctx context.Context, req resourc
 	}
 
 	logType := tailscale.LogType(state.LogType.ValueString())
-	err := r.Client.Logging().DeleteLogstreamConfiguration(ctx, logType)
+	err := r.ClientForTailnet(state.Tailnet).Logging().DeleteLogstreamConfiguration(ctx, logType)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete logstream configuration", err.Error())
 	}
@@ -5,12 +5,19 @@ package tailscale
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 
 	"tailscale.com/client/tailscale/v2"
 )
@@ -442,3 +449,70 @@ func TestAccTailscaleLogstreamConfiguration(t *testing.T) {
 		}
 	}
 }
+
+func TestProvider_TailscaleLogstreamConfigurationWriteOnly(t *testing.T) {
+	const resourceName = "tailscale_logstream_configuration.test_logstream_configuration"
+	const testLogstreamConfigurationWriteOnly = `
+		resource "tailscale_logstream_configuration" "test_logstream_configuration" {
+			log_type         = "configuration"
+			destination_type = "panther"
+			url              = "https://example.com"
+			token_wo         = %q
+			token_wo_version = %d
+		}`
+
+	config := tailscale.LogstreamConfiguration{
+		LogType:           tailscale.LogTypeConfig,
+		DestinationType:   tailscale.LogstreamPantherEndpoint,
+		URL:               "https://example.com",
+		User:              "user",
+		CompressionFormat: tailscale.CompressionFormatNone,
+	}
+
+	var tokens []string
+	resource.Test(t, resource.TestCase{
+		IsUnitTest: true,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		PreCheck: func() {
+			testServer.HandleRequest = func(method, path string) TestResponse {
+				if method == http.MethodPut {
+					var req tailscale.SetLogstreamConfigurationRequest
+					if err := json.Unmarshal(testServer.Body.Bytes(), &req); err != nil {
+						return TestResponse{Code: http.StatusBadRequest}
+					}
+					tokens = append(tokens, req.Token)
+				}
+				return TestResponse{Code: http.StatusOK, Body: config}
+			}
+		},
+		ProtoV5ProviderFactories: testProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testLogstreamConfigurationWriteOnly, "token1", 1),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(resourceName, tfjsonpath.New("token"), knownvalue.Null()),
+					statecheck.ExpectKnownValue(resourceName, tfjsonpath.New("token_wo"), knownvalue.Null()),
+					statecheck.ExpectKnownValue(resourceName, tfjsonpath.New("token_wo_version"), knownvalue.Int64Exact(1)),
+				},
+				Check: func(s *terraform.State) error {
+					return assertEqual([]string{"token1"}, tokens, "token should be sent on create")
+				},
+			},
+			{
+				// Changing only the write-only value has no effect.
+				Config: fmt.Sprintf(testLogstreamConfigurationWriteOnly, "token2", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			{
+				Config: fmt.Sprintf(testLogstreamConfigurationWriteOnly, "token2", 2),
+				Check: func(s *terraform.State) error {
+					return assertEqual([]string{"token1", "token2"}, tokens, "new token should be sent when the version changes")
+				},
+			},
+		},
+	})
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -24,6 +26,8 @@ var (
 	_ resource.Resource                = &oauthClientResource{}
 	_ resource.ResourceWithConfigure   = &oauthClientResource{}
 	_ resource.ResourceWithImportState = &oauthClientResource{}
+	_ resource.ResourceWithIdentity    = &oauthClientResource{}
+	_ resource.ResourceWithModifyPlan  = &oauthClientResource{}
 )
 
 type oauthClientResourceModel struct {
@@ -35,6 +39,7 @@ type oauthClientResourceModel struct {
 	CreatedAt   types.String `tfsdk:"created_at"`
 	UpdatedAt   types.String `tfsdk:"updated_at"`
 	UserID      types.String `tfsdk:"user_id"`
+	Tailnet     types.String `tfsdk:"tailnet"`
 }
 
 func NewOAuthClientResource() resource.Resource {
@@ -43,7 +48,6 @@ func NewOAuthClientResource() resource.Resource {
 
 type oauthClientResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 func (r *oauthClientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -54,6 +58,7 @@ func (r *oauthClientResource) Schema(_ context.Context, _ resource.SchemaRequest
 	resp.Schema = schema.Schema{
 		Description: "The oauth_client resource allows you to create OAuth clients to programmatically interact with the Tailscale API.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"description": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -112,6 +117,15 @@ func (r *oauthClientResource) Schema(_ context.Context, _ resource.SchemaRequest
 	}
 }
 
+func (r *oauthClientResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("id", "The client ID.")
+}
+
+// ImportState imports the resource by client ID or by identity.
+func (r *oauthClientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, r.providerData, "id", req, resp)
+}
+
 func (r *oauthClientResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state oauthClientResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -119,7 +133,12 @@ func (r *oauthClientResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	key, err := r.Client.Keys().Get(ctx, state.ID.ValueString())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		ID:      state.ID,
+	})...)
+
+	key, err := r.ClientForTailnet(state.Tailnet).Keys().Get(ctx, state.ID.ValueString())
 	if err != nil {
 		if tailscale.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
@@ -129,18 +148,25 @@ func (r *oauthClientResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	state.Description = types.StringValue(key.Description)
-	state.CreatedAt = types.StringValue(key.Created.Format(time.RFC3339))
-	state.UpdatedAt = types.StringValue(key.Updated.Format(time.RFC3339))
-	state.UserID = types.StringValue(key.UserID)
-	state.Scopes = SetOfStringValue(ctx, key.Scopes, &resp.Diagnostics)
+	state.populateFromKey(ctx, key, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// populateFromKey updates the model with the properties of an OAuth client.
+// The client secret is only available when the client is created.
+func (m *oauthClientResourceModel) populateFromKey(ctx context.Context, key *tailscale.Key, diags *diag.Diagnostics) {
+	m.ID = types.StringValue(key.ID)
+	m.Description = types.StringValue(key.Description)
+	m.CreatedAt = types.StringValue(key.Created.Format(time.RFC3339))
+	m.UpdatedAt = types.StringValue(key.Updated.Format(time.RFC3339))
+	m.UserID = types.StringValue(key.UserID)
+	m.Scopes = SetOfStringValue(ctx, key.Scopes, diags)
 
 	if key.Tags == nil {
 		key.Tags = []string{}
 	}
-	state.Tags = SetOfStringValue(ctx, key.Tags, &resp.Diagnostics)
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	m.Tags = SetOfStringValue(ctx, key.Tags, diags)
 }
 
 func (r *oauthClientResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -154,7 +180,7 @@ func (r *oauthClientResource) Create(ctx context.Context, req resource.CreateReq
 	resp.Diagnostics.Append(plan.Scopes.ElementsAs(ctx, &scopes, false)...)
 	resp.Diagnostics.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
 
-	key, err := r.Client.Keys().CreateOAuthClient(ctx, tailscale.CreateOAuthClientRequest{
+	key, err := r.ClientForTailnet(plan.Tailnet).Keys().CreateOAuthClient(ctx, tailscale.CreateOAuthClientRequest{
 		Description: plan.Description.ValueString(),
 		Scopes:      scopes,
 		Tags:        tags,
@@ -171,6 +197,10 @@ func (r *oauthClientResource) Create(ctx context.Context, req resource.CreateReq
 	plan.UserID = types.StringValue(key.UserID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
 }
 
 func (r *oauthClientResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -184,7 +214,7 @@ func (r *oauthClientResource) Update(ctx context.Context, req resource.UpdateReq
 	resp.Diagnostics.Append(plan.Scopes.ElementsAs(ctx, &scopes, false)...)
 	resp.Diagnostics.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
 
-	key, err := r.Client.Keys().SetOAuthClient(ctx, plan.ID.ValueString(), tailscale.SetOAuthClientRequest{
+	key, err := r.ClientForTailnet(plan.Tailnet).Keys().SetOAuthClient(ctx, plan.ID.ValueString(), tailscale.SetOAuthClientRequest{
 		Description: plan.Description.ValueString(),
 		Scopes:      scopes,
 		Tags:        tags,
@@ -197,6 +227,15 @@ func (r *oauthClientResource) Update(ctx context.Context, req resource.UpdateReq
 	plan.UpdatedAt = types.StringValue(key.Updated.Format(time.RFC3339))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
+}
+
+// ModifyPlan validates the planned tags against the policy file.
+func (r *oauthClientResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.ValidateTags(ctx, req, resp, path.Root("tags"))
 }
 
 func (r *oauthClientResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -206,7 +245,7 @@ func (r *oauthClientResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	err := r.Client.Keys().Delete(ctx, state.ID.ValueString())
+	err := r.ClientForTailnet(state.Tailnet).Keys().Delete(ctx, state.ID.ValueString())
 	if err != nil && !tailscale.IsNotFound(err) {
 		resp.Diagnostics.AddError("Failed to delete oauth client", err.Error())
 	}
@@ -0,0 +1,982 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/tailscale/hujson"
+)
+
+var (
+	_ resource.Resource                = &policyResource{}
+	_ resource.ResourceWithConfigure   = &policyResource{}
+	_ resource.ResourceWithImportState = &policyResource{}
+	_ resource.ResourceWithIdentity    = &policyResource{}
+	_ resource.ResourceWithModifyPlan  = &policyResource{}
+)
+
+type policyResourceModel struct {
+	ID                       types.String              `tfsdk:"id"`
+	HuJSON                   types.String              `tfsdk:"hujson"`
+	OverwriteExistingContent types.Bool                `tfsdk:"overwrite_existing_content"`
+	ResetPolicyOnDestroy     types.Bool                `tfsdk:"reset_policy_on_destroy"`
+	Tailnet                  types.String              `tfsdk:"tailnet"`
+	ACLs                     []policyACLModel          `tfsdk:"acls"`
+	Grants                   []policyGrantModel        `tfsdk:"grants"`
+	Groups                   []policyGroupModel        `tfsdk:"groups"`
+	TagOwners                []policyTagOwnerModel     `tfsdk:"tag_owners"`
+	Hosts                    []policyHostModel         `tfsdk:"hosts"`
+	AutoApprovers            *policyAutoApproversModel `tfsdk:"auto_approvers"`
+	SSH                      []policySSHModel          `tfsdk:"ssh"`
+	NodeAttrs                []policyNodeAttrModel     `tfsdk:"node_attrs"`
+	Postures                 []policyPostureModel      `tfsdk:"postures"`
+	IPSets                   []policyIPSetModel        `tfsdk:"ipsets"`
+	Tests                    []policyTestModel         `tfsdk:"tests"`
+}
+
+type policyACLModel struct {
+	Action     types.String `tfsdk:"action"`
+	Src        types.List   `tfsdk:"src"`
+	Dst        types.List   `tfsdk:"dst"`
+	Proto      types.String `tfsdk:"proto"`
+	SrcPosture types.List   `tfsdk:"src_posture"`
+}
+
+type policyGrantModel struct {
+	Src        types.List   `tfsdk:"src"`
+	Dst        types.List   `tfsdk:"dst"`
+	IP         types.List   `tfsdk:"ip"`
+	Via        types.List   `tfsdk:"via"`
+	SrcPosture types.List   `tfsdk:"src_posture"`
+	App        types.String `tfsdk:"app"`
+}
+
+type policyGroupModel struct {
+	Name    types.String `tfsdk:"name"`
+	Members types.Set    `tfsdk:"members"`
+}
+
+type policyTagOwnerModel struct {
+	Tag    types.String `tfsdk:"tag"`
+	Owners types.Set    `tfsdk:"owners"`
+}
+
+type policyHostModel struct {
+	Name    types.String `tfsdk:"name"`
+	Address types.String `tfsdk:"address"`
+}
+
+type policyAutoApproversModel struct {
+	Routes   []policyRouteApproversModel   `tfsdk:"routes"`
+	ExitNode types.Set                     `tfsdk:"exit_node"`
+	Services []policyServiceApproversModel `tfsdk:"services"`
+}
+
+type policyRouteApproversModel struct {
+	Route     types.String `tfsdk:"route"`
+	Approvers types.Set    `tfsdk:"approvers"`
+}
+
+type policyServiceApproversModel struct {
+	Service   types.String `tfsdk:"service"`
+	Approvers types.Set    `tfsdk:"approvers"`
+}
+
+type policySSHModel struct {
+	Action          types.String `tfsdk:"action"`
+	Src             types.List   `tfsdk:"src"`
+	Dst             types.List   `tfsdk:"dst"`
+	Users           types.List   `tfsdk:"users"`
+	CheckPeriod     types.String `tfsdk:"check_period"`
+	AcceptEnv       types.List   `tfsdk:"accept_env"`
+	Recorder        types.List   `tfsdk:"recorder"`
+	EnforceRecorder types.Bool   `tfsdk:"enforce_recorder"`
+	SrcPosture      types.List   `tfsdk:"src_posture"`
+}
+
+type policyNodeAttrModel struct {
+	Target types.List   `tfsdk:"target"`
+	Attr   types.List   `tfsdk:"attr"`
+	IPPool types.List   `tfsdk:"ip_pool"`
+	App    types.String `tfsdk:"app"`
+}
+
+type policyPostureModel struct {
+	Name  types.String `tfsdk:"name"`
+	Rules types.List   `tfsdk:"rules"`
+}
+
+type policyIPSetModel struct {
+	Name    types.String `tfsdk:"name"`
+	Entries types.List   `tfsdk:"entries"`
+}
+
+type policyTestModel struct {
+	Src    types.String `tfsdk:"src"`
+	Proto  types.String `tfsdk:"proto"`
+	Accept types.List   `tfsdk:"accept"`
+	Deny   types.List   `tfsdk:"deny"`
+}
+
+// NewPolicyResource returns a new policy resource.
+func NewPolicyResource() resource.Resource {
+	return &policyResource{}
+}
+
+type policyResource struct {
+	ResourceIdentifiedByTailnet
+}
+
+// Metadata defines the resource name as it appears in Terraform configurations.
+func (r *policyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy"
+}
+
+const resourcePolicyDescription = `The policy resource allows you to configure a Tailscale policy file using typed blocks instead of a HuJSON string. See https://tailscale.com/kb/1395/tailnet-policy-file for more information. Note that this resource will completely overwrite existing policy file contents for a given tailnet.
+
+The policy file is rendered as HuJSON in a deterministic format, and is validated against the Tailscale API during planning, so syntax errors and failing tests are surfaced before apply. Sections and fields of the policy file that cannot be represented by this resource, such as ` + "`derpMap`" + `, can't be managed: while the policy file contains them, plans that would update it fail rather than remove them. Use the ` + "`tailscale_acl`" + ` resource to manage them.`
+
+func (r *policyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: resourcePolicyDescription,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"hujson": schema.StringAttribute{
+				Computed:    true,
+				Description: "The policy file rendered as HuJSON, as it is sent to Tailscale.",
+			},
+			"overwrite_existing_content": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If true, will skip requirement to import the policy file before allowing changes. Be careful, can cause the policy file to be overwritten",
+			},
+			"reset_policy_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If true, will reset the policy file for the Tailnet to the default when this resource is destroyed",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"acls": schema.ListNestedBlock{
+				Description: "Access rules that allow traffic from sources to destinations. See https://tailscale.com/kb/1337/acl-syntax for more information.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"action": schema.StringAttribute{
+							Description: "The action to take. The only supported value is `accept`, which is the default.",
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString("accept"),
+							Validators: []validator.String{
+								stringvalidator.OneOf("accept"),
+							},
+						},
+						"src":         policyListAttribute("The sources the rule applies to, such as users, groups, tags, hosts or IP addresses.", true),
+						"dst":         policyListAttribute("The destinations the rule applies to, as `host:ports`.", true),
+						"proto":       policyStringAttribute("The IP protocol the rule applies to. Defaults to TCP, UDP and ICMP."),
+						"src_posture": policyListAttribute("The device postures that sources must match.", false),
+					},
+				},
+			},
+			"grants": schema.ListNestedBlock{
+				Description: "Grants that give sources access to destinations at the network or application layer. See https://tailscale.com/kb/1324/grants for more information.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"src":         policyListAttribute("The sources the grant applies to.", true),
+						"dst":         policyListAttribute("The destinations the grant applies to.", true),
+						"ip":          policyListAttribute("The network layer capabilities granted, as ports or `protocol:ports`.", false),
+						"via":         policyListAttribute("The tags of the routers or exit nodes that traffic must be routed through.", false),
+						"src_posture": policyListAttribute("The device postures that sources must match.", false),
+						"app":         policyJSONAttribute("The application layer capabilities granted, as a JSON object of capability names to lists of parameters. Use `jsonencode` to build this value."),
+					},
+				},
+			},
+			"groups": schema.SetNestedBlock{
+				Description: "Groups of users that can be referred to in other sections.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name":    policyNameAttribute("The name of the group, starting with `group:`.", "group"),
+						"members": policySetAttribute("The login names of the users in the group.", true),
+					},
+				},
+			},
+			"tag_owners": schema.SetNestedBlock{
+				Description: "The users and tags that can apply each tag to devices.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"tag":    policyNameAttribute("The name of the tag, starting with `tag:`.", "tag"),
+						"owners": policySetAttribute("The users, groups and tags that own the tag. Use an empty set to allow only admins to apply the tag.", true),
+					},
+				},
+			},
+			"hosts": schema.SetNestedBlock{
+				Description: "Human-friendly names for IP addresses and CIDR ranges.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "The name of the host.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"address": schema.StringAttribute{
+							Description: "The IP address or CIDR range of the host.",
+							Required:    true,
+						},
+					},
+				},
+			},
+			"auto_approvers": schema.SingleNestedBlock{
+				Description: "The users, groups and tags that can advertise routes, exit nodes and Services without further approval.",
+				Attributes: map[string]schema.Attribute{
+					"exit_node": policySetAttribute("The users, groups and tags whose devices can advertise themselves as exit nodes.", false),
+				},
+				Blocks: map[string]schema.Block{
+					"routes": schema.SetNestedBlock{
+						Description: "The approvers for advertised subnet routes.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"route": schema.StringAttribute{
+									Description: "The route, as a CIDR range.",
+									Required:    true,
+									Validators: []validator.String{
+										cidrValidator{},
+									},
+								},
+								"approvers": policySetAttribute("The users, groups and tags whose devices can advertise the route, or routes within it.", true),
+							},
+						},
+					},
+					"services": schema.SetNestedBlock{
+						Description: "The approvers for Service hosts.",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"service": schema.StringAttribute{
+									Description: "The name of the Service, starting with `svc:`, or a tag matching the Services.",
+									Required:    true,
+									Validators: []validator.String{
+										stringvalidator.LengthAtLeast(1),
+									},
+								},
+								"approvers": policySetAttribute("The users, groups and tags whose devices can host the Service.", true),
+							},
+						},
+					},
+				},
+			},
+			"ssh": schema.ListNestedBlock{
+				Description: "Rules that allow Tailscale SSH connections. See https://tailscale.com/kb/1193/tailscale-ssh for more information.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"action": schema.StringAttribute{
+							Description: "Whether to `accept` connections, or to `check` that users have recently re-authenticated.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("accept", "check"),
+							},
+						},
+						"src":          policyListAttribute("The users, groups and tags that can connect.", true),
+						"dst":          policyListAttribute("The devices that can be connected to, as tags or `autogroup:self`.", true),
+						"users":        policyListAttribute("The users that can be logged in as on the destination devices.", true),
+						"check_period": policyStringAttribute("How often users must re-authenticate when `action` is `check`, such as `12h`, or `always`."),
+						"accept_env":   policyListAttribute("The environment variables that clients can forward to the session.", false),
+						"recorder":     policyListAttribute("The tags of the recorders that sessions are sent to.", false),
+						"src_posture":  policyListAttribute("The device postures that sources must match.", false),
+						"enforce_recorder": schema.BoolAttribute{
+							Description: "Whether to reject connections if no recorder is available. Defaults to false.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+						},
+					},
+				},
+			},
+			"node_attrs": schema.ListNestedBlock{
+				Description: "Attributes that are applied to devices.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"target":  policyListAttribute("The users, groups, tags and devices the attributes apply to.", true),
+						"attr":    policyListAttribute("The attributes to apply.", false),
+						"ip_pool": policyListAttribute("The CIDR ranges to allocate addresses from.", false),
+						"app":     policyJSONAttribute("The app connector configuration, as a JSON object. Use `jsonencode` to build this value."),
+					},
+				},
+			},
+			"postures": schema.SetNestedBlock{
+				Description: "Device posture conditions that can be referred to in other sections. See https://tailscale.com/kb/1288/device-posture for more information.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name":  policyNameAttribute("The name of the posture, starting with `posture:`.", "posture"),
+						"rules": policyListAttribute("The conditions that devices must meet, such as `node:os == 'linux'`.", true),
+					},
+				},
+			},
+			"ipsets": schema.SetNestedBlock{
+				Description: "Named sets of IP addresses and CIDR ranges that can be referred to in other sections.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name":    policyNameAttribute("The name of the IP set, starting with `ipset:`.", "ipset"),
+						"entries": policyListAttribute("The addresses, ranges, hosts and other IP sets in the IP set, optionally prefixed with `add` or `remove`.", true),
+					},
+				},
+			},
+			"tests": schema.ListNestedBlock{
+				Description: "Tests that are checked whenever the policy file changes. The policy file is rejected if any test fails.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"src": schema.StringAttribute{
+							Description: "The user or tag to test access from.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"proto":  policyStringAttribute("The IP protocol to test."),
+						"accept": policyListAttribute("The destinations, as `host:port`, that `src` must be able to access.", false),
+						"deny":   policyListAttribute("The destinations, as `host:port`, that `src` must not be able to access.", false),
+					},
+				},
+			},
+		},
+	}
+}
+
+// policyListAttribute returns a list of strings in the policy file. Empty lists
+// are rejected, because they are omitted when the policy file is rendered.
+func policyListAttribute(description string, required bool) schema.ListAttribute {
+	return schema.ListAttribute{
+		Description: description,
+		ElementType: types.StringType,
+		Required:    required,
+		Optional:    !required,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+		},
+	}
+}
+
+// policySetAttribute returns a set of strings in the policy file, which are
+// rendered in sorted order. Optional sets must not be empty, because they are
+// omitted when the policy file is rendered, while required sets can be empty
+// and are rendered as an empty list.
+func policySetAttribute(description string, required bool) schema.SetAttribute {
+	attribute := schema.SetAttribute{
+		Description: description,
+		ElementType: types.StringType,
+		Required:    required,
+		Optional:    !required,
+	}
+	if !required {
+		attribute.Validators = []validator.Set{
+			setvalidator.SizeAtLeast(1),
+		}
+	}
+	return attribute
+}
+
+// policyStringAttribute returns an optional string in the policy file.
+func policyStringAttribute(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: description,
+		Optional:    true,
+		Validators: []validator.String{
+			stringvalidator.LengthAtLeast(1),
+		},
+	}
+}
+
+// policyJSONAttribute returns an optional JSON object in the policy file.
+func policyJSONAttribute(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: description,
+		Optional:    true,
+		Validators: []validator.String{
+			jsonObjectValidator{},
+		},
+	}
+}
+
+// policyNameAttribute returns the name of an entry in the policy file, which
+// must have the given prefix, such as `group:`.
+func policyNameAttribute(description, prefix string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: description,
+		Required:    true,
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(regexp.MustCompile("^"+prefix+":.+"), "must start with "+prefix+":"),
+		},
+	}
+}
+
+func (r *policyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state policyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
+	acl, err := r.ClientForTailnet(state.Tailnet).PolicyFile().Raw(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to fetch policy file", err.Error())
+		return
+	}
+
+	doc, unsupported, err := parsePolicyDocument(acl.HuJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse policy file", err.Error())
+		return
+	}
+	if len(unsupported) > 0 {
+		resp.Diagnostics.AddWarning("Unsupported policy file content",
+			fmt.Sprintf("The policy file contains sections or fields that cannot be managed by this resource: %s. Changes to this resource can't be applied until they are removed from the policy file, as applying them would remove them.", strings.Join(unsupported, ", ")))
+	}
+
+	policy, err := doc.render()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render policy file", err.Error())
+		return
+	}
+
+	// JSON values are kept as they are written in the configuration, unless
+	// they have changed.
+	prior := state
+	state.setDocument(doc)
+	for i := range min(len(prior.Grants), len(state.Grants)) {
+		state.Grants[i].App = keepEquivalentJSON(prior.Grants[i].App, state.Grants[i].App)
+	}
+	for i := range min(len(prior.NodeAttrs), len(state.NodeAttrs)) {
+		state.NodeAttrs[i].App = keepEquivalentJSON(prior.NodeAttrs[i].App, state.NodeAttrs[i].App)
+	}
+	state.HuJSON = types.StringValue(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *policyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan policyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy := plan.render(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Setting the `ts-default` ETag will make this operation succeed only if
+	// the policy file has never been changed from its default value.
+	var etag string
+	if !plan.OverwriteExistingContent.ValueBool() {
+		etag = "ts-default"
+	}
+
+	if err := r.ClientForTailnet(plan.Tailnet).PolicyFile().Set(ctx, policy, etag); err != nil {
+		if strings.HasSuffix(err.Error(), "(412)") {
+			resp.Diagnostics.AddError("Overwrite Protected",
+				"You are trying to overwrite a non-default policy. Please import the policy first or set overwrite_existing_content = true.")
+			return
+		}
+		resp.Diagnostics.AddError("Failed to set policy file", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(createUUID())
+	plan.HuJSON = types.StringValue(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
+}
+
+func (r *policyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan policyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy := plan.render(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.ClientForTailnet(plan.Tailnet).PolicyFile().Set(ctx, policy, ""); err != nil {
+		resp.Diagnostics.AddError("Failed to update policy file", err.Error())
+		return
+	}
+
+	plan.HuJSON = types.StringValue(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(r.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
+}
+
+// ModifyPlan renders the planned policy file, so that it can be reviewed in
+// the plan, and validates it against the Tailscale API so that syntax errors
+// and failing tests are surfaced at plan time rather than apply.
+func (r *policyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to render when destroying.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// The policy file cannot be rendered until the whole configuration is known.
+	if !req.Config.Raw.IsFullyKnown() {
+		var tailnet types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tailnet"), &tailnet)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("hujson"), types.StringUnknown())...)
+		r.PlanPolicyChange(tailnet, types.StringUnknown())
+		return
+	}
+
+	var config policyResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy := config.render(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("hujson"), policy)...)
+
+	// Tags of other resources are validated against the planned policy file.
+	var current types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("hujson"), &current)...)
+	}
+	if current.ValueString() != policy {
+		r.PlanPolicyChange(config.Tailnet, types.StringValue(policy))
+	}
+
+	// Nothing to validate against before the provider is configured.
+	if r.Client == nil {
+		return
+	}
+
+	client := r.ClientForTailnet(config.Tailnet)
+
+	// Updating the policy file replaces it with the rendered sections, so
+	// refuse to plan an update that would silently remove sections this
+	// resource cannot represent.
+	if !req.State.Raw.IsNull() && current.ValueString() != policy {
+		acl, err := client.PolicyFile().Raw(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to fetch policy file", err.Error())
+			return
+		}
+		_, unsupported, err := parsePolicyDocument(acl.HuJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse policy file", err.Error())
+			return
+		}
+		if len(unsupported) > 0 {
+			resp.Diagnostics.AddError("Unsupported policy file content",
+				fmt.Sprintf("The policy file contains sections or fields that cannot be managed by this resource: %s. Applying this plan would remove them, so remove them from the policy file first, or manage it with tailscale_acl instead.", strings.Join(unsupported, ", ")))
+			return
+		}
+	}
+
+	if err := client.PolicyFile().Validate(ctx, policy); err != nil {
+		resp.Diagnostics.AddError("Invalid policy file", err.Error())
+	}
+}
+
+func (r *policyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state policyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	// Each tailnet always has a policy file, so deleting a resource will only
+	// remove it from Terraform state, leaving the policy file intact.
+	if !state.ResetPolicyOnDestroy.ValueBool() {
+		return
+	}
+
+	// Setting the policy file to an empty string resets it to the default.
+	if err := r.ClientForTailnet(state.Tailnet).PolicyFile().Set(ctx, "", ""); err != nil {
+		resp.Diagnostics.AddError("Failed to reset policy file", err.Error())
+	}
+}
+
+// render returns the policy file described by the model as HuJSON.
+func (m *policyResourceModel) render(ctx context.Context, diags *diag.Diagnostics) string {
+	doc := m.document(ctx, diags)
+	if diags.HasError() {
+		return ""
+	}
+
+	policy, err := doc.render()
+	if err != nil {
+		diags.AddError("Failed to render policy file", err.Error())
+	}
+	return policy
+}
+
+// document converts the model into a [policyDocument], reporting an error if
+// the same name is used more than once in a section.
+func (m *policyResourceModel) document(ctx context.Context, diags *diag.Diagnostics) *policyDocument {
+	doc := &policyDocument{}
+
+	for _, acl := range m.ACLs {
+		doc.ACLs = append(doc.ACLs, policyACL{
+			Action:     cmp.Or(acl.Action.ValueString(), "accept"),
+			Source:     policyStrings(ctx, acl.Src, diags),
+			Proto:      acl.Proto.ValueString(),
+			Dest:       policyStrings(ctx, acl.Dst, diags),
+			SrcPosture: policyStrings(ctx, acl.SrcPosture, diags),
+		})
+	}
+
+	for _, grant := range m.Grants {
+		doc.Grants = append(doc.Grants, policyGrant{
+			Source:     policyStrings(ctx, grant.Src, diags),
+			Dest:       policyStrings(ctx, grant.Dst, diags),
+			IP:         policyStrings(ctx, grant.IP, diags),
+			Via:        policyStrings(ctx, grant.Via, diags),
+			SrcPosture: policyStrings(ctx, grant.SrcPosture, diags),
+			App:        policyJSON(grant.App),
+		})
+	}
+
+	doc.Groups = make(map[string][]string)
+	for _, group := range m.Groups {
+		addPolicyEntry(doc.Groups, "groups", group.Name.ValueString(), policyRequiredStrings(ctx, group.Members, diags), diags)
+	}
+
+	doc.TagOwners = make(map[string][]string)
+	for _, owner := range m.TagOwners {
+		// Tags without owners can only be applied by admins.
+		addPolicyEntry(doc.TagOwners, "tag_owners", owner.Tag.ValueString(), policyRequiredStrings(ctx, owner.Owners, diags), diags)
+	}
+
+	doc.Hosts = make(map[string]string)
+	for _, host := range m.Hosts {
+		addPolicyEntry(doc.Hosts, "hosts", host.Name.ValueString(), host.Address.ValueString(), diags)
+	}
+
+	if aa := m.AutoApprovers; aa != nil {
+		doc.AutoApprovers = &policyAutoApprovers{
+			Routes:   make(map[string][]string),
+			ExitNode: policyStrings(ctx, aa.ExitNode, diags),
+			Services: make(map[string][]string),
+		}
+		for _, route := range aa.Routes {
+			addPolicyEntry(doc.AutoApprovers.Routes, "auto_approvers.routes", route.Route.ValueString(), policyRequiredStrings(ctx, route.Approvers, diags), diags)
+		}
+		for _, svc := range aa.Services {
+			addPolicyEntry(doc.AutoApprovers.Services, "auto_approvers.services", svc.Service.ValueString(), policyRequiredStrings(ctx, svc.Approvers, diags), diags)
+		}
+	}
+
+	for _, rule := range m.SSH {
+		doc.SSH = append(doc.SSH, policySSH{
+			Action:          rule.Action.ValueString(),
+			Source:          policyStrings(ctx, rule.Src, diags),
+			Dest:            policyStrings(ctx, rule.Dst, diags),
+			Users:           policyStrings(ctx, rule.Users, diags),
+			CheckPeriod:     rule.CheckPeriod.ValueString(),
+			AcceptEnv:       policyStrings(ctx, rule.AcceptEnv, diags),
+			Recorder:        policyStrings(ctx, rule.Recorder, diags),
+			EnforceRecorder: rule.EnforceRecorder.ValueBool(),
+			SrcPosture:      policyStrings(ctx, rule.SrcPosture, diags),
+		})
+	}
+
+	for _, attr := range m.NodeAttrs {
+		doc.NodeAttrs = append(doc.NodeAttrs, policyNodeAttr{
+			Target: policyStrings(ctx, attr.Target, diags),
+			Attr:   policyStrings(ctx, attr.Attr, diags),
+			IPPool: policyStrings(ctx, attr.IPPool, diags),
+			App:    policyJSON(attr.App),
+		})
+	}
+
+	doc.Postures = make(map[string][]string)
+	for _, posture := range m.Postures {
+		addPolicyEntry(doc.Postures, "postures", posture.Name.ValueString(), policyStrings(ctx, posture.Rules, diags), diags)
+	}
+
+	doc.IPSets = make(map[string][]string)
+	for _, ipset := range m.IPSets {
+		addPolicyEntry(doc.IPSets, "ipsets", ipset.Name.ValueString(), policyStrings(ctx, ipset.Entries, diags), diags)
+	}
+
+	for _, test := range m.Tests {
+		doc.Tests = append(doc.Tests, policyTest{
+			Source: test.Src.ValueString(),
+			Proto:  test.Proto.ValueString(),
+			Accept: policyStrings(ctx, test.Accept, diags),
+			Deny:   policyStrings(ctx, test.Deny, diags),
+		})
+	}
+
+	return doc
+}
+
+// setDocument replaces the policy file sections of the model with the
+// contents of doc.
+func (m *policyResourceModel) setDocument(doc *policyDocument) {
+	m.ACLs = nil
+	for _, acl := range doc.ACLs {
+		m.ACLs = append(m.ACLs, policyACLModel{
+			Action:     types.StringValue(acl.Action),
+			Src:        policyListValue(acl.Source),
+			Dst:        policyListValue(acl.Dest),
+			Proto:      policyStringValue(acl.Proto),
+			SrcPosture: policyListValue(acl.SrcPosture),
+		})
+	}
+
+	m.Grants = nil
+	for _, grant := range doc.Grants {
+		m.Grants = append(m.Grants, policyGrantModel{
+			Src:        policyListValue(grant.Source),
+			Dst:        policyListValue(grant.Dest),
+			IP:         policyListValue(grant.IP),
+			Via:        policyListValue(grant.Via),
+			SrcPosture: policyListValue(grant.SrcPosture),
+			App:        policyJSONValue(grant.App),
+		})
+	}
+
+	m.Groups = nil
+	for _, name := range slices.Sorted(maps.Keys(doc.Groups)) {
+		m.Groups = append(m.Groups, policyGroupModel{
+			Name:    types.StringValue(name),
+			Members: policyRequiredSetValue(doc.Groups[name]),
+		})
+	}
+
+	m.TagOwners = nil
+	for _, tag := range slices.Sorted(maps.Keys(doc.TagOwners)) {
+		m.TagOwners = append(m.TagOwners, policyTagOwnerModel{
+			Tag:    types.StringValue(tag),
+			Owners: policyRequiredSetValue(doc.TagOwners[tag]),
+		})
+	}
+
+	m.Hosts = nil
+	for _, name := range slices.Sorted(maps.Keys(doc.Hosts)) {
+		m.Hosts = append(m.Hosts, policyHostModel{
+			Name:    types.StringValue(name),
+			Address: types.StringValue(doc.Hosts[name]),
+		})
+	}
+
+	m.AutoApprovers = nil
+	if aa := doc.AutoApprovers; aa != nil {
+		m.AutoApprovers = &policyAutoApproversModel{
+			ExitNode: policySetValue(aa.ExitNode),
+		}
+		for _, route := range slices.Sorted(maps.Keys(aa.Routes)) {
+			m.AutoApprovers.Routes = append(m.AutoApprovers.Routes, policyRouteApproversModel{
+				Route:     types.StringValue(route),
+				Approvers: policyRequiredSetValue(aa.Routes[route]),
+			})
+		}
+		for _, svc := range slices.Sorted(maps.Keys(aa.Services)) {
+			m.AutoApprovers.Services = append(m.AutoApprovers.Services, policyServiceApproversModel{
+				Service:   types.StringValue(svc),
+				Approvers: policyRequiredSetValue(aa.Services[svc]),
+			})
+		}
+	}
+
+	m.SSH = nil
+	for _, rule := range doc.SSH {
+		m.SSH = append(m.SSH, policySSHModel{
+			Action:          types.StringValue(rule.Action),
+			Src:             policyListValue(rule.Source),
+			Dst:             policyListValue(rule.Dest),
+			Users:           policyListValue(rule.Users),
+			CheckPeriod:     policyStringValue(rule.CheckPeriod),
+			AcceptEnv:       policyListValue(rule.AcceptEnv),
+			Recorder:        policyListValue(rule.Recorder),
+			EnforceRecorder: types.BoolValue(rule.EnforceRecorder),
+			SrcPosture:      policyListValue(rule.SrcPosture),
+		})
+	}
+
+	m.NodeAttrs = nil
+	for _, attr := range doc.NodeAttrs {
+		m.NodeAttrs = append(m.NodeAttrs, policyNodeAttrModel{
+			Target: policyListValue(attr.Target),
+			Attr:   policyListValue(attr.Attr),
+			IPPool: policyListValue(attr.IPPool),
+			App:    policyJSONValue(attr.App),
+		})
+	}
+
+	m.Postures = nil
+	for _, name := range slices.Sorted(maps.Keys(doc.Postures)) {
+		m.Postures = append(m.Postures, policyPostureModel{
+			Name:  types.StringValue(name),
+			Rules: policyListValue(doc.Postures[name]),
+		})
+	}
+
+	m.IPSets = nil
+	for _, name := range slices.Sorted(maps.Keys(doc.IPSets)) {
+		m.IPSets = append(m.IPSets, policyIPSetModel{
+			Name:    types.StringValue(name),
+			Entries: policyListValue(doc.IPSets[name]),
+		})
+	}
+
+	m.Tests = nil
+	for _, test := range doc.Tests {
+		m.Tests = append(m.Tests, policyTestModel{
+			Src:    types.StringValue(test.Source),
+			Proto:  policyStringValue(test.Proto),
+			Accept: policyListValue(test.Accept),
+			Deny:   policyListValue(test.Deny),
+		})
+	}
+}
+
+// addPolicyEntry adds the named entry to a section of the policy file,
+// reporting an error if it is already present.
+func addPolicyEntry[V any](section map[string]V, block, name string, value V, diags *diag.Diagnostics) {
+	if _, ok := section[name]; ok {
+		diags.AddAttributeError(path.Root(block), "Duplicate policy file entry",
+			fmt.Sprintf("%q is defined more than once.", name))
+		return
+	}
+	section[name] = value
+}
+
+// policyStrings returns the elements of a list or set of strings, which are
+// sorted for sets so that they are always rendered in the same order.
+func policyStrings(ctx context.Context, value interface {
+	ElementsAs(context.Context, any, bool) diag.Diagnostics
+}, diags *diag.Diagnostics) []string {
+	var values []string
+	diags.Append(value.ElementsAs(ctx, &values, false)...)
+	if _, ok := value.(types.Set); ok {
+		slices.Sort(values)
+	}
+	return values
+}
+
+// policyRequiredStrings returns the elements of a required set of strings,
+// which is an empty list rather than nil if the set is empty, so that it is
+// rendered as an empty list rather than null.
+func policyRequiredStrings(ctx context.Context, value types.Set, diags *diag.Diagnostics) []string {
+	values := policyStrings(ctx, value, diags)
+	if values == nil {
+		values = []string{}
+	}
+	return values
+}
+
+// policyJSON returns the JSON value of a string attribute, or nil if it is null.
+func policyJSON(value types.String) json.RawMessage {
+	if value.IsNull() {
+		return nil
+	}
+	return json.RawMessage(value.ValueString())
+}
+
+// policyListValue returns a list of strings, or null if values is empty.
+func policyListValue(values []string) types.List {
+	if len(values) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	return types.ListValueMust(types.StringType, policyStringValues(values))
+}
+
+// policySetValue returns a set of strings, or null if values is empty.
+func policySetValue(values []string) types.Set {
+	if len(values) == 0 {
+		return types.SetNull(types.StringType)
+	}
+	return types.SetValueMust(types.StringType, policyStringValues(values))
+}
+
+// policyRequiredSetValue returns a set of strings for a required attribute,
+// which is empty rather than null if values is empty.
+func policyRequiredSetValue(values []string) types.Set {
+	return types.SetValueMust(types.StringType, policyStringValues(values))
+}
+
+func policyStringValues(values []string) []attr.Value {
+	elems := make([]attr.Value, len(values))
+	for i, v := range values {
+		elems[i] = types.StringValue(v)
+	}
+	return elems
+}
+
+// keepEquivalentJSON returns prior if it is the same JSON value as current.
+// Either value can be HuJSON, so that comments and trailing commas in the
+// configuration don't cause a diff.
+func keepEquivalentJSON(prior, current types.String) types.String {
+	if prior.IsNull() || current.IsNull() {
+		return current
+	}
+
+	var p, c any
+	if unmarshalHuJSON(prior.ValueString(), &p) != nil || unmarshalHuJSON(current.ValueString(), &c) != nil {
+		return current
+	}
+	if reflect.DeepEqual(p, c) {
+		return prior
+	}
+	return current
+}
+
+// unmarshalHuJSON parses the HuJSON value into v.
+func unmarshalHuJSON(value string, v any) error {
+	b, err := hujson.Standardize([]byte(value))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// policyStringValue returns a string, or null if value is empty.
+func policyStringValue(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// policyJSONValue returns a compacted JSON value, or null if value is empty.
+func policyJSONValue(value json.RawMessage) types.String {
+	var buf bytes.Buffer
+	if len(value) == 0 || json.Compact(&buf, value) != nil {
+		return types.StringNull()
+	}
+	return types.StringValue(buf.String())
+}
@@ -0,0 +1,520 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/hujson"
+)
+
+var (
+	_ resource.Resource                = &policyFragmentResource{}
+	_ resource.ResourceWithConfigure   = &policyFragmentResource{}
+	_ resource.ResourceWithImportState = &policyFragmentResource{}
+	_ resource.ResourceWithIdentity    = &policyFragmentResource{}
+	_ resource.ResourceWithModifyPlan  = &policyFragmentResource{}
+)
+
+// policyFragmentMarker precedes the key of the fragment that owns an entry in
+// the policy file, in a comment before the entry.
+const policyFragmentMarker = "// tailscale_policy_fragment: "
+
+// policyFragmentMaxAttempts is the number of times a fragment is merged into
+// the policy file before giving up, if the policy file keeps being changed
+// concurrently.
+const policyFragmentMaxAttempts = 5
+
+// policyFragmentSections are the sections of the policy file that fragments
+// can add entries to, and whether their entries are the members of an object,
+// rather than the elements of a list.
+var policyFragmentSections = map[string]bool{
+	"groups":                 true,
+	"tagOwners":              true,
+	"hosts":                  true,
+	"ipsets":                 true,
+	"postures":               true,
+	"acls":                   false,
+	"grants":                 false,
+	"ssh":                    false,
+	"nodeAttrs":              false,
+	"tests":                  false,
+	"autoApprovers.routes":   true,
+	"autoApprovers.services": true,
+	"autoApprovers.exitNode": false,
+}
+
+// policyFragmentSectionNames are the names of [policyFragmentSections], sorted.
+var policyFragmentSectionNames = slices.Sorted(maps.Keys(policyFragmentSections))
+
+type policyFragmentResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Key     types.String `tfsdk:"key"`
+	Section types.String `tfsdk:"section"`
+	Content types.String `tfsdk:"content"`
+	Tailnet types.String `tfsdk:"tailnet"`
+}
+
+// policyFragmentIdentityModel is the identity of a policy fragment.
+type policyFragmentIdentityModel struct {
+	Tailnet types.String `tfsdk:"tailnet"`
+	Key     types.String `tfsdk:"key"`
+}
+
+// NewPolicyFragmentResource returns a new policy fragment resource.
+func NewPolicyFragmentResource() resource.Resource {
+	return &policyFragmentResource{}
+}
+
+type policyFragmentResource struct {
+	ResourceBase
+}
+
+// Metadata defines the resource name as it appears in Terraform configurations.
+func (r *policyFragmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_policy_fragment"
+}
+
+const resourcePolicyFragmentDescription = `The policy_fragment resource manages some of the entries in one section of a Tailscale policy file, leaving the rest of the policy file untouched. This allows different teams or workspaces to own different parts of the same policy file.
+
+Each entry added by a fragment is preceded by a comment containing the fragment's key, which is how the fragment finds its own entries again. Changes are merged into the current policy file, and written using its ETag, so that concurrent changes to the policy file are not overwritten. Only changes to the fragment's own entries are detected as drift.
+
+Fragments should not be used together with the ` + "`tailscale_acl`" + ` or ` + "`tailscale_policy`" + ` resources for the same tailnet, which overwrite the whole policy file.`
+
+func (r *policyFragmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: resourcePolicyFragmentDescription,
+		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "A stable key that identifies the fragment's entries in the policy file. It must be unique within the tailnet, and can contain letters, digits, `-`, `_` and `.`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9_.-]+$`), "must only contain letters, digits, '-', '_' and '.'"),
+				},
+			},
+			"section": schema.StringAttribute{
+				Required:    true,
+				Description: fmt.Sprintf("The section of the policy file that the fragment adds entries to. Valid values are `%s`.", strings.Join(policyFragmentSectionNames, "`, `")),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(policyFragmentSectionNames...),
+				},
+			},
+			"content": schema.StringAttribute{
+				Required:    true,
+				Description: "The entries that the fragment adds to the section, as a JSON object for sections that are objects, such as `tagOwners`, or as a JSON list for sections that are lists, such as `grants`. Use `jsonencode` to build this value. Members of an object must not already be defined in the section by someone else.",
+				Validators: []validator.String{
+					aclHuJSONValidator{},
+				},
+			},
+		},
+	}
+}
+
+// IdentitySchema defines the attributes that identify a policy fragment.
+func (r *policyFragmentResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("key", "The key of the policy fragment.")
+}
+
+// ImportState imports the resource by key or by identity. The section is found
+// by looking for the fragment's entries in the policy file.
+func (r *policyFragmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, r.providerData, "key", req, resp)
+}
+
+func (r *policyFragmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state policyFragmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, policyFragmentIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		Key:     state.ID,
+	})...)
+
+	acl, err := r.ClientForTailnet(state.Tailnet).PolicyFile().Raw(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to fetch policy file", err.Error())
+		return
+	}
+
+	// The section is not known when importing, so every section is searched.
+	sections := policyFragmentSectionNames
+	if !state.Section.IsNull() {
+		sections = []string{state.Section.ValueString()}
+	}
+
+	for _, section := range sections {
+		content, found, err := policyFragmentContent(acl.HuJSON, section, state.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse policy file", err.Error())
+			return
+		}
+		if !found {
+			continue
+		}
+
+		state.Key = state.ID
+		state.Section = types.StringValue(section)
+		state.Content = keepEquivalentJSON(state.Content, types.StringValue(content))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	// All of the fragment's entries have been removed from the policy file.
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *policyFragmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan policyFragmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.updatePolicy(ctx, plan.Tailnet, func(policy string) (string, error) {
+		if _, found, err := policyFragmentContent(policy, plan.Section.ValueString(), plan.Key.ValueString()); err != nil {
+			return "", err
+		} else if found {
+			return "", fmt.Errorf("the policy file already contains entries for the fragment %q; import it instead", plan.Key.ValueString())
+		}
+		return mergePolicyFragment(policy, plan.Section.ValueString(), plan.Key.ValueString(), plan.Content.ValueString())
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to add policy fragment", err.Error())
+		return
+	}
+
+	plan.ID = plan.Key
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, policyFragmentIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		Key:     plan.Key,
+	})...)
+}
+
+func (r *policyFragmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan policyFragmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.updatePolicy(ctx, plan.Tailnet, func(policy string) (string, error) {
+		return mergePolicyFragment(policy, plan.Section.ValueString(), plan.Key.ValueString(), plan.Content.ValueString())
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update policy fragment", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, policyFragmentIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		Key:     plan.Key,
+	})...)
+}
+
+func (r *policyFragmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state policyFragmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.updatePolicy(ctx, state.Tailnet, func(policy string) (string, error) {
+		return mergePolicyFragment(policy, state.Section.ValueString(), state.Key.ValueString(), "")
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to remove policy fragment", err.Error())
+	}
+}
+
+// ModifyPlan validates the policy file that would result from merging the
+// planned fragment into the current policy file, so that conflicts, syntax
+// errors and failing tests are surfaced at plan time rather than apply.
+func (r *policyFragmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate when destroying or before the provider is configured.
+	if req.Plan.Raw.IsNull() || r.Client == nil {
+		return
+	}
+
+	var plan policyFragmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fragments are merged into the policy file at apply, so the tags of
+	// other resources can't be validated against it.
+	var state policyFragmentResourceModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if !plan.Content.Equal(state.Content) || !plan.Section.Equal(state.Section) || !plan.Key.Equal(state.Key) {
+		r.PlanPolicyChange(plan.Tailnet, types.StringUnknown())
+	}
+
+	if plan.Key.IsUnknown() || plan.Section.IsUnknown() || plan.Content.IsUnknown() || plan.Tailnet.IsUnknown() {
+		return
+	}
+
+	client := r.ClientForTailnet(plan.Tailnet)
+	acl, err := client.PolicyFile().Raw(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to fetch policy file", err.Error())
+		return
+	}
+
+	policy, err := mergePolicyFragment(acl.HuJSON, plan.Section.ValueString(), plan.Key.ValueString(), plan.Content.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), "Invalid policy fragment", err.Error())
+		return
+	}
+
+	if err := client.PolicyFile().Validate(ctx, policy); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), "Invalid policy fragment", err.Error())
+	}
+}
+
+// updatePolicy applies update to the current policy file, and writes the
+// result using the ETag of the policy file that was read, so that changes made
+// in the meantime are not overwritten. If the policy file was changed in the
+// meantime, the cycle is repeated with the new policy file.
+func (r *policyFragmentResource) updatePolicy(ctx context.Context, tailnet types.String, update func(policy string) (string, error)) error {
+	client := r.ClientForTailnet(tailnet)
+
+	for attempt := 1; ; attempt++ {
+		acl, err := client.PolicyFile().Raw(ctx)
+		if err != nil {
+			return err
+		}
+
+		policy, err := update(acl.HuJSON)
+		if err != nil {
+			return err
+		}
+		if policy == acl.HuJSON {
+			return nil
+		}
+
+		err = client.PolicyFile().Set(ctx, policy, acl.ETag)
+		if !isPreconditionFailed(err) || attempt == policyFragmentMaxAttempts {
+			return err
+		}
+	}
+}
+
+// isPreconditionFailed returns true if err is an API error with a status of
+// 412, which is returned when the ETag of a policy file does not match.
+func isPreconditionFailed(err error) bool {
+	var apiErr tailscale.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status == http.StatusPreconditionFailed
+	}
+	return false
+}
+
+// mergePolicyFragment replaces the entries owned by the fragment with the given
+// key in a section of the policy file with the entries in content. If content
+// is empty, the fragment's entries are removed.
+func mergePolicyFragment(policy, section, key, content string) (string, error) {
+	root, err := hujson.Parse([]byte(policy))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	isObject := policyFragmentSections[section]
+	value, err := findPolicySection(&root, section, content != "")
+	if err != nil || value == nil {
+		return policy, err
+	}
+
+	switch kind := value.Value.Kind(); {
+	case isObject && kind != '{':
+		return "", fmt.Errorf("%s is not a JSON object in the policy file", section)
+	case !isObject && kind != '[':
+		return "", fmt.Errorf("%s is not a JSON list in the policy file", section)
+	}
+
+	marker := hujson.Extra("\n" + policyFragmentMarker + key + "\n")
+
+	var entries *hujson.Value
+	if content != "" {
+		v, err := hujson.Parse([]byte(content))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse content: %w", err)
+		}
+		entries = &v
+	}
+
+	switch entriesOf := value.Value.(type) {
+	case *hujson.Object:
+		entriesOf.Members = slices.DeleteFunc(entriesOf.Members, func(m hujson.ObjectMember) bool {
+			return ownedByPolicyFragment(m.Name.BeforeExtra, key)
+		})
+		if entries == nil {
+			break
+		}
+
+		obj, ok := entries.Value.(*hujson.Object)
+		if !ok {
+			return "", errors.New("content must be a JSON object for this section")
+		}
+		for _, m := range obj.Members {
+			name := m.Name.Value.(hujson.Literal).String()
+			if slices.ContainsFunc(entriesOf.Members, func(existing hujson.ObjectMember) bool {
+				return existing.Name.Value.(hujson.Literal).String() == name
+			}) {
+				return "", fmt.Errorf("%q is already defined in the policy file", name)
+			}
+			m.Name.BeforeExtra = marker
+			entriesOf.Members = append(entriesOf.Members, m)
+		}
+	case *hujson.Array:
+		entriesOf.Elements = slices.DeleteFunc(entriesOf.Elements, func(v hujson.Value) bool {
+			return ownedByPolicyFragment(v.BeforeExtra, key)
+		})
+		if entries == nil {
+			break
+		}
+
+		arr, ok := entries.Value.(*hujson.Array)
+		if !ok {
+			return "", errors.New("content must be a JSON list for this section")
+		}
+		for _, v := range arr.Elements {
+			v.BeforeExtra = marker
+			entriesOf.Elements = append(entriesOf.Elements, v)
+		}
+	}
+
+	root.Format()
+	return root.String(), nil
+}
+
+// policyFragmentContent returns the entries owned by the fragment with the
+// given key in a section of the policy file, as a compact JSON object or list,
+// and whether any entries were found.
+func policyFragmentContent(policy, section, key string) (string, bool, error) {
+	root, err := hujson.Parse([]byte(policy))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	value, err := findPolicySection(&root, section, false)
+	if err != nil || value == nil {
+		return "", false, err
+	}
+
+	var content hujson.Value
+	switch entriesOf := value.Value.(type) {
+	case *hujson.Object:
+		owned := &hujson.Object{}
+		for _, m := range entriesOf.Members {
+			if ownedByPolicyFragment(m.Name.BeforeExtra, key) {
+				owned.Members = append(owned.Members, m)
+			}
+		}
+		if len(owned.Members) == 0 {
+			return "", false, nil
+		}
+		content.Value = owned
+	case *hujson.Array:
+		owned := &hujson.Array{}
+		for _, v := range entriesOf.Elements {
+			if ownedByPolicyFragment(v.BeforeExtra, key) {
+				owned.Elements = append(owned.Elements, v)
+			}
+		}
+		if len(owned.Elements) == 0 {
+			return "", false, nil
+		}
+		content.Value = owned
+	default:
+		return "", false, nil
+	}
+
+	content = content.Clone()
+	content.Minimize()
+	return content.String(), true, nil
+}
+
+// findPolicySection returns the value of a section of the policy file, such as
+// `grants` or `autoApprovers.routes`. Keys in the policy file are matched
+// case-insensitively. If the section does not exist, it is created if create
+// is true, and nil is returned otherwise.
+func findPolicySection(root *hujson.Value, section string, create bool) (*hujson.Value, error) {
+	value := root
+	names := strings.Split(section, ".")
+	for i, name := range names {
+		obj, ok := value.Value.(*hujson.Object)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a JSON object in the policy file", strings.Join(names[:i], "."))
+		}
+
+		idx := slices.IndexFunc(obj.Members, func(m hujson.ObjectMember) bool {
+			return strings.EqualFold(m.Name.Value.(hujson.Literal).String(), name)
+		})
+		if idx < 0 {
+			if !create {
+				return nil, nil
+			}
+
+			var empty hujson.ValueTrimmed = &hujson.Object{}
+			if i == len(names)-1 && !policyFragmentSections[section] {
+				empty = &hujson.Array{}
+			}
+			obj.Members = append(obj.Members, hujson.ObjectMember{
+				Name:  hujson.Value{BeforeExtra: hujson.Extra("\n"), Value: hujson.String(name)},
+				Value: hujson.Value{Value: empty},
+			})
+			idx = len(obj.Members) - 1
+		}
+		value = &obj.Members[idx].Value
+	}
+	return value, nil
+}
+
+// ownedByPolicyFragment reports whether the comments before an entry in the
+// policy file mark it as owned by the fragment with the given key.
+func ownedByPolicyFragment(extra hujson.Extra, key string) bool {
+	for line := range strings.Lines(string(extra)) {
+		if strings.TrimSpace(line) == policyFragmentMarker+key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
+)
+
+func TestMergePolicyFragment(t *testing.T) {
+	const policy = `{
+	// Owned by the platform team.
+	"tagOwners": {
+		"tag:web": ["autogroup:admin"],
+	},
+	"grants": [
+		{"src": ["*"], "dst": ["*"], "ip": ["*"]},
+	],
+}
+`
+
+	merged, err := mergePolicyFragment(policy, "grants", "team-db", `[{"src":["group:db"],"dst":["tag:db"],"ip":["5432"]}]`)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+	// Owned by the platform team.
+	"tagOwners": {
+		"tag:web": ["autogroup:admin"],
+	},
+	"grants": [
+		{"src": ["*"], "dst": ["*"], "ip": ["*"]},
+		// tailscale_policy_fragment: team-db
+		{"src": ["group:db"], "dst": ["tag:db"], "ip": ["5432"]},
+	],
+}
+`, merged)
+
+	content, found, err := policyFragmentContent(merged, "grants", "team-db")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `[{"src":["group:db"],"dst":["tag:db"],"ip":["5432"]}]`, content)
+
+	// Entries of other fragments are not found.
+	_, found, err = policyFragmentContent(merged, "grants", "team-web")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// Updating a fragment replaces its entries in place of the old ones.
+	updated, err := mergePolicyFragment(merged, "grants", "team-db", `[{"src":["group:db"],"dst":["tag:db"],"ip":["5433"]}]`)
+	require.NoError(t, err)
+	assert.Contains(t, updated, `"ip": ["5433"]`)
+	assert.NotContains(t, updated, `"ip": ["5432"]`)
+
+	// Removing a fragment leaves the rest of the policy file as it was.
+	removed, err := mergePolicyFragment(updated, "grants", "team-db", "")
+	require.NoError(t, err)
+	assert.Equal(t, policy, removed)
+}
+
+func TestMergePolicyFragmentSections(t *testing.T) {
+	merged, err := mergePolicyFragment(`{}`, "autoApprovers.routes", "routes", `{"10.0.0.0/8":["tag:router"]}`)
+	require.NoError(t, err)
+
+	content, found, err := policyFragmentContent(merged, "autoApprovers.routes", "routes")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `{"10.0.0.0/8":["tag:router"]}`, content)
+
+	// Keys of the policy file are case-insensitive.
+	_, err = mergePolicyFragment(`{"TagOwners": {"tag:web": []}}`, "tagOwners", "web", `{"tag:web":["autogroup:admin"]}`)
+	assert.ErrorContains(t, err, `"tag:web" is already defined`)
+
+	_, err = mergePolicyFragment(`{}`, "grants", "web", `{"tag:web":[]}`)
+	assert.ErrorContains(t, err, "must be a JSON list")
+
+	_, err = mergePolicyFragment(`{"groups": []}`, "groups", "web", `{"group:web":[]}`)
+	assert.ErrorContains(t, err, "groups is not a JSON object")
+}
+
+func TestPolicyFragmentConcurrentUpdate(t *testing.T) {
+	server := fakecontrol.NewServer(t)
+	u, err := url.Parse(server.URL())
+	require.NoError(t, err)
+	client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+
+	r := &policyFragmentResource{}
+	r.Client = &client
+	r.providerData = &providerData{Client: &client}
+
+	// The policy file is changed by someone else between the fragment reading
+	// and writing it, so the fragment must be merged again.
+	attempts := 0
+	err = r.updatePolicy(context.Background(), types.StringNull(), func(policy string) (string, error) {
+		attempts++
+		if attempts == 1 {
+			server.SetPolicy(`{"hosts": {"db": "100.64.0.10"}}`)
+		}
+		return mergePolicyFragment(policy, "grants", "web", `[{"src":["*"],"dst":["tag:web"],"ip":["443"]}]`)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	policy, _ := server.Policy()
+	assert.Contains(t, policy, `"db": "100.64.0.10"`)
+	assert.Contains(t, policy, policyFragmentMarker+"web")
+}
+
+func TestProvider_TailscalePolicyFragmentLifecycle(t *testing.T) {
+	const resourceName = "tailscale_policy_fragment.web"
+
+	testPolicyFragment := func(port int) string {
+		return fmt.Sprintf(`
+			resource "tailscale_policy_fragment" "web" {
+				key     = "team-web"
+				section = "grants"
+				content = jsonencode([
+					{ src = ["group:web"], dst = ["tag:web"], ip = ["%d"] },
+				])
+			}
+
+			resource "tailscale_policy_fragment" "owners" {
+				key     = "team-web-owners"
+				section = "tagOwners"
+				content = jsonencode({ "tag:web" = ["group:web"] })
+			}`, port)
+	}
+
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"groups": {"group:web": ["alice@example.com"]}}`)
+
+	checkPolicy := func(contains ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			policy, _ := server.Policy()
+			for _, c := range contains {
+				if !strings.Contains(policy, c) {
+					return fmt.Errorf("policy file does not contain %q: %s", c, policy)
+				}
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			if policy, _ := server.Policy(); strings.Contains(policy, policyFragmentMarker) {
+				return fmt.Errorf("policy fragments were not removed: %s", policy)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testPolicyFragment(443),
+				Check:  checkPolicy(`"group:web": ["alice@example.com"]`, `"ip": ["443"]`, `"tag:web": ["group:web"]`),
+			},
+			{
+				// Changes to the rest of the policy file are not drift, and
+				// are kept when the fragment is updated.
+				PreConfig: func() {
+					policy, _ := server.Policy()
+					server.SetPolicy(strings.Replace(policy, "alice@example.com", "bob@example.com", 1))
+				},
+				Config: testPolicyFragment(8443),
+				Check:  checkPolicy(`"group:web": ["bob@example.com"]`, `"ip": ["8443"]`),
+			},
+			{
+				// HuJSON content is kept as written, rather than being
+				// replaced by its JSON equivalent on the next refresh.
+				Config: strings.Replace(testPolicyFragment(8443), `jsonencode({ "tag:web" = ["group:web"] })`, `<<-EOT
+					{
+						// Owned by the web team.
+						"tag:web": ["group:web"],
+					}
+				EOT`, 1),
+				Check: resource.TestCheckResourceAttrWith("tailscale_policy_fragment.owners", "content", func(value string) error {
+					if !strings.Contains(value, "// Owned by the web team.") {
+						return fmt.Errorf("content was not kept as written: %s", value)
+					}
+					return nil
+				}),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "team-web",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
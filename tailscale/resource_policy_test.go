@@ -0,0 +1,407 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
+)
+
+const testPolicyHuJSON = `{
+	"groups": {
+		"group:dev": ["alice@example.com", "bob@example.com"]
+	},
+	"tagOwners": {
+		"tag:prod": [],
+		"tag:web":  ["group:dev"]
+	},
+	"hosts": {
+		"db": "100.64.0.10"
+	},
+	"postures": {
+		"posture:latest": ["node:tsReleaseTrack == 'stable'"]
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src":    ["group:dev"],
+			"dst":    ["tag:web:443"]
+		}
+	],
+	"grants": [
+		{
+			"src": ["group:dev"],
+			"dst": ["tag:prod"],
+			"ip":  ["tcp:22"],
+			"app": {
+				"example.com/cap/admin": [
+					{
+						"read": true
+					}
+				]
+			}
+		}
+	],
+	"ssh": [
+		{
+			"action":      "check",
+			"src":         ["group:dev"],
+			"dst":         ["tag:web"],
+			"users":       ["root"],
+			"checkPeriod": "12h"
+		}
+	],
+	"autoApprovers": {
+		"routes": {
+			"10.0.0.0/8": ["tag:web"]
+		},
+		"exitNode": ["tag:web"]
+	},
+	"tests": [
+		{
+			"src":    "alice@example.com",
+			"accept": ["tag:web:443"],
+			"deny":   ["db:5432"]
+		}
+	]
+}
+`
+
+func TestParsePolicyDocument(t *testing.T) {
+	doc, unsupported, err := parsePolicyDocument(testPolicyHuJSON)
+	require.NoError(t, err)
+	assert.Empty(t, unsupported)
+
+	rendered, err := doc.render()
+	require.NoError(t, err)
+	assert.Equal(t, testPolicyHuJSON, rendered)
+}
+
+func TestParsePolicyDocumentLegacy(t *testing.T) {
+	doc, unsupported, err := parsePolicyDocument(`{
+		// Keys are case-insensitive.
+		"ACLs": [{"Action": "accept", "Users": ["*"], "Ports": ["*:*"]}],
+		"Tests": [{"User": "alice@example.com", "Allow": ["db:22"]}],
+		"DerpMap": {"Regions": {}},
+		"randomizeClientPort": true,
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DerpMap", "randomizeClientPort"}, unsupported)
+	assert.Equal(t, []policyACL{{Action: "accept", Source: []string{"*"}, Dest: []string{"*:*"}}}, doc.ACLs)
+	assert.Equal(t, []policyTest{{Source: "alice@example.com", Accept: []string{"db:22"}}}, doc.Tests)
+}
+
+func TestParsePolicyDocumentUnsupportedFields(t *testing.T) {
+	doc, unsupported, err := parsePolicyDocument(`{
+		"ssh": [{
+			"action":     "accept",
+			"src":        ["group:dev"],
+			"dst":        ["tag:web"],
+			"users":      ["root"],
+			"srcPosture": ["posture:latest"],
+			"futureField": "value",
+		}],
+		"grants": [{"src": ["*"], "dst": ["*"], "ip": ["*"], "users": ["alice@example.com"], "via": []}],
+		"autoApprovers": {"exitNode": ["tag:exit"], "futureApprovers": {"x": ["tag:x"]}},
+	}`)
+	require.NoError(t, err)
+
+	// Fields that are dropped when the policy file is rendered are reported,
+	// including legacy names outside of the sections that they belong to.
+	assert.Equal(t, []string{"autoApprovers.futureApprovers", "grants[0].users", "ssh[0].futureField"}, unsupported)
+	assert.Equal(t, []string{"posture:latest"}, doc.SSH[0].SrcPosture)
+}
+
+func TestParsePolicyDocumentModel(t *testing.T) {
+	ctx := context.Background()
+
+	doc, _, err := parsePolicyDocument(testPolicyHuJSON)
+	require.NoError(t, err)
+
+	// The model must describe the same policy as the policy file it was read
+	// from, so that Read does not produce spurious diffs.
+	var model policyResourceModel
+	model.setDocument(doc)
+
+	var diags diag.Diagnostics
+	rendered := model.render(ctx, &diags)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, testPolicyHuJSON, rendered)
+}
+
+func TestParsePolicyDocumentModelDuplicates(t *testing.T) {
+	model := policyResourceModel{
+		Groups: []policyGroupModel{
+			{Name: policyStringValue("group:dev"), Members: policySetValue([]string{"alice@example.com"})},
+			{Name: policyStringValue("group:dev"), Members: policySetValue([]string{"bob@example.com"})},
+		},
+	}
+
+	var diags diag.Diagnostics
+	model.render(context.Background(), &diags)
+	assert.True(t, diags.HasError())
+}
+
+func TestParsePolicyDocumentModelEmptySets(t *testing.T) {
+	ctx := context.Background()
+
+	// Required sets can be empty, and are rendered as empty lists.
+	const policy = `{
+	"groups": {
+		"group:empty": []
+	},
+	"tagOwners": {
+		"tag:prod": []
+	},
+	"autoApprovers": {
+		"routes": {
+			"10.0.0.0/8": []
+		},
+		"services": {
+			"svc:web": []
+		}
+	}
+}
+`
+	doc, _, err := parsePolicyDocument(policy)
+	require.NoError(t, err)
+
+	var model policyResourceModel
+	model.setDocument(doc)
+	assert.False(t, model.Groups[0].Members.IsNull())
+	assert.False(t, model.AutoApprovers.Routes[0].Approvers.IsNull())
+	assert.False(t, model.AutoApprovers.Services[0].Approvers.IsNull())
+
+	var diags diag.Diagnostics
+	rendered := model.render(ctx, &diags)
+	require.False(t, diags.HasError(), diags)
+	assert.Equal(t, policy, rendered)
+}
+
+func TestKeepEquivalentJSON(t *testing.T) {
+	prior := types.StringValue(`{
+		// Owned by the web team.
+		"tag:web": ["group:web"],
+	}`)
+	assert.Equal(t, prior, keepEquivalentJSON(prior, types.StringValue(`{"tag:web":["group:web"]}`)))
+
+	current := types.StringValue(`{"tag:web":["group:dev"]}`)
+	assert.Equal(t, current, keepEquivalentJSON(prior, current))
+	assert.Equal(t, current, keepEquivalentJSON(types.StringNull(), current))
+}
+
+func TestProvider_TailscalePolicyLifecycle(t *testing.T) {
+	const resourceName = "tailscale_policy.test"
+
+	const testPolicyCreate = `
+		resource "tailscale_policy" "test" {
+			reset_policy_on_destroy = true
+
+			groups {
+				name    = "group:dev"
+				members = ["alice@example.com"]
+			}
+
+			tag_owners {
+				tag    = "tag:web"
+				owners = ["group:dev"]
+			}
+
+			acls {
+				src = ["group:dev"]
+				dst = ["tag:web:443"]
+			}
+		}`
+
+	const testPolicyUpdate = `
+		locals {
+			services = {
+				web = 443
+				db  = 5432
+			}
+		}
+
+		resource "tailscale_policy" "test" {
+			reset_policy_on_destroy = true
+
+			groups {
+				name    = "group:dev"
+				members = ["bob@example.com", "alice@example.com"]
+			}
+
+			dynamic "tag_owners" {
+				for_each = local.services
+				content {
+					tag    = "tag:${tag_owners.key}"
+					owners = ["group:dev"]
+				}
+			}
+
+			dynamic "grants" {
+				for_each = local.services
+				content {
+					src = ["group:dev"]
+					dst = ["tag:${grants.key}"]
+					ip  = ["tcp:${grants.value}"]
+				}
+			}
+
+			tests {
+				src    = "alice@example.com"
+				accept = ["tag:db:5432"]
+			}
+		}`
+
+	factories, server := testFakeControlProviderFactories(t)
+	checkPolicy := func(expected string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			if policy, _ := server.Policy(); policy != expected {
+				return fmt.Errorf("unexpected policy file: %s", policy)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy:             checkPolicy(fakecontrol.DefaultPolicy),
+		Steps: []resource.TestStep{
+			{
+				Config: testPolicyCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "acls.0.action", "accept"),
+					checkPolicy(`{
+	"groups": {
+		"group:dev": ["alice@example.com"]
+	},
+	"tagOwners": {
+		"tag:web": ["group:dev"]
+	},
+	"acls": [
+		{
+			"action": "accept",
+			"src":    ["group:dev"],
+			"dst":    ["tag:web:443"]
+		}
+	]
+}
+`),
+				),
+			},
+			{
+				// Changes made outside of Terraform are detected and reverted.
+				PreConfig: func() {
+					server.SetPolicy(`{"acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`)
+				},
+				Config: testPolicyUpdate,
+				Check: checkPolicy(`{
+	"groups": {
+		"group:dev": ["alice@example.com", "bob@example.com"]
+	},
+	"tagOwners": {
+		"tag:db":  ["group:dev"],
+		"tag:web": ["group:dev"]
+	},
+	"grants": [
+		{
+			"src": ["group:dev"],
+			"dst": ["tag:db"],
+			"ip":  ["tcp:5432"]
+		},
+		{
+			"src": ["group:dev"],
+			"dst": ["tag:web"],
+			"ip":  ["tcp:443"]
+		}
+	],
+	"tests": [
+		{
+			"src":    "alice@example.com",
+			"accept": ["tag:db:5432"]
+		}
+	]
+}
+`),
+			},
+			{
+				// Updates that would remove sections the resource can't
+				// represent are refused.
+				PreConfig: func() {
+					server.SetPolicy(`{"randomizeClientPort": true, "acls": [{"action": "accept", "src": ["*"], "dst": ["*:*"]}]}`)
+				},
+				Config:      testPolicyUpdate,
+				ExpectError: regexp.MustCompile("Unsupported policy file content"),
+			},
+			{
+				PreConfig: func() {
+					server.SetPolicy(`{}`)
+				},
+				Config: testPolicyUpdate,
+			},
+			{
+				// Imported policies have a new random ID, so they are matched
+				// by their contents.
+				ResourceName:                         resourceName,
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "hujson",
+				ImportStateVerifyIgnore:              []string{"id", "reset_policy_on_destroy", "overwrite_existing_content"},
+			},
+		},
+	})
+}
+
+func TestAccTailscalePolicy(t *testing.T) {
+	const resourceName = "tailscale_policy.test"
+
+	const testPolicy = `
+		resource "tailscale_policy" "test" {
+			overwrite_existing_content = true
+			reset_policy_on_destroy    = true
+
+			tag_owners {
+				tag    = "tag:example"
+				owners = ["autogroup:member"]
+			}
+
+			grants {
+				src = ["autogroup:member"]
+				dst = ["tag:example"]
+				ip  = ["*"]
+			}
+		}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "tag_owners.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "grants.0.ip.0", "*"),
+				),
+			},
+			{
+				ResourceName:                         resourceName,
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "hujson",
+				ImportStateVerifyIgnore:              []string{"id", "reset_policy_on_destroy", "overwrite_existing_content"},
+			},
+		},
+	})
+}
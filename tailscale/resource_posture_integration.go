@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -23,15 +24,19 @@ var (
 	_ resource.Resource                = &postureIntegrationResource{}
 	_ resource.ResourceWithConfigure   = &postureIntegrationResource{}
 	_ resource.ResourceWithImportState = &postureIntegrationResource{}
+	_ resource.ResourceWithIdentity    = &postureIntegrationResource{}
 )
 
 type postureIntegrationResourceModel struct {
-	ID              types.String `tfsdk:"id"`
-	PostureProvider types.String `tfsdk:"posture_provider"`
-	CloudID         types.String `tfsdk:"cloud_id"`
-	ClientID        types.String `tfsdk:"client_id"`
-	TenantID        types.String `tfsdk:"tenant_id"`
-	ClientSecret    types.String `tfsdk:"client_secret"`
+	ID                    types.String `tfsdk:"id"`
+	PostureProvider       types.String `tfsdk:"posture_provider"`
+	CloudID               types.String `tfsdk:"cloud_id"`
+	ClientID              types.String `tfsdk:"client_id"`
+	TenantID              types.String `tfsdk:"tenant_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	ClientSecretWO        types.String `tfsdk:"client_secret_wo"`
+	ClientSecretWOVersion types.Int64  `tfsdk:"client_secret_wo_version"`
+	Tailnet               types.String `tfsdk:"tailnet"`
 }
 
 func NewPostureIntegrationResource() resource.Resource {
@@ -40,7 +45,6 @@ func NewPostureIntegrationResource() resource.Resource {
 
 type postureIntegrationResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 func (p *postureIntegrationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -48,9 +52,13 @@ func (p *postureIntegrationResource) Metadata(_ context.Context, req resource.Me
 }
 
 func (p *postureIntegrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	const clientSecretDescription = "The secret (auth key, token, etc.) used to authenticate with the provider."
+	clientSecretWO, clientSecretWOVersion := writeOnlySecretAttributes("client_secret", clientSecretDescription)
+
 	resp.Schema = schema.Schema{
 		Description: "The posture_integration resource allows you to manage integrations with device posture data providers. See https://tailscale.com/kb/1288/device-posture for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed:      true,
 				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
@@ -91,14 +99,30 @@ func (p *postureIntegrationResource) Schema(_ context.Context, _ resource.Schema
 				Default:     stringdefault.StaticString(""),
 			},
 			"client_secret": schema.StringAttribute{
-				Description: "The secret (auth key, token, etc.) used to authenticate with the provider.",
-				Required:    true,
+				Description: clientSecretDescription + " Exactly one of `client_secret` or `client_secret_wo` must be set.",
+				Optional:    true,
 				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("client_secret_wo")),
+				},
 			},
+			"client_secret_wo":         clientSecretWO,
+			"client_secret_wo_version": clientSecretWOVersion,
 		},
 	}
 }
 
+// IdentitySchema defines the identity of the resource, which is also used to
+// import it.
+func (p *postureIntegrationResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("id", "The ID of the posture integration.")
+}
+
+// ImportState imports the resource by ID or by identity.
+func (p *postureIntegrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, p.providerData, "id", req, resp)
+}
+
 func (p *postureIntegrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state postureIntegrationResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -107,7 +131,12 @@ func (p *postureIntegrationResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
-	integration, err := p.Client.DevicePosture().GetIntegration(ctx, state.ID.ValueString())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: p.IdentityTailnet(state.Tailnet),
+		ID:      state.ID,
+	})...)
+
+	integration, err := p.ClientForTailnet(state.Tailnet).DevicePosture().GetIntegration(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch posture integration",
 			fmt.Sprintf("Error reading posture integration with id %q: %s", state.ID.ValueString(), err.Error()))
@@ -131,14 +160,20 @@ func (p *postureIntegrationResource) Create(ctx context.Context, req resource.Cr
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	integration, err := p.Client.DevicePosture().CreateIntegration(
+
+	clientSecret := writeOnlyStringValue(ctx, req.Config, "client_secret_wo", plan.ClientSecret, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	integration, err := p.ClientForTailnet(plan.Tailnet).DevicePosture().CreateIntegration(
 		ctx,
 		tailscale.CreatePostureIntegrationRequest{
 			Provider:     tailscale.PostureIntegrationProvider(plan.PostureProvider.ValueString()),
 			CloudID:      plan.CloudID.ValueString(),
 			ClientID:     plan.ClientID.ValueString(),
 			TenantID:     plan.TenantID.ValueString(),
-			ClientSecret: plan.ClientSecret.ValueString(),
+			ClientSecret: clientSecret.ValueString(),
 		},
 	)
 
@@ -151,6 +186,10 @@ func (p *postureIntegrationResource) Create(ctx context.Context, req resource.Cr
 	plan.ID = types.StringValue(integration.ID)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: p.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
 }
 
 func (p *postureIntegrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -161,14 +200,19 @@ func (p *postureIntegrationResource) Update(ctx context.Context, req resource.Up
 		return
 	}
 
-	_, err := p.Client.DevicePosture().UpdateIntegration(
+	clientSecret := writeOnlyStringValue(ctx, req.Config, "client_secret_wo", plan.ClientSecret, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := p.ClientForTailnet(plan.Tailnet).DevicePosture().UpdateIntegration(
 		ctx,
 		plan.ID.ValueString(),
 		tailscale.UpdatePostureIntegrationRequest{
 			CloudID:      plan.CloudID.ValueString(),
 			ClientID:     plan.ClientID.ValueString(),
 			TenantID:     plan.TenantID.ValueString(),
-			ClientSecret: plan.ClientSecret.ValueStringPointer(),
+			ClientSecret: clientSecret.ValueStringPointer(),
 		},
 	)
 	if err != nil {
@@ -179,6 +223,10 @@ func (p *postureIntegrationResource) Update(ctx context.Context, req resource.Up
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: p.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
 }
 
 func (p *postureIntegrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -189,7 +237,7 @@ func (p *postureIntegrationResource) Delete(ctx context.Context, req resource.De
 		return
 	}
 
-	err := p.Client.DevicePosture().DeleteIntegration(ctx, plan.ID.ValueString())
+	err := p.ClientForTailnet(plan.Tailnet).DevicePosture().DeleteIntegration(ctx, plan.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete posture integration",
 			fmt.Sprintf("Error deleting posture integration with id %q: %s", plan.ID.ValueString(), err.Error()))
@@ -6,10 +6,17 @@ package tailscale
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 
 	"tailscale.com/client/tailscale/v2"
 )
@@ -265,3 +272,96 @@ func TestAccTailscalePostureIntegrationMigration(t *testing.T) {
 			resource.TestCheckResourceAttr(resourceName, "client_secret", "test-secret3"),
 		))
 }
+
+func TestProvider_TailscalePostureIntegrationWriteOnly(t *testing.T) {
+	const resourceName = "tailscale_posture_integration.test_posture_integration"
+	const testPostureIntegrationWriteOnly = `
+		resource "tailscale_posture_integration" "test_posture_integration" {
+			posture_provider         = "kolide"
+			client_secret_wo         = %q
+			client_secret_wo_version = %d
+		}`
+
+	integration := tailscale.PostureIntegration{
+		ID:       "pi123",
+		Provider: tailscale.PostureIntegrationProviderKolide,
+	}
+
+	var writes []string
+	resource.Test(t, resource.TestCase{
+		IsUnitTest: true,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		PreCheck: func() {
+			testServer.HandleRequest = func(method, path string) TestResponse {
+				if method == http.MethodPost || method == http.MethodPatch {
+					writes = append(writes, method+" "+testServer.Body.String())
+				}
+				return TestResponse{Code: http.StatusOK, Body: integration}
+			}
+		},
+		ProtoV5ProviderFactories: testProviderFactories(t),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testPostureIntegrationWriteOnly, "secret1", 1),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(resourceName, tfjsonpath.New("client_secret"), knownvalue.Null()),
+					statecheck.ExpectKnownValue(resourceName, tfjsonpath.New("client_secret_wo"), knownvalue.Null()),
+					statecheck.ExpectKnownValue(resourceName, tfjsonpath.New("client_secret_wo_version"), knownvalue.Int64Exact(1)),
+				},
+				Check: func(s *terraform.State) error {
+					return assertEqual([]string{`POST {"provider":"kolide","clientSecret":"secret1"}`}, writes, "secret should be sent on create")
+				},
+			},
+			{
+				// Changing only the write-only value has no effect.
+				Config: fmt.Sprintf(testPostureIntegrationWriteOnly, "secret2", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			{
+				Config: fmt.Sprintf(testPostureIntegrationWriteOnly, "secret2", 2),
+				Check: func(s *terraform.State) error {
+					return assertEqual(`PATCH {"clientSecret":"secret2"}`, writes[len(writes)-1], "new secret should be sent when the version changes")
+				},
+			},
+		},
+	})
+}
+
+func TestProvider_TailscalePostureIntegrationSecretValidation(t *testing.T) {
+	testCases := []expectedErrorTestCase{
+		{
+			Name: "no-secret",
+			Config: `
+				resource "tailscale_posture_integration" "test_posture_integration" {
+					posture_provider = "kolide"
+				}`,
+			ExpectError: regexp.MustCompile(`No attribute specified when one \(and only one\) of`),
+		},
+		{
+			Name: "both-secrets",
+			Config: `
+				resource "tailscale_posture_integration" "test_posture_integration" {
+					posture_provider         = "kolide"
+					client_secret            = "secret"
+					client_secret_wo         = "secret"
+					client_secret_wo_version = 1
+				}`,
+			ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+		},
+		{
+			Name: "missing-version",
+			Config: `
+				resource "tailscale_posture_integration" "test_posture_integration" {
+					posture_provider = "kolide"
+					client_secret_wo = "secret"
+				}`,
+			ExpectError: regexp.MustCompile(`client_secret_wo_version.*must be specified`),
+		},
+	}
+
+	runExpectedErrorTests(t, testCases)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
@@ -27,6 +28,8 @@ var (
 	_ resource.Resource                = &serviceResource{}
 	_ resource.ResourceWithConfigure   = &serviceResource{}
 	_ resource.ResourceWithImportState = &serviceResource{}
+	_ resource.ResourceWithIdentity    = &serviceResource{}
+	_ resource.ResourceWithModifyPlan  = &serviceResource{}
 )
 
 type serviceResourceModel struct {
@@ -36,6 +39,7 @@ type serviceResourceModel struct {
 	Comment types.String `tfsdk:"comment"`
 	Ports   types.Set    `tfsdk:"ports"`
 	Tags    types.Set    `tfsdk:"tags"`
+	Tailnet types.String `tfsdk:"tailnet"`
 }
 
 // NewServiceResource returns a new service resource.
@@ -45,7 +49,6 @@ func NewServiceResource() resource.Resource {
 
 type serviceResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -58,6 +61,7 @@ func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Description: "The Service resource allows you to manage Tailscale Services in your Tailscale network. Services let you publish internal resources (like databases or web servers) as named resources in your tailnet. Services provide a stable MagicDNS name, a Tailscale virtual IP address pair, can be served by multiple nodes, and are valid access control destinations. See https://tailscale.com/docs/features/tailscale-services) for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"name": schema.StringAttribute{
 				Description: "The name of the Service. Must begin with `svc:`.",
 				Required:    true,
@@ -109,6 +113,16 @@ func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+// IdentitySchema defines the attributes that identify a Service.
+func (r *serviceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("name", "The name of the Service, e.g. 'svc:my-service'.")
+}
+
+// ImportState imports the resource by Service name or by identity.
+func (r *serviceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, r.providerData, "name", req, resp)
+}
+
 func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan serviceResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -121,7 +135,7 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	if err := r.Client.VIPServices().CreateOrUpdate(ctx, svc); err != nil {
+	if err := r.ClientForTailnet(plan.Tailnet).VIPServices().CreateOrUpdate(ctx, svc); err != nil {
 		resp.Diagnostics.AddError("Failed to create Service", err.Error())
 		return
 	}
@@ -129,7 +143,7 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 	plan.ID = plan.Name
 
 	// Re-fetch to get the 'addrs' which are computed by the API
-	createdSvc, err := r.Client.VIPServices().Get(ctx, plan.Name.ValueString())
+	createdSvc, err := r.ClientForTailnet(plan.Tailnet).VIPServices().Get(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch service for IPs", err.Error())
 		return
@@ -138,6 +152,10 @@ func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest
 	plan.Addrs = ListOfStringValue(ctx, createdSvc.Addrs, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serviceIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		Name:    plan.Name,
+	})...)
 }
 
 func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -147,7 +165,12 @@ func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	svc, err := r.Client.VIPServices().Get(ctx, state.ID.ValueString())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serviceIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		Name:    state.ID,
+	})...)
+
+	svc, err := r.ClientForTailnet(state.Tailnet).VIPServices().Get(ctx, state.ID.ValueString())
 	if err != nil {
 		if tailscale.IsNotFound(err) {
 			resp.State.RemoveResource(ctx)
@@ -157,14 +180,7 @@ func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	state.Name = types.StringValue(svc.Name)
-	state.Comment = types.StringValue(svc.Comment)
-	state.Addrs = ListOfStringValue(ctx, svc.Addrs, &resp.Diagnostics)
-	state.Ports = SetOfStringValue(ctx, svc.Ports, &resp.Diagnostics)
-	if svc.Tags == nil {
-		svc.Tags = []string{}
-	}
-	state.Tags = SetOfStringValue(ctx, svc.Tags, &resp.Diagnostics)
+	state.populateFromService(ctx, svc, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -177,24 +193,46 @@ func (r *serviceResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	svc := r.buildServiceFromResource(ctx, &plan, &resp.Diagnostics)
-	if err := r.Client.VIPServices().CreateOrUpdate(ctx, svc); err != nil {
+	if err := r.ClientForTailnet(plan.Tailnet).VIPServices().CreateOrUpdate(ctx, svc); err != nil {
 		resp.Diagnostics.AddError("Failed to update Service", err.Error())
 		return
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, serviceIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		Name:    plan.Name,
+	})...)
+}
+
+// ModifyPlan validates the planned tags against the policy file.
+func (r *serviceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.ValidateTags(ctx, req, resp, path.Root("tags"))
 }
 
 func (r *serviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state serviceResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
-	err := r.Client.VIPServices().Delete(ctx, state.ID.ValueString())
+	err := r.ClientForTailnet(state.Tailnet).VIPServices().Delete(ctx, state.ID.ValueString())
 	if err != nil && !tailscale.IsNotFound(err) {
 		resp.Diagnostics.AddError("Failed to delete Service", err.Error())
 	}
 }
 
+// populateFromService updates the model with the properties of a Service.
+func (m *serviceResourceModel) populateFromService(ctx context.Context, svc *tailscale.VIPService, diags *diag.Diagnostics) {
+	m.ID = types.StringValue(svc.Name)
+	m.Name = types.StringValue(svc.Name)
+	m.Comment = types.StringValue(svc.Comment)
+	m.Addrs = ListOfStringValue(ctx, svc.Addrs, diags)
+	m.Ports = SetOfStringValue(ctx, svc.Ports, diags)
+	if svc.Tags == nil {
+		svc.Tags = []string{}
+	}
+	m.Tags = SetOfStringValue(ctx, svc.Tags, diags)
+}
+
 func (r *serviceResource) buildServiceFromResource(ctx context.Context, model *serviceResourceModel, diags *diag.Diagnostics) tailscale.VIPService {
 	var ports, tags, addrs []string
 	diags.Append(model.Ports.ElementsAs(ctx, &ports, false)...)
@@ -50,6 +50,47 @@ func TestProvider_TailscaleService(t *testing.T) {
 	})
 }
 
+func TestProvider_TailscaleServiceLifecycle(t *testing.T) {
+	const resourceName = "tailscale_service.test_service"
+
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"], "tag:api": ["autogroup:admin"]}}`)
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, ok := server.Service("svc:test-service"); ok {
+				return fmt.Errorf("service still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testService,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "addrs.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "a test Service"),
+				),
+			},
+			{
+				Config: testServiceUpdate,
+				Check: func(s *terraform.State) error {
+					svc, _ := server.Service("svc:test-service")
+					if svc.Comment != "an updated test Service" || len(svc.Ports) != 2 || len(svc.Tags) != 2 {
+						return fmt.Errorf("service was not updated: %+v", svc)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccTailscaleService(t *testing.T) {
 	const resourceName = "tailscale_service.test_service"
 
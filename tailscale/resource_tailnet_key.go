@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -32,6 +33,7 @@ var (
 	_ resource.ResourceWithConfigure   = &tailnetKeyResource{}
 	_ resource.ResourceWithModifyPlan  = &tailnetKeyResource{}
 	_ resource.ResourceWithImportState = &tailnetKeyResource{}
+	_ resource.ResourceWithIdentity    = &tailnetKeyResource{}
 )
 
 type tailnetKeyResourceModel struct {
@@ -48,6 +50,7 @@ type tailnetKeyResourceModel struct {
 	Invalid           types.Bool   `tfsdk:"invalid"`
 	RecreateIfInvalid types.String `tfsdk:"recreate_if_invalid"`
 	UserID            types.String `tfsdk:"user_id"`
+	Tailnet           types.String `tfsdk:"tailnet"`
 }
 
 func NewTailnetKeyResource() resource.Resource {
@@ -56,7 +59,6 @@ func NewTailnetKeyResource() resource.Resource {
 
 type tailnetKeyResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 func (t *tailnetKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -67,6 +69,7 @@ func (t *tailnetKeyResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "The tailnet_key resource allows you to create pre-authentication keys that can register new nodes without needing to sign in via a web browser. See https://tailscale.com/kb/1085/auth-keys for more information",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed:      true,
 				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
@@ -158,6 +161,15 @@ func (t *tailnetKeyResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	}
 }
 
+func (t *tailnetKeyResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("id", "The ID of the key.")
+}
+
+// ImportState imports the resource by key ID or by identity.
+func (t *tailnetKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, t.providerData, "id", req, resp)
+}
+
 func (t *tailnetKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan tailnetKeyResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -181,7 +193,7 @@ func (t *tailnetKeyResource) Create(ctx context.Context, req resource.CreateRequ
 	createKeyRequest.ExpirySeconds = plan.Expiry.ValueInt64()
 	createKeyRequest.Description = plan.Description.ValueString()
 
-	key, err := t.Client.Keys().CreateAuthKey(ctx, createKeyRequest)
+	key, err := t.ClientForTailnet(plan.Tailnet).Keys().CreateAuthKey(ctx, createKeyRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create key", fmt.Sprintf("Error creating tailnet key: %s", err.Error()))
 		return
@@ -196,6 +208,10 @@ func (t *tailnetKeyResource) Create(ctx context.Context, req resource.CreateRequ
 	plan.UserID = types.StringValue(key.UserID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: t.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
 }
 
 func (t *tailnetKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -206,7 +222,7 @@ func (t *tailnetKeyResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	err := t.Client.Keys().Delete(ctx, state.ID.ValueString())
+	err := t.ClientForTailnet(state.Tailnet).Keys().Delete(ctx, state.ID.ValueString())
 	// Single-use keys may no longer be here, so we can ignore deletions that fail due to not-found errors.
 	if err != nil && !tailscale.IsNotFound(err) {
 		resp.Diagnostics.AddError("Failed to delete key", fmt.Sprintf("Error deleting tailnet key with id %q: %s", state.ID, err.Error()))
@@ -236,7 +252,12 @@ func (t *tailnetKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	key, err := t.Client.Keys().Get(ctx, state.ID.ValueString())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: t.IdentityTailnet(state.Tailnet),
+		ID:      state.ID,
+	})...)
+
+	key, err := t.ClientForTailnet(state.Tailnet).Keys().Get(ctx, state.ID.ValueString())
 	if tailscale.IsNotFound(err) {
 		state.Invalid = types.BoolValue(true)
 		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -244,8 +265,6 @@ func (t *tailnetKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 	} else if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch key", fmt.Sprintf("Error reading tailnet key with id %q: %s", state.ID, err.Error()))
 		return
-	} else {
-		state.Invalid = types.BoolValue(false)
 	}
 
 	if key.KeyType != "auth" {
@@ -253,32 +272,32 @@ func (t *tailnetKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	if key.Invalid {
-		state.Invalid = types.BoolValue(true)
-	}
-
-	state.ID = types.StringValue(key.ID)
-	state.Reusable = types.BoolValue(key.Capabilities.Devices.Create.Reusable)
-	state.Ephemeral = types.BoolValue(key.Capabilities.Devices.Create.Ephemeral)
-	if key.Capabilities.Devices.Create.Tags == nil {
-		key.Capabilities.Devices.Create.Tags = []string{}
-	}
-	tags, diags := types.SetValueFrom(ctx, types.StringType, key.Capabilities.Devices.Create.Tags)
-	resp.Diagnostics.Append(diags...)
+	state.populateFromKey(ctx, key, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	state.Tags = tags
-	state.Preauthorized = types.BoolValue(key.Capabilities.Devices.Create.Preauthorized)
-	state.Expiry = types.Int64PointerValue((*int64)(key.ExpirySeconds))
-	state.CreatedAt = types.StringValue(key.Created.Format(time.RFC3339))
-	state.ExpiresAt = types.StringValue(key.Expires.Format(time.RFC3339))
-	state.Description = types.StringValue(key.Description)
-	state.UserID = types.StringValue(key.UserID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
+// populateFromKey updates the model with the properties of an auth key.
+func (m *tailnetKeyResourceModel) populateFromKey(ctx context.Context, key *tailscale.Key, diags *diag.Diagnostics) {
+	m.ID = types.StringValue(key.ID)
+	m.Invalid = types.BoolValue(key.Invalid)
+	m.Reusable = types.BoolValue(key.Capabilities.Devices.Create.Reusable)
+	m.Ephemeral = types.BoolValue(key.Capabilities.Devices.Create.Ephemeral)
+	if key.Capabilities.Devices.Create.Tags == nil {
+		key.Capabilities.Devices.Create.Tags = []string{}
+	}
+	m.Tags = SetOfStringValue(ctx, key.Capabilities.Devices.Create.Tags, diags)
+	m.Preauthorized = types.BoolValue(key.Capabilities.Devices.Create.Preauthorized)
+	m.Expiry = types.Int64PointerValue((*int64)(key.ExpirySeconds))
+	m.CreatedAt = types.StringValue(key.Created.Format(time.RFC3339))
+	m.ExpiresAt = types.StringValue(key.Expires.Format(time.RFC3339))
+	m.Description = types.StringValue(key.Description)
+	m.UserID = types.StringValue(key.UserID)
+}
+
 func (t *tailnetKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var state tailnetKeyResourceModel
 	diags := req.Plan.Get(ctx, &state)
@@ -287,7 +306,7 @@ func (t *tailnetKeyResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	key, err := t.Client.Keys().Get(ctx, state.ID.ValueString())
+	key, err := t.ClientForTailnet(state.Tailnet).Keys().Get(ctx, state.ID.ValueString())
 	if tailscale.IsNotFound(err) {
 		state.Invalid = types.BoolValue(true)
 	} else if err != nil {
@@ -312,9 +331,15 @@ func (t *tailnetKeyResource) Update(ctx context.Context, req resource.UpdateRequ
 	state.UserID = types.StringValue(key.UserID)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: t.IdentityTailnet(state.Tailnet),
+		ID:      state.ID,
+	})...)
 }
 
 func (t *tailnetKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	t.ValidateTags(ctx, req, resp, path.Root("tags"))
+
 	// Do not replace on resource creation.
 	if req.State.Raw.IsNull() {
 		return
@@ -25,6 +25,7 @@ var (
 	_ resource.Resource                = &tailnetSettingsResource{}
 	_ resource.ResourceWithConfigure   = &tailnetSettingsResource{}
 	_ resource.ResourceWithImportState = &tailnetSettingsResource{}
+	_ resource.ResourceWithIdentity    = &tailnetSettingsResource{}
 	_ resource.ResourceWithModifyPlan  = &tailnetSettingsResource{}
 )
 
@@ -41,6 +42,7 @@ type tailnetSettingsResourceModel struct {
 	RegionalRoutingOn                     types.Bool   `tfsdk:"regional_routing_on"`
 	PostureIdentityCollectionOn           types.Bool   `tfsdk:"posture_identity_collection_on"`
 	HTTPSEnabled                          types.Bool   `tfsdk:"https_enabled"`
+	Tailnet                               types.String `tfsdk:"tailnet"`
 }
 
 func NewTailnetSettingsResource() resource.Resource {
@@ -48,8 +50,7 @@ func NewTailnetSettingsResource() resource.Resource {
 }
 
 type tailnetSettingsResource struct {
-	ResourceBase
-	ResourceImportedByID
+	ResourceIdentifiedByTailnet
 }
 
 func (s *tailnetSettingsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -60,6 +61,7 @@ func (s *tailnetSettingsResource) Schema(_ context.Context, _ resource.SchemaReq
 	resp.Schema = schema.Schema{
 		Description: "The tailnet_settings resource allows you to configure settings for your tailnet. See https://tailscale.com/api#tag/tailnetsettings for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed:      true,
 				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
@@ -177,6 +179,8 @@ func (s *tailnetSettingsResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
+	resp.Diagnostics.Append(s.SetIdentity(ctx, resp.Identity, state.Tailnet)...)
+
 	err := s.readSettings(ctx, &state)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch tailnet settings", fmt.Sprintf("Error reading tailnet settings: %s", err))
@@ -188,7 +192,7 @@ func (s *tailnetSettingsResource) Read(ctx context.Context, req resource.ReadReq
 }
 
 func (s *tailnetSettingsResource) readSettings(ctx context.Context, state *tailnetSettingsResourceModel) error {
-	settings, err := s.Client.TailnetSettings().Get(ctx)
+	settings, err := s.ClientForTailnet(state.Tailnet).TailnetSettings().Get(ctx)
 	if err != nil {
 		return err
 	}
@@ -247,6 +251,7 @@ func (s *tailnetSettingsResource) Create(ctx context.Context, req resource.Creat
 	plan.ID = types.StringValue(createUUID())
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(s.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (s *tailnetSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -276,6 +281,7 @@ func (s *tailnetSettingsResource) Update(ctx context.Context, req resource.Updat
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(s.SetIdentity(ctx, resp.Identity, plan.Tailnet)...)
 }
 
 func (s *tailnetSettingsResource) resourceTailnetSettingsDoUpdate(ctx context.Context, plan tailnetSettingsResourceModel, state tailnetSettingsResourceModel) error {
@@ -320,7 +326,7 @@ func (s *tailnetSettingsResource) resourceTailnetSettingsDoUpdate(ctx context.Co
 		HTTPSEnabled:                           boolIfDiff(plan.HTTPSEnabled, state.HTTPSEnabled),
 	}
 
-	return s.Client.TailnetSettings().Update(ctx, settingsRequest)
+	return s.ClientForTailnet(plan.Tailnet).TailnetSettings().Update(ctx, settingsRequest)
 }
 
 func (s *tailnetSettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
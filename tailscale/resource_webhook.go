@@ -23,6 +23,7 @@ var (
 	_ resource.Resource                = &webhookResource{}
 	_ resource.ResourceWithConfigure   = &webhookResource{}
 	_ resource.ResourceWithImportState = &webhookResource{}
+	_ resource.ResourceWithIdentity    = &webhookResource{}
 )
 
 // NewWebhookResource returns a new webhook resource.
@@ -32,7 +33,6 @@ func NewWebhookResource() resource.Resource {
 
 type webhookResource struct {
 	ResourceBase
-	ResourceImportedByID
 }
 
 // Metadata defines the resource name as it appears in Terraform configurations.
@@ -45,6 +45,7 @@ func (r *webhookResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Description: "The webhook resource allows you to configure webhook endpoints for your Tailscale network. See https://tailscale.com/kb/1213/webhooks for more information.",
 		Attributes: map[string]schema.Attribute{
+			"tailnet": tailnetResourceAttribute(),
 			"id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -115,12 +116,24 @@ func (r *webhookResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+// IdentitySchema defines the identity of the resource, which is also used to
+// import it.
+func (r *webhookResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identitySchema("id", "The ID of the webhook endpoint.")
+}
+
+// ImportState imports the resource by ID or by identity.
+func (r *webhookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStateWithIdentity(ctx, r.providerData, "id", req, resp)
+}
+
 type webhookResourceData struct {
 	ID            types.String `tfsdk:"id"`
 	Secret        types.String `tfsdk:"secret"`
 	EndpointURL   types.String `tfsdk:"endpoint_url"`
 	ProviderType  types.String `tfsdk:"provider_type"`
 	Subscriptions types.Set    `tfsdk:"subscriptions"`
+	Tailnet       types.String `tfsdk:"tailnet"`
 }
 
 // requestSubscriptions gets a list of subscriptions in a type that
@@ -158,7 +171,7 @@ func (r *webhookResource) Create(ctx context.Context, req resource.CreateRequest
 		Subscriptions: requestSubscriptions,
 	}
 
-	webhook, err := r.Client.Webhooks().Create(ctx, request)
+	webhook, err := r.ClientForTailnet(plan.Tailnet).Webhooks().Create(ctx, request)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create webhook", err.Error())
 		return
@@ -175,6 +188,10 @@ func (r *webhookResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
 }
 
 func (r *webhookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -185,7 +202,12 @@ func (r *webhookResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	webhook, err := r.Client.Webhooks().Get(ctx, state.ID.ValueString())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(state.Tailnet),
+		ID:      state.ID,
+	})...)
+
+	webhook, err := r.ClientForTailnet(state.Tailnet).Webhooks().Get(ctx, state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Error fetching webhook", err.Error())
 		return
@@ -216,7 +238,7 @@ func (r *webhookResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	_, err := r.Client.Webhooks().Update(ctx, endpointID, requestSubscriptions)
+	_, err := r.ClientForTailnet(plan.Tailnet).Webhooks().Update(ctx, endpointID, requestSubscriptions)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update webhook", err.Error())
 		return
@@ -224,6 +246,10 @@ func (r *webhookResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, idIdentityModel{
+		Tailnet: r.IdentityTailnet(plan.Tailnet),
+		ID:      plan.ID,
+	})...)
 }
 
 func (r *webhookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -235,7 +261,7 @@ func (r *webhookResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	endpointID := state.ID.ValueString()
 
-	if err := r.Client.Webhooks().Delete(ctx, endpointID); err != nil {
+	if err := r.ClientForTailnet(state.Tailnet).Webhooks().Delete(ctx, endpointID); err != nil {
 		resp.Diagnostics.AddError("Failed to delete webhook", err.Error())
 		return
 	}
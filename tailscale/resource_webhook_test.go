@@ -55,6 +55,58 @@ func TestProvider_TailscaleWebhook(t *testing.T) {
 	})
 }
 
+func TestProvider_TailscaleWebhookLifecycle(t *testing.T) {
+	const resourceName = "tailscale_webhook.test_webhook"
+
+	factories, server := testFakeControlProviderFactories(t)
+	checkSubscriptions := func(expected ...tailscale.WebhookSubscriptionType) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			webhook, ok := server.Webhook(s.RootModule().Resources[resourceName].Primary.ID)
+			if !ok {
+				return fmt.Errorf("webhook not found")
+			}
+			slices.Sort(expected)
+			slices.Sort(webhook.Subscriptions)
+			if !slices.Equal(webhook.Subscriptions, expected) {
+				return fmt.Errorf("bad webhook.subscriptions: %v", webhook.Subscriptions)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, rs := range s.RootModule().Resources {
+				if _, ok := server.Webhook(rs.Primary.ID); ok {
+					return fmt.Errorf("webhook %s still exists", rs.Primary.ID)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testWebhookCreate,
+				Check: resource.ComposeTestCheckFunc(
+					checkSubscriptions(tailscale.WebhookUserNeedsApproval, tailscale.WebhookNodeCreated),
+					resource.TestCheckResourceAttrSet(resourceName, "secret"),
+				),
+			},
+			{
+				Config: testWebhookUpdate,
+				Check:  checkSubscriptions(tailscale.WebhookNodeCreated, tailscale.WebhookUserSuspended, tailscale.WebhookUserRoleUpdated),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"secret"},
+			},
+		},
+	})
+}
+
 func TestAccTailscaleWebhook(t *testing.T) {
 	const resourceName = "tailscale_webhook.test_webhook"
 
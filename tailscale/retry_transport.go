@@ -0,0 +1,210 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryMaxWait = 30 * time.Second
+
+	// retryBaseWait is the wait before the first retry, which is doubled for
+	// each subsequent attempt up to the configured maximum wait.
+	retryBaseWait = 500 * time.Millisecond
+)
+
+// newHTTPClient returns the HTTP client used for all requests to the API.
+func newHTTPClient(maxRetries int, maxWait time.Duration, maxConcurrent int) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	// The client has no overall timeout, as that would include the time spent
+	// waiting between retries. Instead, give up on attempts that get no response.
+	base.ResponseHeaderTimeout = time.Minute
+
+	return &http.Client{
+		Transport: newRetryTransport(base, maxRetries, maxWait, maxConcurrent),
+	}
+}
+
+// retryTransport is an [http.RoundTripper] that retries requests that were
+// rate limited or failed with a transient server error, using exponential
+// backoff with jitter. It optionally limits the number of requests in flight.
+type retryTransport struct {
+	base http.RoundTripper
+
+	maxRetries int
+	maxWait    time.Duration
+
+	// sem limits the number of concurrent requests, if set.
+	sem chan struct{}
+
+	// wait pauses between attempts. It is replaced in tests.
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// newRetryTransport returns a [retryTransport] wrapping base. A non-positive
+// maxConcurrent does not limit the number of concurrent requests.
+func newRetryTransport(base http.RoundTripper, maxRetries int, maxWait time.Duration, maxConcurrent int) *retryTransport {
+	t := &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		maxWait:    maxWait,
+		wait:       sleepContext,
+	}
+	if maxConcurrent > 0 {
+		t.sem = make(chan struct{}, maxConcurrent)
+	}
+	return t
+}
+
+// RoundTrip sends the request, retrying it when it is safe to do so.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			// RoundTrippers must not modify the request, so each retry
+			// sends a copy with a fresh body.
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.roundTrip(attemptReq)
+		if attempt >= t.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		logFields := map[string]any{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if resp != nil {
+			logFields["status"] = resp.StatusCode
+		} else {
+			logFields["error"] = err.Error()
+		}
+		ctx := newAPILogContext(req.Context())
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "Retrying Tailscale API request", logFields)
+
+		if resp != nil {
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.wait(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTrip sends a single attempt, waiting for a free slot if the number of
+// concurrent requests is limited.
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+			defer func() { <-t.sem }()
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// backoff returns how long to wait before the next attempt. The Retry-After
+// header takes precedence over the exponential backoff, but neither will
+// exceed the configured maximum wait.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(d, t.maxWait)
+		}
+	}
+
+	d := min(retryBaseWait<<attempt, t.maxWait)
+	if d <= 0 {
+		return 0
+	}
+	// Wait somewhere between half and all of the backoff, so that
+	// concurrent requests that fail together don't retry together.
+	return d/2 + rand.N(d/2+1)
+}
+
+// shouldRetry reports whether a request can be safely retried. Rate limited
+// requests were never processed, so they are always retried. Server errors and
+// network errors are only retried for idempotent requests.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// isIdempotent reports whether requests with the given method are safe to
+// repeat, as defined in RFC 9110 section 9.2.2.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d, returning early with an error if ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"tailscale.com/client/tailscale/v2"
+)
+
+// newRetryTestClient returns a client connected to a new [TestServer] that
+// uses a [retryTransport] which records the waits between attempts instead
+// of sleeping.
+func newRetryTestClient(t *testing.T, maxRetries int, maxWait time.Duration) (*tailscale.Client, *TestServer, *[]time.Duration) {
+	t.Helper()
+
+	baseURL, server := NewTestHarness(t)
+	parsedBaseURL, err := url.Parse(baseURL)
+	assert.NoError(t, err)
+
+	var waits []time.Duration
+	transport := newRetryTransport(http.DefaultTransport, maxRetries, maxWait, 0)
+	transport.wait = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	client := &tailscale.Client{
+		BaseURL: parsedBaseURL,
+		APIKey:  "api_123",
+		HTTP:    &http.Client{Transport: transport},
+	}
+	return client, server, &waits
+}
+
+func TestRetryTransport_RetriesRateLimitedRequests(t *testing.T) {
+	client, server, waits := newRetryTestClient(t, 3, 30*time.Second)
+
+	var methods []string
+	responses := []TestResponse{
+		{Code: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"2"}}},
+		{Code: http.StatusOK},
+	}
+	server.HandleRequest = func(method, path string) TestResponse {
+		resp := responses[min(len(methods), len(responses)-1)]
+		methods = append(methods, method)
+		return resp
+	}
+
+	err := client.Devices().SetTags(context.Background(), "123", []string{"tag:server"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{http.MethodPost, http.MethodPost}, methods, "rate limited POST should be retried")
+	assert.Equal(t, []time.Duration{2 * time.Second}, *waits, "Retry-After should be honoured")
+	assert.JSONEq(t, `{"tags":["tag:server"]}`, server.Body.String(), "body should be replayed")
+}
+
+func TestRetryTransport_RetryAfterIsCapped(t *testing.T) {
+	client, server, waits := newRetryTestClient(t, 1, 5*time.Second)
+	server.SetResponses([]TestResponse{
+		{Code: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"120"}}},
+		{Code: http.StatusOK, Body: tailscale.Device{ID: "123"}},
+	})
+
+	_, err := client.Devices().Get(context.Background(), "123")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{5 * time.Second}, *waits)
+}
+
+func TestRetryTransport_RetriesServerErrorsForIdempotentRequests(t *testing.T) {
+	client, server, waits := newRetryTestClient(t, 2, 30*time.Second)
+	server.SetResponses([]TestResponse{
+		{Code: http.StatusServiceUnavailable, Body: map[string]string{"message": "unavailable"}},
+	})
+
+	_, err := client.Devices().Get(context.Background(), "123")
+	assert.ErrorContains(t, err, "unavailable (503)")
+	assert.Equal(t, 3, server.calls, "should make the initial attempt and 2 retries")
+	assert.Len(t, *waits, 2)
+	for i, wait := range *waits {
+		backoff := retryBaseWait << i
+		assert.GreaterOrEqual(t, wait, backoff/2)
+		assert.LessOrEqual(t, wait, backoff)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryServerErrorsForUnsafeRequests(t *testing.T) {
+	client, server, waits := newRetryTestClient(t, 3, 30*time.Second)
+	server.SetResponses([]TestResponse{
+		{Code: http.StatusInternalServerError, Body: map[string]string{"message": "oh no"}},
+		{Code: http.StatusOK},
+	})
+
+	err := client.Devices().SetAuthorized(context.Background(), "123", true)
+	assert.ErrorContains(t, err, "oh no (500)")
+	assert.Equal(t, 1, server.calls)
+	assert.Empty(t, *waits)
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	client, server, _ := newRetryTestClient(t, 3, 30*time.Second)
+	server.SetResponses([]TestResponse{
+		{Code: http.StatusNotFound, Body: map[string]string{"message": "not found"}},
+	})
+
+	_, err := client.Devices().Get(context.Background(), "123")
+	assert.True(t, tailscale.IsNotFound(err))
+	assert.Equal(t, 1, server.calls)
+}
+
+func TestRetryTransport_LimitsConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		n := inFlight.Add(1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+	transport := newRetryTransport(base, 0, time.Second, 2)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+			assert.NoError(t, err)
+			_, err = transport.RoundTrip(req)
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), maxInFlight.Load())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, d, float64(2*time.Second))
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestProvider_RetryConfigValidation(t *testing.T) {
+	testCases := []expectedErrorTestCase{
+		{
+			Name: "negative-max-retries",
+			Config: `
+				provider "tailscale" {
+					max_retries = -1
+				}
+
+				data "tailscale_acl" "acl" {}`,
+			ExpectError: regexp.MustCompile(`Attribute max_retries value must be at least 0`),
+		},
+		{
+			Name: "invalid-retry-max-wait",
+			Config: `
+				provider "tailscale" {
+					retry_max_wait = "soon"
+				}
+
+				data "tailscale_acl" "acl" {}`,
+			ExpectError: regexp.MustCompile(`unable to parse value as a duration, got: soon`),
+		},
+		{
+			Name: "zero-max-concurrent-requests",
+			Config: `
+				provider "tailscale" {
+					max_concurrent_requests = 0
+				}
+
+				data "tailscale_acl" "acl" {}`,
+			ExpectError: regexp.MustCompile(`Attribute max_concurrent_requests value must be at least 1`),
+		},
+	}
+
+	runExpectedErrorTests(t, testCases)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"tailscale.com/client/tailscale/v2"
+)
+
+// tagPolicy is the tagOwners section of a tailnet's policy file, which tags are
+// validated against during plan. The policy file is fetched at most once per
+// run, the first time a tag is validated.
+type tagPolicy struct {
+	once      sync.Once
+	tagOwners map[string][]string
+	err       error
+
+	// planned is true if a resource plans to change the policy file in this
+	// run, in which case tags are validated against plannedTagOwners instead.
+	// plannedTagOwners is nil if the planned policy file isn't known.
+	planned          bool
+	plannedTagOwners map[string][]string
+}
+
+// tagPolicy returns the cached tagPolicy for the given tailnet.
+func (p *providerData) tagPolicy(tailnet string) *tagPolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tagPolicies == nil {
+		p.tagPolicies = make(map[string]*tagPolicy)
+	}
+	tp, ok := p.tagPolicies[tailnet]
+	if !ok {
+		tp = &tagPolicy{}
+		p.tagPolicies[tailnet] = tp
+	}
+	return tp
+}
+
+// planPolicyChange records that a resource plans to change the policy file of
+// the client's tailnet to policy, or to a policy file that isn't known yet if
+// policy is empty. If more than one resource plans to change the policy file,
+// the result isn't known.
+func (p *providerData) planPolicyChange(client *tailscale.Client, policy string) {
+	tp := p.tagPolicy(client.Tailnet)
+
+	var tagOwners map[string][]string
+	if policy != "" {
+		if doc, _, err := parsePolicyDocument(policy); err == nil {
+			tagOwners = doc.TagOwners
+			if tagOwners == nil {
+				tagOwners = make(map[string][]string)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if tp.planned {
+		tagOwners = nil
+	}
+	tp.planned = true
+	tp.plannedTagOwners = tagOwners
+}
+
+// tagOwners returns the tagOwners section of the policy file of the client's
+// tailnet, or of the policy file that a resource plans to replace it with. It
+// returns false if the tags can't be validated, because the policy file can't
+// be read or will be changed in a way that isn't known yet.
+//
+// A planned policy file is only known once its resource has been planned, so
+// resources that use tags added by it must depend on it; the provider can't
+// tell that a resource that changes the policy file is still to be planned.
+func (p *providerData) tagOwners(ctx context.Context, client *tailscale.Client) (map[string][]string, bool) {
+	tp := p.tagPolicy(client.Tailnet)
+
+	p.mu.Lock()
+	planned, plannedTagOwners := tp.planned, tp.plannedTagOwners
+	p.mu.Unlock()
+	if planned {
+		return plannedTagOwners, plannedTagOwners != nil
+	}
+
+	tp.once.Do(func() {
+		var acl *tailscale.RawACL
+		acl, tp.err = client.PolicyFile().Raw(ctx)
+		if tp.err != nil {
+			return
+		}
+
+		var doc *policyDocument
+		doc, _, tp.err = parsePolicyDocument(acl.HuJSON)
+		if tp.err == nil {
+			tp.tagOwners = doc.TagOwners
+		}
+	})
+	if tp.err != nil {
+		// The provider's credentials may not be allowed to read the policy
+		// file, in which case the API will still reject unknown tags.
+		tflog.Warn(ctx, "Unable to read policy file to validate tags", map[string]any{
+			"tailnet": client.Tailnet,
+			"error":   tp.err.Error(),
+		})
+		return nil, false
+	}
+	return tp.tagOwners, true
+}
+
+// PlanPolicyChange records that the resource plans to change the policy file
+// of the given tailnet to policy, so that tags of other resources are
+// validated against the planned policy file rather than the current one. An
+// unknown policy means tags can't be validated.
+func (d *ResourceBase) PlanPolicyChange(tailnet, policy types.String) {
+	if d.providerData == nil {
+		return
+	}
+	d.providerData.planPolicyChange(d.ClientForTailnet(tailnet), policy.ValueString())
+}
+
+// ValidateTags checks during plan that the tags in the set attribute at p are
+// declared in the tagOwners section of the policy file of the resource's
+// tailnet, so that unknown tags are reported before apply rather than
+// rejected by the API, possibly halfway through an apply. Tags that are
+// already in state are not validated again.
+//
+// Validation is skipped when the provider's `validate_tags` setting is false,
+// or the policy file can't be read.
+func (d *ResourceBase) ValidateTags(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, p path.Path) {
+	// Nothing to validate when destroying or before the provider is configured.
+	if req.Plan.Raw.IsNull() || d.providerData == nil || !d.providerData.validateTags {
+		return
+	}
+
+	var planned types.Set
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, p, &planned)...)
+	if resp.Diagnostics.HasError() || planned.IsNull() || planned.IsUnknown() {
+		return
+	}
+
+	existing := make(map[string]bool)
+	if !req.State.Raw.IsNull() {
+		var state types.Set
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &state)...)
+		for _, v := range state.Elements() {
+			if tag, ok := v.(types.String); ok {
+				existing[tag.ValueString()] = true
+			}
+		}
+	}
+
+	var tags []string
+	for _, v := range planned.Elements() {
+		tag, ok := v.(types.String)
+		if !ok || tag.IsUnknown() || tag.IsNull() || existing[tag.ValueString()] {
+			continue
+		}
+		tags = append(tags, tag.ValueString())
+	}
+	d.checkTags(ctx, resp, p, tags)
+}
+
+// ValidateTag is like [ResourceBase.ValidateTags], for a resource with a
+// single tag in the string attribute at p.
+func (d *ResourceBase) ValidateTag(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, p path.Path) {
+	// Nothing to validate when destroying or before the provider is configured.
+	if req.Plan.Raw.IsNull() || d.providerData == nil || !d.providerData.validateTags {
+		return
+	}
+
+	var planned, existing types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, p, &planned)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &existing)...)
+	}
+	if resp.Diagnostics.HasError() || planned.IsNull() || planned.IsUnknown() || planned.Equal(existing) {
+		return
+	}
+	d.checkTags(ctx, resp, p, []string{planned.ValueString()})
+}
+
+// checkTags adds an error to the attribute at p for each of tags that isn't
+// declared in the policy file of the planned tailnet.
+func (d *ResourceBase) checkTags(ctx context.Context, resp *resource.ModifyPlanResponse, p path.Path, tags []string) {
+	var tailnet types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("tailnet"), &tailnet)...)
+	if resp.Diagnostics.HasError() || tailnet.IsUnknown() || len(tags) == 0 {
+		return
+	}
+
+	tagOwners, ok := d.providerData.tagOwners(ctx, d.ClientForTailnet(tailnet))
+	if !ok {
+		return
+	}
+	for _, tag := range tags {
+		if _, ok := tagOwners[tag]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				p,
+				"Unknown tag",
+				fmt.Sprintf("The tag %q is not declared in the tagOwners section of the policy file. "+
+					"If it is added to the policy file by another resource, make this resource depend on it. "+
+					"Set validate_tags = false in the provider configuration to disable this check.", tag),
+			)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) David Bond, Tailscale Inc, & Contributors
+// SPDX-License-Identifier: MIT
+
+package tailscale
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tailscale.com/client/tailscale/v2"
+
+	"github.com/tailscale/terraform-provider-tailscale/internal/fakecontrol"
+)
+
+func TestProviderDataTagOwners(t *testing.T) {
+	ctx := context.Background()
+	server := fakecontrol.NewServer(t)
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"]}}`)
+
+	u, _ := url.Parse(server.URL())
+	client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+	data := &providerData{Client: &client, validateTags: true}
+
+	tagOwners, ok := data.tagOwners(ctx, data.Client)
+	require.True(t, ok)
+	assert.Contains(t, tagOwners, "tag:web")
+
+	// The policy file is only read once per run.
+	server.SetPolicy(`{"tagOwners": {"tag:db": ["autogroup:admin"]}}`)
+	tagOwners, _ = data.tagOwners(ctx, data.Client)
+	assert.Contains(t, tagOwners, "tag:web")
+
+	// Tags are validated against a planned policy file, unless it isn't known.
+	data.planPolicyChange(data.Client, `{"tagOwners": {"tag:prod": ["autogroup:admin"]}}`)
+	tagOwners, ok = data.tagOwners(ctx, data.Client)
+	require.True(t, ok)
+	assert.Equal(t, map[string][]string{"tag:prod": {"autogroup:admin"}}, tagOwners)
+
+	data.planPolicyChange(data.Client, `{"tagOwners": {"tag:db": ["autogroup:admin"]}}`)
+	_, ok = data.tagOwners(ctx, data.Client)
+	assert.False(t, ok, "expected tags not to be validated after two planned changes")
+
+	// Tags aren't validated if the policy file can't be read.
+	other := data.ClientForTailnet("example.com")
+	server.SetPolicy("not a policy file")
+	_, ok = data.tagOwners(ctx, other)
+	assert.False(t, ok)
+}
+
+func TestProviderDataTagOwnersPlanOrder(t *testing.T) {
+	ctx := context.Background()
+	server := fakecontrol.NewServer(t)
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"]}}`)
+	const planned = `{"tagOwners": {"tag:web": ["autogroup:admin"], "tag:db": ["autogroup:admin"]}}`
+
+	u, _ := url.Parse(server.URL())
+	newData := func() *providerData {
+		client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+		return &providerData{Client: &client, validateTags: true}
+	}
+
+	// A resource planned after the policy file change, as it is when it
+	// depends on the resource that changes the policy file, is validated
+	// against the planned policy file.
+	data := newData()
+	data.planPolicyChange(data.Client, planned)
+	tagOwners, ok := data.tagOwners(ctx, data.Client)
+	require.True(t, ok)
+	assert.Contains(t, tagOwners, "tag:db")
+
+	// A resource planned before it is validated against the current policy
+	// file, which is why depends_on is required.
+	data = newData()
+	tagOwners, ok = data.tagOwners(ctx, data.Client)
+	require.True(t, ok)
+	assert.NotContains(t, tagOwners, "tag:db")
+	data.planPolicyChange(data.Client, planned)
+	tagOwners, _ = data.tagOwners(ctx, data.Client)
+	assert.Contains(t, tagOwners, "tag:db")
+}
+
+func TestProvider_TailscaleTagValidation(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+	server.SetPolicy(`{"tagOwners": {"tag:web": ["autogroup:admin"]}}`)
+	device := server.AddDevice(tailscale.Device{Hostname: "web"})
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "tailscale_tailnet_key" "test" {
+						tags = ["tag:web", "tag:unknown"]
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Unknown tag`),
+			},
+			{
+				// Tags added to the policy file in the same run are validated
+				// against the planned policy file.
+				Config: `
+					resource "tailscale_acl" "test" {
+						overwrite_existing_content = true
+						acl = jsonencode({
+							tagOwners = {
+								"tag:web" = ["autogroup:admin"]
+								"tag:db"  = ["autogroup:admin"]
+							}
+						})
+					}
+
+					resource "tailscale_device_tags" "test" {
+						device_id  = "` + device.NodeID + `"
+						tags       = ["tag:db"]
+						depends_on = [tailscale_acl.test]
+					}`,
+			},
+			{
+				// Resources planned before the policy file change are
+				// validated against the current policy file.
+				Config: `
+					resource "tailscale_acl" "test" {
+						overwrite_existing_content = true
+						acl = jsonencode({
+							tagOwners = {
+								"tag:web" = ["autogroup:admin"]
+								"tag:db"  = ["autogroup:admin"]
+								"tag:new" = ["autogroup:admin"]
+							}
+						})
+						depends_on = [tailscale_tailnet_key.test]
+					}
+
+					resource "tailscale_tailnet_key" "test" {
+						tags = ["tag:new"]
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Unknown tag`),
+			},
+		},
+	})
+}
+
+func TestProvider_TailscaleTagValidationDisabled(t *testing.T) {
+	factories, server := testFakeControlProviderFactories(t)
+	device := server.AddDevice(tailscale.Device{Hostname: "web"})
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV5ProviderFactories: factories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					provider "tailscale" {
+						validate_tags = false
+					}
+
+					resource "tailscale_device_tags" "test" {
+						device_id = "` + device.NodeID + `"
+						tags      = ["tag:unknown"]
+					}`,
+			},
+		},
+	})
+}
@@ -5,21 +5,29 @@ package tailscale
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/stretchr/testify/assert"
 )
 
 type TestResponse struct {
-	Code int
-	Body interface{}
+	Code   int
+	Body   interface{}
+	Header http.Header
 }
 
 type TestServer struct {
@@ -79,11 +87,16 @@ func (t *TestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	t.Method = r.Method
 	t.Path = r.URL.Path
 
-	resp := t.HandleRequest(r.Method, t.Path)
-
 	t.Body = bytes.NewBuffer([]byte{})
 	_, err := io.Copy(t.Body, r.Body)
 	assert.NoError(t.t, err)
+
+	// The body is read first so that it is available to HandleRequest.
+	resp := t.HandleRequest(r.Method, t.Path)
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
 	w.WriteHeader(resp.Code)
 	switch body := resp.Body.(type) {
 	case []byte:
@@ -128,3 +141,123 @@ func runExpectedErrorTests(t *testing.T, testCases []expectedErrorTestCase) {
 		})
 	}
 }
+
+type functionTestCase struct {
+	name        string
+	arguments   []attr.Value
+	expected    attr.Value
+	expectError string
+}
+
+// runFunctionTests calls the Run method of a provider function with the
+// given arguments and checks the result, or the error if one is expected.
+func runFunctionTests(t *testing.T, fn function.Function, testCases []functionTestCase) {
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			var def function.DefinitionResponse
+			fn.Definition(ctx, function.DefinitionRequest{}, &def)
+
+			result, funcErr := def.Definition.Return.NewResultData(ctx)
+			if funcErr != nil {
+				t.Fatalf("unexpected error creating result data: %s", funcErr)
+			}
+
+			req := function.RunRequest{Arguments: function.NewArgumentsData(tt.arguments)}
+			resp := &function.RunResponse{Result: result}
+			fn.Run(ctx, req, resp)
+
+			if tt.expectError != "" {
+				if resp.Error == nil {
+					t.Fatalf("expected error containing %q, got none", tt.expectError)
+				}
+				assert.Contains(t, resp.Error.Error(), tt.expectError)
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if !tt.expected.Equal(resp.Result.Value()) {
+				t.Errorf("expected %s, got %s", tt.expected, resp.Result.Value())
+			}
+		})
+	}
+}
+
+type actionTestCase struct {
+	name         string
+	config       map[string]tftypes.Value
+	response     TestResponse
+	expectMethod string
+	expectPath   string
+	expectBody   string
+	expectError  string
+}
+
+// runActionTests invokes an action with each configuration against a
+// [TestServer], and checks the request that it sent. Attributes missing from
+// the configuration are null.
+func runActionTests(t *testing.T, newAction func() action.Action, testCases []actionTestCase) {
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			baseURL, server := NewTestHarness(t)
+			server.HandleRequest = func(method, path string) TestResponse {
+				return tt.response
+			}
+			u, err := url.Parse(baseURL)
+			if err != nil {
+				t.Fatalf("unexpected error parsing base URL: %s", err)
+			}
+			client := createTailscaleClient(u, "test", "-", "api_123", "", "", "", "", nil, nil)
+
+			a := newAction()
+			var configureResp action.ConfigureResponse
+			a.(action.ActionWithConfigure).Configure(ctx, action.ConfigureRequest{ProviderData: &providerData{Client: &client}}, &configureResp)
+			if configureResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error configuring action: %v", configureResp.Diagnostics)
+			}
+
+			var schemaResp action.SchemaResponse
+			a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+			objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+			for name, typ := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(typ, nil)
+				if v, ok := tt.config[name]; ok {
+					values[name] = v
+				}
+			}
+
+			req := action.InvokeRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    tftypes.NewValue(objectType, values),
+				},
+			}
+			resp := &action.InvokeResponse{SendProgress: func(action.InvokeProgressEvent) {}}
+			a.Invoke(ctx, req, resp)
+
+			assert.Equal(t, tt.expectMethod, server.Method)
+			assert.Equal(t, tt.expectPath, server.Path)
+			if tt.expectBody != "" {
+				assert.JSONEq(t, tt.expectBody, server.Body.String())
+			}
+
+			if tt.expectError != "" {
+				if !resp.Diagnostics.HasError() {
+					t.Fatalf("expected error containing %q, got none", tt.expectError)
+				}
+				assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), tt.expectError)
+				return
+			}
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
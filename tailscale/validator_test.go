@@ -40,6 +40,88 @@ func TestCIDRValidator(t *testing.T) {
 	runStringValidatorTests(t, cidrValidator{}, testCases)
 }
 
+func TestRFC3339Validator(t *testing.T) {
+	testCases := []stringValidatorTestCase{
+		{
+			name:   "valid-timestamp",
+			config: types.StringValue("2026-01-02T15:04:05Z"),
+		},
+		{
+			name:   "valid-offset",
+			config: types.StringValue("2026-01-02T15:04:05+01:00"),
+		},
+		{
+			name:    "date-only",
+			config:  types.StringValue("2026-01-02"),
+			wantErr: true,
+		},
+	}
+
+	runStringValidatorTests(t, rfc3339Validator{}, testCases)
+}
+
+func TestDNSLabelValidator(t *testing.T) {
+	testCases := []stringValidatorTestCase{
+		{
+			name:   "valid-label",
+			config: types.StringValue("db-1"),
+		},
+		{
+			name:    "uppercase",
+			config:  types.StringValue("DB"),
+			wantErr: true,
+		},
+		{
+			name:    "leading-hyphen",
+			config:  types.StringValue("-db"),
+			wantErr: true,
+		},
+		{
+			name:    "fqdn",
+			config:  types.StringValue("db.example.ts.net"),
+			wantErr: true,
+		},
+		{
+			name:    "too-long",
+			config:  types.StringValue(strings.Repeat("a", 64)),
+			wantErr: true,
+		},
+	}
+
+	runStringValidatorTests(t, dnsLabelValidator{}, testCases)
+}
+
+func TestTailscaleIPv4Validator(t *testing.T) {
+	testCases := []stringValidatorTestCase{
+		{
+			name:   "valid-address",
+			config: types.StringValue("100.100.1.1"),
+		},
+		{
+			name:    "outside-cgnat",
+			config:  types.StringValue("192.168.0.1"),
+			wantErr: true,
+		},
+		{
+			name:    "reserved",
+			config:  types.StringValue("100.100.100.100"),
+			wantErr: true,
+		},
+		{
+			name:    "tailnet-ipv6",
+			config:  types.StringValue("fd7a:115c:a1e0::1"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			config:  types.StringValue("100.64.0"),
+			wantErr: true,
+		},
+	}
+
+	runStringValidatorTests(t, tailscaleIPv4Validator{}, testCases)
+}
+
 func TestRetryDeadlineValidator(t *testing.T) {
 	testCases := []stringValidatorTestCase{
 		{
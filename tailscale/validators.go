@@ -5,8 +5,13 @@ package tailscale
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
@@ -20,6 +25,11 @@ var (
 	_ validator.String = cidrValidator{}
 	_ validator.String = retryDeadlineValidator{}
 	_ validator.String = aclHuJSONValidator{}
+	_ validator.String = aclTestsValidator{}
+	_ validator.String = jsonObjectValidator{}
+	_ validator.String = rfc3339Validator{}
+	_ validator.String = dnsLabelValidator{}
+	_ validator.String = tailscaleIPv4Validator{}
 	_ validator.List   = atLeastOneBlockRequiredListValidator{}
 	_ validator.Set    = exactlyOneBlockRequiredSetValidator{}
 )
@@ -51,6 +61,112 @@ func (v cidrValidator) ValidateString(ctx context.Context, req validator.StringR
 	}
 }
 
+// rfc3339Validator is a [validator.String] for RFC3339 timestamps.
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(_ context.Context) string {
+	return "value must be an RFC3339 timestamp"
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.ValueString(),
+		))
+	}
+}
+
+// dnsLabelRegexp matches lowercase DNS labels, as defined by RFC 1123.
+var dnsLabelRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// dnsLabelValidator is a [validator.String] for MagicDNS machine names, which
+// must be a single lowercase DNS label.
+type dnsLabelValidator struct{}
+
+func (v dnsLabelValidator) Description(_ context.Context) string {
+	return "value must be a DNS label of at most 63 lowercase letters, digits and hyphens, not starting or ending with a hyphen"
+}
+
+func (v dnsLabelValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v dnsLabelValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	if !dnsLabelRegexp.MatchString(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.ValueString(),
+		))
+	}
+}
+
+var (
+	// cgnatRange is the range from which Tailscale assigns IPv4 addresses.
+	cgnatRange = netip.MustParsePrefix("100.64.0.0/10")
+	// tailscaleULARange is the range from which Tailscale assigns IPv6
+	// addresses, which can't be changed.
+	tailscaleULARange = netip.MustParsePrefix("fd7a:115c:a1e0::/48")
+	// reservedTailscaleIPv4Ranges are the parts of cgnatRange that Tailscale
+	// doesn't assign to devices: the Tailscale service IP and ChromeOS's
+	// range.
+	reservedTailscaleIPv4Ranges = []netip.Prefix{
+		netip.MustParsePrefix("100.100.100.0/24"),
+		netip.MustParsePrefix("100.115.92.0/23"),
+	}
+)
+
+// tailscaleIPv4Validator is a [validator.String] for IPv4 addresses that
+// Tailscale can assign to a device.
+type tailscaleIPv4Validator struct{}
+
+func (v tailscaleIPv4Validator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be an IPv4 address in %s", cgnatRange)
+}
+
+func (v tailscaleIPv4Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v tailscaleIPv4Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	addr, err := netip.ParseAddr(req.ConfigValue.ValueString())
+	description := v.Description(ctx)
+	switch {
+	case err == nil && tailscaleULARange.Contains(addr):
+		description = fmt.Sprintf("value must be an IPv4 address; addresses in the tailnet's IPv6 range %s are assigned by Tailscale and can't be changed", tailscaleULARange)
+	case err == nil && addr.Is4() && cgnatRange.Contains(addr):
+		i := slices.IndexFunc(reservedTailscaleIPv4Ranges, func(p netip.Prefix) bool { return p.Contains(addr) })
+		if i < 0 {
+			return
+		}
+		description = fmt.Sprintf("value must not be in %s, which is reserved by Tailscale", reservedTailscaleIPv4Ranges[i])
+	}
+
+	resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+		req.Path,
+		description,
+		req.ConfigValue.ValueString(),
+	))
+}
+
 // retryDeadlineValdiator is a [validator.String] that checks whether a string can be
 // parsed as a duration greater than 1s.
 type retryDeadlineValidator struct{}
@@ -111,6 +227,68 @@ func (v aclHuJSONValidator) ValidateString(ctx context.Context, req validator.St
 	}
 }
 
+// aclTestsValidator is a [validator.String] that runs the tests and sshTests
+// sections of a HuJSON policy file offline, so that failing tests are reported
+// without access to the Tailscale API. Assertions that depend on the state of
+// the tailnet, such as the members of autogroup:admin, are skipped.
+type aclTestsValidator struct{}
+
+func (v aclTestsValidator) Description(_ context.Context) string {
+	return "the tests of the policy file must pass"
+}
+
+func (v aclTestsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v aclTestsValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	// Policy files that can't be parsed are reported by aclHuJSONValidator, or
+	// by the Tailscale API.
+	evaluator, err := newPolicyEvaluator(req.ConfigValue.ValueString())
+	if err != nil {
+		return
+	}
+
+	if failures := evaluator.runTests(); len(failures) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Policy file tests failed",
+			fmt.Sprintf("The following tests of the policy file failed:\n\n- %s", strings.Join(failures, "\n- ")),
+		)
+	}
+}
+
+// jsonObjectValidator is a [validator.String] that checks whether a string is
+// a JSON object.
+type jsonObjectValidator struct{}
+
+func (v jsonObjectValidator) Description(_ context.Context) string {
+	return "string must be a JSON object"
+}
+
+func (v jsonObjectValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonObjectValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(req.ConfigValue.ValueString()), &object); err != nil || object == nil {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(
+			req.Path,
+			v.Description(ctx),
+			req.ConfigValue.ValueString(),
+		))
+	}
+}
+
 // atLeastOneBlockRequiredListValidator validates that a list has a configuration
 // value. Intended for use with `schema.ListNestedBlock`.
 type atLeastOneBlockRequiredListValidator struct{}